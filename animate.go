@@ -0,0 +1,66 @@
+package pattern
+
+import (
+	"context"
+	"errors"
+	"image"
+)
+
+// Animated is implemented by patterns that change over time. SetTime moves the
+// pattern to t seconds; at time 0 it looks as it does when it is not animated.
+type Animated interface {
+	image.Image
+	SetTime(t float64)
+}
+
+var (
+	_ Animated = (*Plasma)(nil)
+	_ Animated = (*ConcentricWater)(nil)
+	_ Animated = (*VHS)(nil)
+	_ Animated = (*SpeedLines)(nil)
+	_ Animated = (*Fog)(nil)
+	_ Animated = (*HorizontalLine)(nil)
+	_ Animated = (*VerticalLine)(nil)
+)
+
+// SetChainTime sets the time of img and of every Animated image it is built from,
+// found as for SetChainBounds.
+func SetChainTime(img image.Image, t float64) {
+	walkChain(img, func(img image.Image) {
+		if a, ok := img.(Animated); ok {
+			a.SetTime(t)
+		}
+	})
+}
+
+// IsAnimated reports whether img, or any image it is built from, is Animated.
+func IsAnimated(img image.Image) bool {
+	animated := false
+	walkChain(img, func(img image.Image) {
+		_, ok := img.(Animated)
+		animated = animated || ok
+	})
+	return animated
+}
+
+// RenderFrames renders frames images of img, fps to the second, starting at time 0.
+// The time of the whole chain is set with SetChainTime before each frame is
+// rendered with Render and ops. Patterns that keep what they have drawn, such as
+// ErrorDiffusion and Buffer, go on showing the first frame, so chains holding them
+// need to be built again for each frame.
+func RenderFrames(ctx context.Context, img image.Image, frames int, fps float64, ops ...func(any)) ([]image.Image, error) {
+	if frames < 1 || fps <= 0 {
+		return nil, errors.New("an animation needs at least one frame and a positive frame rate")
+	}
+	r := NewRenderer(ops...)
+	out := make([]image.Image, 0, frames)
+	for i := 0; i < frames; i++ {
+		SetChainTime(img, float64(i)/fps)
+		frame, err := r.Render(ctx, img)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, frame)
+	}
+	return out, nil
+}
@@ -0,0 +1,92 @@
+package pattern
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// sameFrame reports whether a and b render the same within r.
+func sameFrame(a, b image.Image, r image.Rectangle) bool {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if color.RGBA64Model.Convert(a.At(x, y)) != color.RGBA64Model.Convert(b.At(x, y)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func TestAnimatedPatterns(t *testing.T) {
+	r := image.Rect(0, 0, 48, 48)
+	for name, newFn := range map[string]func(ops ...func(any)) image.Image{
+		"plasma":           func(ops ...func(any)) image.Image { return NewPlasma(append(ops, SetBounds(r))...) },
+		"concentric_water": NewConcentricWater,
+		"vhs": func(ops ...func(any)) image.Image {
+			return NewVHS(NewChecker(color.Black, color.White), ops...)
+		},
+		"speed_lines": func(ops ...func(any)) image.Image {
+			return NewSpeedLines(append(ops, SetCenter(24, 24), SetMinRadius(4), SetMaxRadius(8))...)
+		},
+		"horizontal_line": func(ops ...func(any)) image.Image {
+			return NewHorizontalLine(append(ops, SetLineSize(3), SetSpaceSize(5))...)
+		},
+		"vertical_line": func(ops ...func(any)) image.Image {
+			return NewVerticalLine(append(ops, SetLineSize(3), SetSpaceSize(5))...)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			still := newFn()
+			if !sameFrame(still, newFn(SetTime(0)), r) {
+				t.Error("Expected time 0 to look as the pattern does when not animated")
+			}
+			if sameFrame(still, newFn(SetTime(0.25)), r) {
+				t.Error("Expected the pattern to change over time")
+			}
+			if !sameFrame(newFn(SetTime(0.25)), newFn(SetTime(1.25)), r) {
+				t.Error("Expected the animation to repeat every second")
+			}
+		})
+	}
+
+	if sameFrame(NewFog(), NewFog(SetTime(0.5)), r) {
+		t.Error("Expected the fog to drift")
+	}
+}
+
+func TestSetChainTime(t *testing.T) {
+	water := NewConcentricWater().(*ConcentricWater)
+	vhs := NewVHS(water).(*VHS)
+	if !IsAnimated(vhs) || IsAnimated(NewChecker(color.Black, color.White)) {
+		t.Error("Expected only the chain with animated patterns to be animated")
+	}
+	SetChainTime(vhs, 0.5)
+	if water.Time.Time != 0.5 || vhs.Time.Time != 0.5 {
+		t.Errorf("Expected the whole chain at 0.5s, got %v and %v", water.Time.Time, vhs.Time.Time)
+	}
+}
+
+func TestRenderFrames(t *testing.T) {
+	lines := NewHorizontalLine(SetLineSize(2), SetSpaceSize(2))
+	frames, err := RenderFrames(context.Background(), lines, 4, 4, SetBounds(image.Rect(0, 0, 4, 4)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(frames) != 4 {
+		t.Fatalf("Expected 4 frames, got %d", len(frames))
+	}
+	// The lines move down one pixel a frame.
+	for i, f := range frames {
+		if _, _, _, a := f.At(0, i).RGBA(); a == 0 {
+			t.Errorf("Frame %d: expected a line at row %d", i, i)
+		}
+		if _, _, _, a := f.At(0, (i+2)%4).RGBA(); a != 0 {
+			t.Errorf("Frame %d: expected a space at row %d", i, (i+2)%4)
+		}
+	}
+	if _, err := RenderFrames(context.Background(), lines, 0, 4); err == nil {
+		t.Error("Expected an error rendering no frames")
+	}
+}
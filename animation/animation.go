@@ -0,0 +1,189 @@
+package animation
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"io"
+	"math"
+
+	"github.com/arran4/go-pattern"
+)
+
+// GIFOptions are the options for EncodeGIF.
+type GIFOptions struct {
+	// Palette holds the colours of every frame, at most 256 of them. It defaults to
+	// palette.Plan9.
+	Palette color.Palette
+	// Dither reduces a frame to the palette. It defaults to
+	// pattern.NewBayer8x8Dither: an ordered dither does not shimmer from frame to
+	// frame as error diffusion does.
+	Dither func(img image.Image, p color.Palette) image.Image
+}
+
+// EncodeGIF writes frames as an animated GIF that loops forever, showing fps frames
+// a second. GIF delays are counted in hundredths of a second, so the frame rate is
+// rounded to suit.
+func EncodeGIF(w io.Writer, frames []image.Image, fps float64, o *GIFOptions) error {
+	if err := check(frames, fps); err != nil {
+		return err
+	}
+	pal, dither := color.Palette(palette.Plan9), func(img image.Image, p color.Palette) image.Image {
+		return pattern.NewBayer8x8Dither(img, p)
+	}
+	if o != nil && o.Palette != nil {
+		pal = o.Palette
+	}
+	if o != nil && o.Dither != nil {
+		dither = o.Dither
+	}
+	if len(pal) == 0 || len(pal) > 256 {
+		return fmt.Errorf("a GIF palette holds 1 to 256 colours, not %d", len(pal))
+	}
+
+	delay := max(1, int(math.Round(100/fps)))
+	anim := &gif.GIF{}
+	// index caches the palette index of each colour the dither produces.
+	index := map[color.Color]uint8{}
+	for _, frame := range frames {
+		d := dither(frame, pal)
+		b := frame.Bounds()
+		p := image.NewPaletted(b, pal)
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				c := d.At(x, y)
+				i, ok := index[c]
+				if !ok {
+					i = uint8(pal.Index(c))
+					index[c] = i
+				}
+				p.SetColorIndex(x, y, i)
+			}
+		}
+		anim.Image = append(anim.Image, p)
+		anim.Delay = append(anim.Delay, delay)
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+// EncodeAPNG writes frames as an animated PNG that loops forever, showing fps
+// frames a second. Every frame is stored in full as 8-bit RGBA. Viewers without
+// APNG support show the first frame.
+func EncodeAPNG(w io.Writer, frames []image.Image, fps float64) error {
+	if err := check(frames, fps); err != nil {
+		return err
+	}
+	b := frames[0].Bounds()
+	// The delay is a fraction of a second with 16-bit parts.
+	num, den := uint16(1), uint16(0)
+	if fps == math.Trunc(fps) && fps <= math.MaxUint16 {
+		den = uint16(fps)
+	} else {
+		num, den = uint16(min(math.MaxUint16, math.Round(1000/fps))), 1000
+	}
+
+	e := &apngEncoder{w: w}
+	e.write([]byte("\x89PNG\r\n\x1a\n"))
+	e.chunk("IHDR", u32(uint32(b.Dx())), u32(uint32(b.Dy())), []byte{8, 6, 0, 0, 0})
+	e.chunk("acTL", u32(uint32(len(frames))), u32(0))
+	var seq uint32
+	for i, frame := range frames {
+		e.chunk("fcTL", u32(seq), u32(uint32(b.Dx())), u32(uint32(b.Dy())), u32(0), u32(0),
+			binary.BigEndian.AppendUint16(nil, num), binary.BigEndian.AppendUint16(nil, den), []byte{0, 0})
+		seq++
+		data, err := compress(frame)
+		if err != nil {
+			return err
+		}
+		if i == 0 {
+			e.chunk("IDAT", data)
+		} else {
+			e.chunk("fdAT", u32(seq), data)
+			seq++
+		}
+	}
+	e.chunk("IEND")
+	return e.err
+}
+
+// check checks that there are frames to encode, all the same size, and a frame
+// rate to show them at.
+func check(frames []image.Image, fps float64) error {
+	if len(frames) == 0 {
+		return errors.New("an animation needs at least one frame")
+	}
+	if fps <= 0 {
+		return fmt.Errorf("the frame rate must be positive, not %v", fps)
+	}
+	size := frames[0].Bounds().Size()
+	if size.X <= 0 || size.Y <= 0 {
+		return fmt.Errorf("the frames are empty: %v", frames[0].Bounds())
+	}
+	for i, f := range frames {
+		if f.Bounds().Size() != size {
+			return fmt.Errorf("frame %d is %v, not %v like the first", i, f.Bounds().Size(), size)
+		}
+	}
+	return nil
+}
+
+// compress returns the zlib compressed scanlines of img as 8-bit RGBA. Each
+// scanline uses the Sub filter, which suits the smooth gradients of most patterns.
+func compress(img image.Image) ([]byte, error) {
+	b := img.Bounds()
+	var buf bytes.Buffer
+	z := zlib.NewWriter(&buf)
+	row := make([]byte, 1+4*b.Dx())
+	row[0] = 1 // Sub
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		var prev color.NRGBA
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			i := 1 + 4*(x-b.Min.X)
+			row[i], row[i+1], row[i+2], row[i+3] = c.R-prev.R, c.G-prev.G, c.B-prev.B, c.A-prev.A
+			prev = c
+		}
+		if _, err := z.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	if err := z.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// apngEncoder writes PNG chunks, keeping the first error.
+type apngEncoder struct {
+	w   io.Writer
+	err error
+}
+
+func (e *apngEncoder) write(b []byte) {
+	if e.err == nil {
+		_, e.err = e.w.Write(b)
+	}
+}
+
+// chunk writes a chunk of the given type, its data being the parts joined.
+func (e *apngEncoder) chunk(typ string, parts ...[]byte) {
+	data := bytes.Join(parts, nil)
+	crc := crc32.NewIEEE()
+	crc.Write([]byte(typ))
+	crc.Write(data)
+	e.write(u32(uint32(len(data))))
+	e.write([]byte(typ))
+	e.write(data)
+	e.write(u32(crc.Sum32()))
+}
+
+func u32(v uint32) []byte {
+	return binary.BigEndian.AppendUint32(nil, v)
+}
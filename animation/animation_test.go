@@ -0,0 +1,168 @@
+package animation
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"testing"
+)
+
+func frames(n int) []image.Image {
+	var out []image.Image
+	for i := 0; i < n; i++ {
+		img := image.NewNRGBA(image.Rect(0, 0, 5, 3))
+		for y := 0; y < 3; y++ {
+			for x := 0; x < 5; x++ {
+				img.Set(x, y, color.NRGBA{uint8(40 * i), uint8(50 * x), uint8(80 * y), uint8(255 - 20*x)})
+			}
+		}
+		out = append(out, img)
+	}
+	return out
+}
+
+type chunk struct {
+	typ  string
+	data []byte
+}
+
+func readChunks(t *testing.T, b []byte) []chunk {
+	t.Helper()
+	if !bytes.HasPrefix(b, []byte("\x89PNG\r\n\x1a\n")) {
+		t.Fatal("Expected the PNG signature")
+	}
+	b = b[8:]
+	var chunks []chunk
+	for len(b) > 0 {
+		n := binary.BigEndian.Uint32(b)
+		c := chunk{string(b[4:8]), b[8 : 8+n]}
+		if crc := binary.BigEndian.Uint32(b[8+n:]); crc != crc32.ChecksumIEEE(b[4:8+n]) {
+			t.Errorf("Bad CRC on %s", c.typ)
+		}
+		chunks = append(chunks, c)
+		b = b[12+n:]
+	}
+	return chunks
+}
+
+func TestEncodeAPNG(t *testing.T) {
+	src := frames(3)
+	var buf bytes.Buffer
+	if err := EncodeAPNG(&buf, src, 25); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := png.Decode(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatalf("Expected plain PNG decoders to read the first frame: %v", err)
+	}
+
+	chunks := readChunks(t, buf.Bytes())
+	var ihdr []byte
+	var got []image.Image
+	seq := uint32(0)
+	for _, c := range chunks {
+		switch c.typ {
+		case "IHDR":
+			ihdr = c.data
+		case "acTL":
+			if n := binary.BigEndian.Uint32(c.data); n != 3 {
+				t.Errorf("Expected 3 frames, got %d", n)
+			}
+		case "fcTL", "fdAT":
+			if s := binary.BigEndian.Uint32(c.data); s != seq {
+				t.Errorf("Expected sequence number %d, got %d", seq, s)
+			}
+			seq++
+			if c.typ == "fcTL" {
+				if num, den := binary.BigEndian.Uint16(c.data[20:]), binary.BigEndian.Uint16(c.data[22:]); num != 1 || den != 25 {
+					t.Errorf("Expected a delay of 1/25, got %d/%d", num, den)
+				}
+				continue
+			}
+			got = append(got, decodeFrame(t, ihdr, c.data[4:]))
+		case "IDAT":
+			got = append(got, decodeFrame(t, ihdr, c.data))
+		}
+	}
+	if len(got) != len(src) {
+		t.Fatalf("Expected %d frames, got %d", len(src), len(got))
+	}
+	for i := range src {
+		for y := 0; y < 3; y++ {
+			for x := 0; x < 5; x++ {
+				want := color.NRGBAModel.Convert(src[i].At(x, y))
+				if c := color.NRGBAModel.Convert(got[i].At(x, y)); c != want {
+					t.Errorf("Frame %d at %d,%d: expected %v, got %v", i, x, y, want, c)
+				}
+			}
+		}
+	}
+}
+
+// decodeFrame decodes the image data of a frame as a PNG of its own.
+func decodeFrame(t *testing.T, ihdr, data []byte) image.Image {
+	t.Helper()
+	e := &apngEncoder{w: &bytes.Buffer{}}
+	e.write([]byte("\x89PNG\r\n\x1a\n"))
+	e.chunk("IHDR", ihdr)
+	e.chunk("IDAT", data)
+	e.chunk("IEND")
+	img, err := png.Decode(e.w.(*bytes.Buffer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return img
+}
+
+func TestEncodeGIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := EncodeGIF(&buf, frames(4), 20, nil); err != nil {
+		t.Fatal(err)
+	}
+	g, err := gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Image) != 4 || g.Delay[0] != 5 || g.LoopCount != 0 {
+		t.Errorf("Expected 4 frames of 5/100s looping forever, got %d frames, delay %d, loop count %d", len(g.Image), g.Delay[0], g.LoopCount)
+	}
+
+	bw := color.Palette{color.Black, color.White}
+	solid := image.NewUniform(color.White)
+	buf.Reset()
+	err = EncodeGIF(&buf, []image.Image{&bounded{solid, image.Rect(0, 0, 4, 4)}}, 10, &GIFOptions{Palette: bw})
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err = gif.DecodeAll(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := color.GrayModel.Convert(g.Image[0].At(2, 2)); c != (color.Gray{255}) {
+		t.Errorf("Expected white, got %v", c)
+	}
+}
+
+type bounded struct {
+	image.Image
+	r image.Rectangle
+}
+
+func (b *bounded) Bounds() image.Rectangle { return b.r }
+
+func TestCheck(t *testing.T) {
+	mixed := append(frames(1), image.Image(image.NewRGBA(image.Rect(0, 0, 2, 2))))
+	for name, err := range map[string]error{
+		"none":    EncodeAPNG(&bytes.Buffer{}, nil, 10),
+		"fps":     EncodeGIF(&bytes.Buffer{}, frames(1), 0, nil),
+		"mixed":   EncodeAPNG(&bytes.Buffer{}, mixed, 10),
+		"palette": EncodeGIF(&bytes.Buffer{}, frames(1), 10, &GIFOptions{Palette: make(color.Palette, 300)}),
+	} {
+		if err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -121,3 +121,29 @@ func NewBlend(i1, i2 image.Image, mode BlendMode, ops ...func(any)) image.Image
 	}
 	return p
 }
+
+// blendModeNames names the BlendMode values in the pattern registry.
+var blendModeNames = []string{"add", "multiply", "average", "screen", "overlay", "normal"}
+
+func init() {
+	RegisterPattern(&PatternType{
+		Name:     "blend",
+		Category: CategoryCompositor,
+		Inputs:   []Input{{Name: "background"}, {Name: "foreground"}},
+		Params: []Param{
+			{Name: "mode", Type: ParamEnum, Default: "normal", Values: blendModeNames, Doc: "How the foreground is combined with the background."},
+		},
+		Sample: &Blend{},
+		New: func(a *Args) (image.Image, error) {
+			mode := BlendMode(a.Enum("mode", blendModeNames))
+			return NewBlend(a.Input("background"), a.Input("foreground"), mode, a.Options...), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			p := img.(*Blend)
+			a.SetInput("background", p.Image1)
+			a.SetInput("foreground", p.Image2)
+			a.Set("mode", enumName(blendModeNames, int(p.Mode)))
+			return nil
+		},
+	})
+}
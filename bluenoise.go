@@ -3,33 +3,26 @@ package pattern
 import (
 	"image"
 	"image/color"
-	"math/rand"
+	"math"
 	"sync"
 )
 
 // Ensure BlueNoise implements the image.Image interface.
 var _ image.Image = (*BlueNoise)(nil)
 
-// BlueNoise generates a blue noise texture approximation.
-// We use Mitchell's Best-Candidate Algorithm to generate points,
-// then rasterize them with a slight Gaussian blur (conceptually) or return high-frequency noise.
-//
-// Blue noise is characterized by minimal low-frequency components and no spectral peaks.
-//
-// For this implementation, we will generate a set of points that are well-separated (Poisson Disk-like)
-// and then return a value based on the distance to the nearest point, or simply
-// fill pixels.
-//
-// A better "Blue Noise Mask" for dithering is usually a dense array of values 0-255
-// arranged such that thresholding at any level produces a blue noise distribution.
-// Generating such a mask (e.g. via Void-and-Cluster) is expensive (O(N^2) or O(N^3)).
-//
-// We will implement a simplified generator that tries to maintain separation.
+// BlueNoise draws a blue noise mask: a grey texture with no low frequencies and no
+// spectral peaks, whose pixels below any grey level are spread evenly without
+// clumps. It is the void-and-cluster mask of BlueNoiseMask, Size pixels square and
+// 64 by default, tiled from the origin, so it makes a good threshold map for
+// dithering.
 type BlueNoise struct {
 	Null
 	Seed int64
+	Size int
+	// Values holds the mask as 8-bit greys, Size by Size, once it is drawn. The
+	// mask is kept, so changes to Seed or Size after that have no effect.
 	Values []uint8
-	once      sync.Once
+	once   sync.Once
 }
 
 func (p *BlueNoise) SetSeed(v int64) {
@@ -40,67 +33,45 @@ func (p *BlueNoise) SetSeedUint64(v uint64) {
 	p.Seed = int64(v)
 }
 
+// size returns the size of the mask, within the sizes BlueNoiseMask generates.
+func (p *BlueNoise) size() int {
+	switch {
+	case p.Size == 0:
+		return 64
+	case p.Size < MinBlueNoiseSize:
+		return MinBlueNoiseSize
+	case p.Size > MaxBlueNoiseSize:
+		return MaxBlueNoiseSize
+	}
+	return p.Size
+}
+
 func (p *BlueNoise) generate() {
 	p.once.Do(func() {
-		w, h := p.bounds.Dx(), p.bounds.Dy()
-		p.Values = make([]uint8, w*h)
-
-		// Initialize with random noise
-		rnd := rand.New(rand.NewSource(p.Seed))
-		for i := range p.Values {
-			p.Values[i] = uint8(rnd.Intn(256))
-		}
-
-		// Apply a simple high-pass filter to remove low frequencies (clumps)
-		// Blue noise = White Noise - Low Pass Filtered White Noise.
-		// We can simulate this by subtracting a blurred version from the original.
-
-		temp := make([]int, w*h)
-
-		// 3x3 Box Blur (Low Pass)
-		for y := 0; y < h; y++ {
-			for x := 0; x < w; x++ {
-				sum := 0
-				count := 0
-				for ky := -1; ky <= 1; ky++ {
-					for kx := -1; kx <= 1; kx++ {
-						px := x + kx
-						py := y + ky
-						// Wrap
-						if px < 0 { px += w }
-						if px >= w { px -= w }
-						if py < 0 { py += h }
-						if py >= h { py -= h }
-
-						sum += int(p.Values[py*w + px])
-						count++
-					}
-				}
-				avg := sum / count
-				// High Pass = Original - Low Pass + 128 (to center)
-				val := int(p.Values[y*w + x]) - avg + 128
-				if val < 0 { val = 0 }
-				if val > 255 { val = 255 }
-				temp[y*w + x] = val
-			}
-		}
-
-		for i := range p.Values {
-			p.Values[i] = uint8(temp[i])
+		mask := BlueNoiseMask(p.size(), p.Seed)
+		p.Values = make([]uint8, len(mask))
+		for i, rank := range mask {
+			// Ranks are spread evenly over the greys.
+			p.Values[i] = uint8(int(rank) * 256 / len(mask))
 		}
 	})
 }
 
 func (p *BlueNoise) At(x, y int) color.Color {
 	p.generate()
-	w := p.bounds.Dx()
-	h := p.bounds.Dy()
+	// The mask keeps the size it was drawn at.
+	size := int(math.Sqrt(float64(len(p.Values))))
 
-	gx := x % w; if gx < 0 { gx += w }
-	gy := y % h; if gy < 0 { gy += h }
+	gx := x % size
+	if gx < 0 {
+		gx += size
+	}
+	gy := y % size
+	if gy < 0 {
+		gy += size
+	}
 
-	v := p.Values[gy*w + gx]
-	return color.Gray{Y: v}
+	return color.Gray{Y: p.Values[gy*size+gx]}
 }
 
 // NewBlueNoise creates a new BlueNoise pattern.
@@ -110,9 +81,39 @@ func NewBlueNoise(ops ...func(any)) image.Image {
 			bounds: image.Rect(0, 0, 64, 64), // Default size
 		},
 		Seed: 1, // Default seed
+		Size: 64,
 	}
 	for _, op := range ops {
 		op(p)
 	}
 	return p
 }
+
+// SetBlueNoiseSize sets the size of the mask a BlueNoise tiles.
+func SetBlueNoiseSize(size int) func(any) {
+	return func(i any) {
+		if p, ok := i.(*BlueNoise); ok {
+			p.Size = size
+		}
+	}
+}
+
+func init() {
+	RegisterPattern(&PatternType{
+		Name:     "blue_noise",
+		Category: CategoryGenerator,
+		Params: []Param{
+			{Name: "size", Type: ParamInt, Default: 64, Min: MinBlueNoiseSize, Max: MaxBlueNoiseSize, Doc: "Size of the tiled mask."},
+		},
+		Sample: &BlueNoise{},
+		New: func(a *Args) (image.Image, error) {
+			p := NewBlueNoise().(*BlueNoise)
+			p.Size = a.Int("size")
+			return applyOps(p, a.Options), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			a.Set("size", img.(*BlueNoise).size())
+			return nil
+		},
+	})
+}
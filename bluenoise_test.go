@@ -0,0 +1,143 @@
+package pattern
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestBlueNoiseMask(t *testing.T) {
+	for _, size := range []int{16, 24, 64} {
+		mask := BlueNoiseMask(size, 1)
+		if len(mask) != size*size {
+			t.Fatalf("size %d: mask holds %d ranks", size, len(mask))
+		}
+		seen := make([]bool, len(mask))
+		for _, r := range mask {
+			if int(r) >= len(mask) || seen[r] {
+				t.Fatalf("size %d: ranks are not a permutation", size)
+			}
+			seen[r] = true
+		}
+		if !reflect.DeepEqual(mask, voidAndCluster(size, 1)) {
+			t.Errorf("size %d: mask differs when generated again", size)
+		}
+		if reflect.DeepEqual(mask, BlueNoiseMask(size, 2)) {
+			t.Errorf("size %d: mask is the same for another seed", size)
+		}
+	}
+	if got := len(BlueNoiseMask(4, 1)); got != 16*16 {
+		t.Errorf("size 4 gave %d ranks, want a 16x16 mask", got)
+	}
+}
+
+func TestBlueNoiseMaskEven(t *testing.T) {
+	// At every threshold the pixels below it are spread evenly: each 8x8 block of
+	// the mask holds close to its share, where white noise strays twice as far.
+	const size = 64
+	mask := BlueNoiseMask(size, 1)
+	for _, level := range []int{size * size / 10, size * size / 4, size * size / 2, size * size * 9 / 10} {
+		want := float64(level) / (size * size) * 64
+		for by := 0; by < size; by += 8 {
+			for bx := 0; bx < size; bx += 8 {
+				n := 0
+				for y := by; y < by+8; y++ {
+					for x := bx; x < bx+8; x++ {
+						if int(mask[y*size+x]) < level {
+							n++
+						}
+					}
+				}
+				if d := float64(n) - want; d > 3 || d < -3 {
+					t.Fatalf("level %d: block (%d, %d) holds %d pixels, want about %v", level, bx, by, n, want)
+				}
+			}
+		}
+	}
+
+	// The first tenth are well apart, around the wrap too.
+	var points []image.Point
+	for i, r := range mask {
+		if int(r) < size*size/10 {
+			points = append(points, image.Point{i % size, i / size})
+		}
+	}
+	for i, p := range points {
+		for _, q := range points[i+1:] {
+			dx, dy := (p.X-q.X+size)%size, (p.Y-q.Y+size)%size
+			if dx > size/2 {
+				dx = size - dx
+			}
+			if dy > size/2 {
+				dy = size - dy
+			}
+			if dx*dx+dy*dy < 4 {
+				t.Fatalf("%v and %v of the first tenth are neighbours", p, q)
+			}
+		}
+	}
+}
+
+func TestBlueNoiseMaskCache(t *testing.T) {
+	dir := t.TempDir()
+	defer func(old string) { BlueNoiseCacheDir = old }(BlueNoiseCacheDir)
+	BlueNoiseCacheDir = dir
+
+	// A seed of its own keeps the mask out of the memory cache.
+	mask := BlueNoiseMask(16, -7357)
+	path := filepath.Join(dir, "bluenoise-16--7357.bin")
+	loaded, err := readBlueNoiseMask(path, 16)
+	if err != nil {
+		t.Fatalf("reading the cached mask: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, mask) {
+		t.Error("cached mask differs")
+	}
+
+	if err := os.WriteFile(path, make([]byte, 2*16*16), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readBlueNoiseMask(path, 16); err == nil {
+		t.Error("reading a mask of zeros succeeded")
+	}
+	if _, err := readBlueNoiseMask(path, 32); err == nil {
+		t.Error("reading a mask of the wrong size succeeded")
+	}
+}
+
+func TestBlueNoise(t *testing.T) {
+	p := NewBlueNoise(SetBlueNoiseSize(32), SetSeed(3))
+	mask := BlueNoiseMask(32, 3)
+	for _, pt := range []image.Point{{0, 0}, {5, 9}, {31, 31}} {
+		want := color.Gray{Y: uint8(int(mask[pt.Y*32+pt.X]) * 256 / 1024)}
+		for _, o := range []image.Point{{0, 0}, {32, 0}, {-32, 64}} {
+			if got := p.At(pt.X+o.X, pt.Y+o.Y); got != want {
+				t.Errorf("At(%v) = %v, want %v", pt.Add(o), got, want)
+			}
+		}
+	}
+
+	// The mask keeps the size it was drawn at.
+	p.(*BlueNoise).Size = 64
+	if got, want := p.At(40, 40), p.At(8, 8); got != want {
+		t.Errorf("At(40, 40) = %v after growing Size, want the 32 pixel tile's %v", got, want)
+	}
+
+	// A mid grey dithers to half white.
+	grey := NewRect(SetBounds(image.Rect(0, 0, 64, 64)), SetFillColor(color.Gray{Y: 128}))
+	d := NewBlueNoiseDither(grey, nil)
+	white := 0
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			if r, _, _, _ := d.At(x, y).RGBA(); r > 0x8000 {
+				white++
+			}
+		}
+	}
+	if white < 1900 || white > 2200 {
+		t.Errorf("%d of 4096 pixels of mid grey are white, want about half", white)
+	}
+}
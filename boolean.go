@@ -779,3 +779,79 @@ func NewDemoNot(ops ...func(any)) image.Image {
 	h := NewHorizontalLine(SetLineSize(20), SetSpaceSize(20), SetLineColor(color.Black))
 	return NewNot(h, ops...)
 }
+
+// booleanModeNames names the BooleanMode values in the pattern registry.
+var booleanModeNames = []string{"auto", "fuzzy", "threshold", "component-wise", "bitwise"}
+
+// booleanOpNames names the BooleanOpType values, as the registered operators are named.
+var booleanOpNames = []string{"and", "or", "xor", "not", "bitwise_and", "bitwise_or", "bitwise_xor", "bitwise_not"}
+
+// booleanType describes one of the boolean operators. Operators share their concrete
+// types, so Match tells them apart by Op.
+func booleanType(name string, sample image.Image, op BooleanOpType, in Input, newFn func(inputs []image.Image) image.Image) *PatternType {
+	return &PatternType{
+		Name:     name,
+		Category: CategoryCompositor,
+		Inputs:   []Input{in},
+		Params: []Param{
+			{Name: "mode", Type: ParamEnum, Default: "auto", Values: booleanModeNames, Doc: "How input colours are combined."},
+			{Name: "threshold", Type: ParamFloat, Default: 0.0, Min: 0, Max: 1, Doc: "Intensity above which an input is true."},
+		},
+		Sample: sample,
+		Match: func(img image.Image) bool {
+			return booleanImageOf(img).Op == op
+		},
+		New: func(a *Args) (image.Image, error) {
+			p := newFn(a.InputList(in.Name))
+			bi := booleanImageOf(p)
+			bi.Mode = BooleanMode(a.Enum("mode", booleanModeNames))
+			bi.Threshold = a.Float("threshold")
+			return applyOps(p, a.Options), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			bi := booleanImageOf(img)
+			a.SetInput(in.Name, bi.Inputs...)
+			a.Set("mode", enumName(booleanModeNames, int(bi.Mode)))
+			a.Set("threshold", bi.Threshold)
+			return nil
+		},
+	}
+}
+
+func booleanImageOf(img image.Image) *BooleanImage {
+	switch p := img.(type) {
+	case *And:
+		return &p.BooleanImage
+	case *Or:
+		return &p.BooleanImage
+	case *Xor:
+		return &p.BooleanImage
+	case *Not:
+		return &p.BooleanImage
+	}
+	return &BooleanImage{}
+}
+
+func init() {
+	inputs := Input{Name: "inputs", Variadic: true}
+	single := Input{Name: "inputs"}
+	not := func(newFn func(image.Image, ...func(any)) image.Image) func([]image.Image) image.Image {
+		return func(inputs []image.Image) image.Image { return newFn(inputs[0]) }
+	}
+	for _, t := range []*PatternType{
+		booleanType("and", &And{}, OpAnd, inputs, func(in []image.Image) image.Image { return NewAnd(in) }),
+		booleanType("or", &Or{}, OpOr, inputs, func(in []image.Image) image.Image { return NewOr(in) }),
+		booleanType("xor", &Xor{}, OpXor, inputs, func(in []image.Image) image.Image { return NewXor(in) }),
+		booleanType("not", &Not{}, OpNot, single, not(NewNot)),
+		booleanType("bitwise_and", &And{}, OpBitwiseAnd, inputs, func(in []image.Image) image.Image { return NewBitwiseAnd(in) }),
+		booleanType("bitwise_or", &Or{}, OpBitwiseOr, inputs, func(in []image.Image) image.Image { return NewBitwiseOr(in) }),
+		booleanType("bitwise_xor", &Xor{}, OpBitwiseXor, inputs, func(in []image.Image) image.Image { return NewBitwiseXor(in) }),
+		booleanType("bitwise_not", &Not{}, OpBitwiseNot, single, not(NewBitwiseNot)),
+	} {
+		RegisterPattern(t)
+	}
+	RegisterPattern(generatorType("demo_and", nil, NewDemoAnd))
+	RegisterPattern(generatorType("demo_or", nil, NewDemoOr))
+	RegisterPattern(generatorType("demo_xor", nil, NewDemoXor))
+	RegisterPattern(generatorType("demo_not", nil, NewDemoNot))
+}
@@ -6,8 +6,9 @@ import (
 	"math"
 )
 
-// Ensure Brick implements image.Image
+// Ensure Brick implements image.Image and Sampler
 var _ image.Image = (*Brick)(nil)
+var _ Sampler = (*Brick)(nil)
 
 // Brick is a pattern that simulates a brick wall with running bond layout.
 // It supports configurable brick size, mortar size, row offset, and multiple brick textures.
@@ -22,6 +23,15 @@ type Brick struct {
 }
 
 func (b *Brick) At(x, y int) color.Color {
+	return b.SampleAt(float64(x)+0.5, float64(y)+0.5)
+}
+
+// SampleAt returns the colour at the continuous position (x, y).
+func (b *Brick) SampleAt(px, py float64) color.Color {
+	// Work from the pixel's top-left corner, which is what At historically sampled.
+	x := px - 0.5
+	y := py - 0.5
+
 	// Defaults
 	width := b.Width
 	if width <= 0 {
@@ -42,10 +52,10 @@ func (b *Brick) At(x, y int) color.Color {
 
 	// Calculate row index
 	// Handle negative coordinates correctly
-	row := int(math.Floor(float64(y) / float64(cellH)))
+	row := int(math.Floor(y / float64(cellH)))
 
-	// Calculate local Y within the cell (0 to cellH-1)
-	localY := (y%cellH + cellH) % cellH
+	// Calculate local Y within the cell (0 to cellH)
+	localY := y - float64(row*cellH)
 
 	// Determine row offset
 	xOffset := 0.0
@@ -57,30 +67,29 @@ func (b *Brick) At(x, y int) color.Color {
 	}
 
 	// Adjust x by offset
-	effX := float64(x) - xOffset
+	effX := x - xOffset
 	col := int(math.Floor(effX / float64(cellW)))
-	floorEffX := int(math.Floor(effX))
-	localX := (floorEffX%cellW + cellW) % cellW
+	localX := effX - float64(col*cellW)
 
 	// Determine if we are in mortar or brick
 	// Center the brick in the cell
 	// Mortar is split: half on left/top, half on right/bottom
-	mortarHalf := mortar / 2
+	mortarHalf := float64(mortar / 2)
 	// If mortar is odd, the extra pixel goes to the end (right/bottom)
 	// range: [mortarHalf, mortarHalf + width) is brick
 
 	inMortar := false
-	if localX < mortarHalf || localX >= mortarHalf+width {
+	if localX < mortarHalf || localX >= mortarHalf+float64(width) {
 		inMortar = true
 	}
-	if localY < mortarHalf || localY >= mortarHalf+height {
+	if localY < mortarHalf || localY >= mortarHalf+float64(height) {
 		inMortar = true
 	}
 
 	if inMortar {
 		if b.MortarImage != nil {
 			// Sample mortar in world space
-			return b.MortarImage.At(x, y)
+			return sampleImage(b.MortarImage, px, py)
 		}
 		// Default mortar color
 		return color.RGBA{200, 200, 200, 255}
@@ -107,13 +116,12 @@ func (b *Brick) At(x, y int) color.Color {
 	}
 
 	// Calculate coordinates inside the brick (0 to width-1, 0 to height-1)
-	bx := localX - mortarHalf
-	by := localY - mortarHalf
+	bx := int(math.Floor(localX - mortarHalf))
+	by := int(math.Floor(localY - mortarHalf))
 
 	// Map to image coordinates
 	// We want to "stamp" the image onto the brick.
 	// We assume the image provided is the texture for the brick face.
-	// We should tile it or clamp it?
 	// Tiling (Tile pattern logic) is safest if the texture is smaller/larger.
 	ib := img.Bounds()
 	iw, ih := ib.Dx(), ib.Dy()
@@ -198,3 +206,45 @@ func (b *Brick) SetMortarSize(s int)   { b.MortarSize = s }
 func (b *Brick) SetBrickOffset(o float64) { b.Offset = o }
 func (b *Brick) SetBrickImages(imgs []image.Image) { b.BrickImages = imgs }
 func (b *Brick) SetMortarImage(img image.Image) { b.MortarImage = img }
+
+func init() {
+	RegisterPattern(&PatternType{
+		Name:     "brick",
+		Category: CategoryGenerator,
+		Inputs: []Input{
+			{Name: "bricks", Optional: true, Variadic: true},
+			{Name: "mortar", Optional: true},
+		},
+		Params: []Param{
+			{Name: "width", Type: ParamInt, Default: 40, Min: 1, Max: 4096, Doc: "Width of a brick."},
+			{Name: "height", Type: ParamInt, Default: 20, Min: 1, Max: 4096, Doc: "Height of a brick."},
+			{Name: "mortar_size", Type: ParamInt, Default: 4, Min: 0, Max: 4096, Doc: "Width of the mortar between bricks."},
+			{Name: "offset", Type: ParamFloat, Default: 0.5, Min: 0, Max: 1, Doc: "Offset of alternate rows, as a fraction of the brick width."},
+		},
+		Sample: &Brick{},
+		New: func(a *Args) (image.Image, error) {
+			p := NewBrick().(*Brick)
+			p.Width = a.Int("width")
+			p.Height = a.Int("height")
+			p.MortarSize = a.Int("mortar_size")
+			p.Offset = a.Float("offset")
+			if bricks := a.InputList("bricks"); bricks != nil {
+				p.BrickImages = bricks
+			}
+			if mortar := a.Input("mortar"); mortar != nil {
+				p.MortarImage = mortar
+			}
+			return applyOps(p, a.Options), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			p := img.(*Brick)
+			a.SetInput("bricks", p.BrickImages...)
+			a.SetInput("mortar", p.MortarImage)
+			a.Set("width", p.Width)
+			a.Set("height", p.Height)
+			a.Set("mortar_size", p.MortarSize)
+			a.Set("offset", p.Offset)
+			return nil
+		},
+	})
+}
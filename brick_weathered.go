@@ -224,3 +224,7 @@ func clampColor(v float64) uint8 {
 func lerpBrick(a, b, t float64) float64 {
 	return a + (b-a)*t
 }
+
+func init() {
+	RegisterPattern(generatorType("chipped_brick", &ChippedBrick{}, NewChippedBrick))
+}
@@ -1,9 +1,9 @@
 package pattern
 
 import (
+	"context"
 	"image"
 	"image/color"
-	"image/draw"
 	"sync"
 	"time"
 )
@@ -59,18 +59,26 @@ func (b *Buffer) SetDirty() {
 
 // Refresh updates the cached image from the source.
 func (b *Buffer) Refresh() {
+	// A background context is never cancelled, so rendering cannot fail.
+	_ = b.RefreshContext(context.Background())
+}
+
+// RefreshContext updates the cached image from the source, rendering it in parallel tiles.
+// If ctx is cancelled the previous cache is kept and the error is returned.
+func (b *Buffer) RefreshContext(ctx context.Context) error {
 	b.mutex.Lock()
 	defer b.mutex.Unlock()
 
-	bounds := b.Bounds()
-	dst := image.NewRGBA(bounds)
 	// If the source has different bounds, we might want to respect that,
 	// but usually a pattern fills the target.
-	// We draw source into dst.
-	draw.Draw(dst, bounds, b.Source, bounds.Min, draw.Src)
+	dst, err := Render(ctx, b.Source, SetBounds(b.Bounds()))
+	if err != nil {
+		return err
+	}
 	b.Cached = dst
 	b.LastRefresh = time.Now()
 	b.dirty = false
+	return nil
 }
 
 // At returns the color of the pixel at (x, y).
@@ -120,3 +128,11 @@ func NewBuffer(source image.Image, ops ...func(any)) *Buffer {
 	}
 	return b
 }
+
+func init() {
+	RegisterPattern(filterType("buffer", &Buffer{}, func(img image.Image, ops ...func(any)) image.Image {
+		return NewBuffer(img, ops...)
+	}, func(img image.Image) image.Image {
+		return img.(*Buffer).Source
+	}))
+}
@@ -3,15 +3,18 @@ package pattern
 import (
 	"image"
 	"image/color"
+	"math"
 )
 
-// Ensure Checker implements the image.Image interface.
+// Ensure Checker implements the image.Image and Sampler interfaces.
 var _ image.Image = (*Checker)(nil)
+var _ Sampler = (*Checker)(nil)
 
 // Checker is a pattern that alternates between two colors in a checkerboard fashion.
 type Checker struct {
 	Null
 	SpaceSize
+	AntiAlias
 	color1, color2 color.Color
 }
 
@@ -24,6 +27,9 @@ func (c *Checker) Bounds() image.Rectangle {
 }
 
 func (c *Checker) At(x, y int) color.Color {
+	if c.AntiAlias.AntiAlias {
+		return c.SampleAt(float64(x)+0.5, float64(y)+0.5)
+	}
 	size := c.SpaceSize.SpaceSize
 	if size <= 0 {
 		size = 1
@@ -44,6 +50,30 @@ func (c *Checker) At(x, y int) color.Color {
 	return c.color2
 }
 
+// SampleAt returns the colour at the continuous position (x, y).
+// With AntiAlias set, it returns the exact average over the one pixel square centred on (x, y).
+func (c *Checker) SampleAt(x, y float64) color.Color {
+	size := float64(c.SpaceSize.SpaceSize)
+	if size <= 0 {
+		size = 1
+	}
+
+	if c.AntiAlias.AntiAlias {
+		// The checker is the XOR of two square waves, so its box average is separable.
+		ax := bandCoverage(x-0.5, x+0.5, size, 2*size)
+		ay := bandCoverage(y-0.5, y+0.5, size, 2*size)
+		w1 := ax*ay + (1-ax)*(1-ay)
+		return mixCoverage([]color.Color{c.color1, c.color2}, []float64{w1, 1 - w1})
+	}
+
+	cx := int(math.Floor(x / size))
+	cy := int(math.Floor(y / size))
+	if (cx+cy)%2 == 0 {
+		return c.color1
+	}
+	return c.color2
+}
+
 // NewChecker creates a new Checker with the given colors and square size.
 func NewChecker(color1, color2 color.Color, ops ...func(any)) image.Image {
 	p := &Checker{
@@ -66,3 +96,25 @@ func NewChecker(color1, color2 color.Color, ops ...func(any)) image.Image {
 func NewDemoChecker(ops ...func(any)) image.Image {
 	return NewChecker(color.Black, color.White, ops...)
 }
+
+func init() {
+	RegisterPattern(&PatternType{
+		Name:     "checker",
+		Category: CategoryGenerator,
+		Params: []Param{
+			{Name: "color1", Type: ParamColor, Default: color.Black, Doc: "Colour of the first squares."},
+			{Name: "color2", Type: ParamColor, Default: color.White, Doc: "Colour of the alternate squares."},
+		},
+		Sample: &Checker{},
+		New: func(a *Args) (image.Image, error) {
+			return NewChecker(a.Color("color1"), a.Color("color2"), a.Options...), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			p := img.(*Checker)
+			a.Set("color1", p.color1)
+			a.Set("color2", p.color2)
+			return nil
+		},
+	})
+	RegisterPattern(generatorType("demo_checker", nil, NewDemoChecker))
+}
@@ -74,3 +74,8 @@ func NewChunkyBands(ops ...func(any)) image.Image {
 func NewDemoChunkyBands(ops ...func(any)) image.Image {
 	return NewChunkyBands(ops...)
 }
+
+func init() {
+	RegisterPattern(generatorType("chunky_bands", &ChunkyBands{}, NewChunkyBands))
+	RegisterPattern(generatorType("demo_chunky_bands", nil, NewDemoChunkyBands))
+}
@@ -3,10 +3,12 @@ package pattern
 import (
 	"image"
 	"image/color"
+	"math"
 )
 
-// Ensure Circle implements the image.Image interface.
+// Ensure Circle implements the image.Image and Sampler interfaces.
 var _ image.Image = (*Circle)(nil)
+var _ Sampler = (*Circle)(nil)
 
 // Circle is a pattern that draws a circle fitting within its bounds.
 // It supports a border (LineSize, LineColor, LineImageSource) and a fill (FillColor, FillImageSource).
@@ -18,9 +20,13 @@ type Circle struct {
 	FillColor
 	FillImageSource
 	SpaceColor
+	AntiAlias
 }
 
 func (p *Circle) At(x, y int) color.Color {
+	if p.AntiAlias.AntiAlias {
+		return p.SampleAt(float64(x)+0.5, float64(y)+0.5)
+	}
 	b := p.Bounds()
 	width := b.Dx()
 	height := b.Dy()
@@ -104,6 +110,87 @@ func (p *Circle) At(x, y int) color.Color {
 	return p.LineColor.LineColor
 }
 
+// SampleAt returns the colour at the continuous position (x, y).
+// With AntiAlias set, the edges of the circle and its border are blended by coverage.
+func (p *Circle) SampleAt(x, y float64) color.Color {
+	b := p.Bounds()
+	cx := float64(b.Min.X+b.Max.X) / 2
+	cy := float64(b.Min.Y+b.Max.Y) / 2
+	dist := math.Hypot(x-cx, y-cy)
+
+	diameter := b.Dx()
+	if b.Dy() < diameter {
+		diameter = b.Dy()
+	}
+	outer := float64(diameter) / 2
+
+	var space color.Color = color.RGBA{}
+	if p.SpaceColor.SpaceColor != nil {
+		space = p.SpaceColor.SpaceColor
+	}
+
+	// Coverage of the outer circle and, when there is a border, the inner fill.
+	outerCov, innerCov := 0.0, 0.0
+	ls := p.LineSize.LineSize
+	inner := outer
+	if ls > 0 {
+		inner = math.Max(float64(diameter-2*ls), 0) / 2
+	}
+	if p.AntiAlias.AntiAlias {
+		outerCov = edgeCoverage(outer - dist)
+		innerCov = edgeCoverage(inner - dist)
+	} else {
+		if dist <= outer {
+			outerCov = 1
+		}
+		if dist <= inner {
+			innerCov = 1
+		}
+	}
+	if outerCov == 0 {
+		return space
+	}
+
+	var line, fill color.Color
+	if ls > 0 {
+		line = p.LineColor.LineColor
+		if p.LineImageSource.LineImageSource != nil {
+			line = sampleImage(p.LineImageSource.LineImageSource, x, y)
+		}
+		if p.FillImageSource.FillImageSource != nil {
+			fill = sampleImage(p.FillImageSource.FillImageSource, x, y)
+		} else if p.FillColor.FillColor != nil {
+			fill = p.FillColor.FillColor
+		} else {
+			fill = color.RGBA{}
+		}
+	} else {
+		// Prioritize: FillImage > FillColor > LineImage > LineColor
+		switch {
+		case p.FillImageSource.FillImageSource != nil:
+			fill = sampleImage(p.FillImageSource.FillImageSource, x, y)
+		case p.FillColor.FillColor != nil:
+			fill = p.FillColor.FillColor
+		case p.LineImageSource.LineImageSource != nil:
+			fill = sampleImage(p.LineImageSource.LineImageSource, x, y)
+		default:
+			fill = p.LineColor.LineColor
+		}
+		line = fill
+	}
+
+	if outerCov == 1 && innerCov == 1 {
+		return fill
+	}
+	if outerCov == 1 && innerCov == 0 {
+		return line
+	}
+	return mixCoverage(
+		[]color.Color{space, line, fill},
+		[]float64{1 - outerCov, outerCov - innerCov, innerCov},
+	)
+}
+
 // NewCircle creates a new Circle pattern.
 func NewCircle(ops ...func(any)) image.Image {
 	p := &Circle{
@@ -126,3 +213,8 @@ func NewCircle(ops ...func(any)) image.Image {
 func NewDemoCircle(ops ...func(any)) image.Image {
 	return NewCircle(ops...)
 }
+
+func init() {
+	RegisterPattern(generatorType("circle", &Circle{}, NewCircle))
+	RegisterPattern(generatorType("demo_circle", nil, NewDemoCircle))
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	_ "embed"
 	"flag"
 	"fmt"
@@ -16,11 +17,13 @@ import (
 	"log"
 	"os"
 	"path"
+	"reflect"
 	"sort"
 	"strconv"
 	"strings"
 	"text/template"
 	"unicode"
+	"unicode/utf8"
 
 	pattern "github.com/arran4/go-pattern"
 	"golang.org/x/image/font"
@@ -37,6 +40,9 @@ type PatternDemo struct {
 	OutputFilename string
 	Description    string
 	GoUsageSample  string
+	// GoUsage is the part of GoUsageSample that builds the pattern, without
+	// the code that writes it out.
+	GoUsage string
 
 	Generator func(image.Rectangle) image.Image
 
@@ -107,7 +113,7 @@ func main() {
 
 func discoverPatterns(root string) ([]PatternDemo, error) {
 	fset := token.NewFileSet()
-	pkgs, err := parser.ParseDir(fset, root, nil, 0)
+	pkgs, err := parser.ParseDir(fset, root, nil, parser.ParseComments)
 	if err != nil {
 		return nil, err
 	}
@@ -147,6 +153,7 @@ func discoverPatterns(root string) ([]PatternDemo, error) {
 				pd := PatternDemo{
 					Name:          name + " Pattern",
 					GoUsageSample: usage,
+					GoUsage:       constructorUsage(fn, fset, fileContent),
 				}
 
 				if fn.Doc != nil {
@@ -450,9 +457,14 @@ func (p *PatternDemo) Generate() image.Image {
 		d.Dot = fixed.P(currentX+(iw-labelW)/2, padding+20)
 		d.DrawString(it.label)
 
+		rendered, err := pattern.Render(context.Background(), it.img, pattern.SetBounds(b.Intersect(it.img.Bounds())))
+		if err != nil {
+			log.Fatalf("failed to render %s: %v", it.label, err)
+		}
+
 		imgX := currentX + (iw - sz)/2
 		r := image.Rect(imgX, padding+labelHeight, imgX+sz, padding+labelHeight+sz)
-		draw.Draw(dst, r, it.img, b.Min, draw.Src)
+		draw.Draw(dst, r, rendered, b.Min, draw.Src)
 
 		currentX += iw + padding
 	}
@@ -462,189 +474,336 @@ func (p *PatternDemo) Generate() image.Image {
 
 func generateCLIInit(demos []PatternDemo, outfile string) error {
 	fset := token.NewFileSet()
-	pkgs, err := parser.ParseDir(fset, ".", nil, 0)
+	pkgs, err := parser.ParseDir(fset, ".", nil, parser.ParseComments)
 	if err != nil {
 		return err
 	}
+	docs := newDocIndex(pkgs)
+
+	// Document the commands of the pattern registry for pattern-cli list and
+	// describe. Descriptions and Go usage come from the examples, as in the README;
+	// the first example of each command wins. Commands whose examples say nothing
+	// of them are described by their constructor or type doc comments, or else
+	// their registry parameters.
+	type commandDoc struct {
+		Description, GoUsage string
+	}
+	cmdDocs := make(map[string]*commandDoc)
+	for _, t := range pattern.Patterns() {
+		cmdDocs[t.Name] = &commandDoc{}
+	}
+	seen := make(map[string]bool)
+	for _, d := range demos {
+		name := strings.TrimSuffix(d.Name, " Pattern")
+		doc, ok := cmdDocs[toSnakeCase(name)]
+		if !ok || seen[toSnakeCase(name)] {
+			continue
+		}
+		seen[toSnakeCase(name)] = true
+		if desc := describeCommand(d.Description, "ExampleNew"+name); !isStubDoc(desc) {
+			doc.Description = desc
+		}
+		doc.GoUsage = d.GoUsage
+	}
+	names := make([]string, 0, len(cmdDocs))
+	for name, doc := range cmdDocs {
+		t, _ := pattern.LookupPattern(name)
+		if doc.Description == "" {
+			doc.Description = docs.constructor(docs.constructors[name])
+		}
+		if doc.Description == "" {
+			if typ := reflect.TypeOf(t.Sample); typ != nil {
+				if typ.Kind() == reflect.Pointer {
+					typ = typ.Elem()
+				}
+				doc.Description = docs.types[typ.Name()]
+			}
+			if isStubDoc(doc.Description) {
+				doc.Description = describeParams(t)
+			}
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var out strings.Builder
+	out.WriteString("// Code generated by cmd/bootstrap/main.go; DO NOT EDIT.\n")
+	out.WriteString("package pattern_cli\n\n")
+	out.WriteString("// generatedDocs documents the commands of the pattern registry.\n")
+	out.WriteString("var generatedDocs = map[string]commandDoc{\n")
+	for _, name := range names {
+		doc := cmdDocs[name]
+		if doc.Description+doc.GoUsage == "" {
+			continue
+		}
+		out.WriteString(fmt.Sprintf("\t%q: {\n", name))
+		for _, field := range []struct{ name, value string }{
+			{"Description", doc.Description},
+			{"GoUsage", doc.GoUsage},
+		} {
+			if field.value != "" {
+				out.WriteString(fmt.Sprintf("\t\t%s: %s,\n", field.name, goString(field.value)))
+			}
+		}
+		out.WriteString("\t},\n")
+	}
+	out.WriteString("}\n")
+
+	return os.WriteFile(outfile, []byte(out.String()), 0644)
+}
+
+// describeCommand tidies the doc comment of an example or constructor into a
+// command description. It drops a title line such as "Checker Pattern", the
+// Output: marker and the name that starts the first sentence, with any "is a
+// pattern that" after it.
+func describeCommand(doc, funcName string) string {
+	var lines []string
+	for i, line := range strings.Split(strings.TrimSpace(doc), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "Output:":
+			continue
+		case i == 0 && isTitle(line) && strings.Contains(strings.TrimSpace(doc), "\n"):
+			continue
+		}
+		lines = append(lines, line)
+	}
+	s := strings.Join(lines, "\n")
+	if rest, ok := strings.CutPrefix(s, funcName+" "); ok {
+		rest = strings.TrimPrefix(rest, "is a pattern that ")
+		r, size := utf8.DecodeRuneInString(rest)
+		s = string(unicode.ToUpper(r)) + rest[size:]
+	}
+	return s
+}
 
-	type Command struct {
-		Name      string
-		FuncName  string
-		Args      []string
-		TakesInput bool
+// isStubDoc reports whether a description says nothing about the command, as
+// "Is a convenience function.", "Creates a new Foo pattern." or a note on the
+// example's place in the documentation do.
+func isStubDoc(desc string) bool {
+	// Only the first sentence is judged, as that is what list shows.
+	desc = strings.Join(strings.Fields(desc), " ")
+	if i := strings.Index(desc, ". "); i >= 0 {
+		desc = desc[:i+1]
+	}
+	lower := strings.ToLower(desc)
+	switch {
+	case desc == "",
+		strings.HasPrefix(lower, "is a convenience function"),
+		strings.HasPrefix(lower, "helper for"),
+		strings.Contains(lower, "documentation"),
+		strings.Contains(lower, "demo variant for readme"):
+		return true
+	}
+	rest, ok := strings.CutPrefix(desc, "Creates a new ")
+	if !ok {
+		return false
+	}
+	// Only names follow, as in "Creates a new Bitwise And pattern."
+	for _, word := range strings.Fields(strings.TrimSuffix(strings.TrimSuffix(rest, "."), " pattern")) {
+		if r, _ := utf8.DecodeRuneInString(word); !unicode.IsUpper(r) {
+			return false
+		}
 	}
-	var commands []Command
+	return true
+}
 
+// docIndex holds the doc comments of the package's constructors and pattern
+// types, and what each constructor builds, so a constructor with a stub comment
+// can be described by the type it returns or the constructor it calls.
+type docIndex struct {
+	funcs map[string]string
+	types map[string]string
+	// constructors maps a command name to the constructor named after it, as
+	// checker to NewChecker.
+	constructors map[string]string
+	// builds maps a constructor to the types it takes the address of literals
+	// of, and calls to the constructor it returns the result of.
+	builds map[string][]string
+	calls  map[string]string
+}
+
+func newDocIndex(pkgs map[string]*ast.Package) *docIndex {
+	d := &docIndex{
+		funcs:        make(map[string]string),
+		types:        make(map[string]string),
+		constructors: make(map[string]string),
+		builds:       make(map[string][]string),
+		calls:        make(map[string]string),
+	}
 	for _, pkg := range pkgs {
 		for filename, f := range pkg.Files {
 			if strings.HasSuffix(filename, "_test.go") || strings.HasSuffix(filename, "_example.go") {
 				continue
 			}
-
-			ast.Inspect(f, func(n ast.Node) bool {
-				fn, ok := n.(*ast.FuncDecl)
-				if !ok {
-					return true
-				}
-				if !strings.HasPrefix(fn.Name.Name, "New") {
-					return true
-				}
-
-				cmdName := toSnakeCase(strings.TrimPrefix(fn.Name.Name, "New"))
-
-				var args []string
-				takesInput := false
-
-				if fn.Type.Params != nil {
-					for i, param := range fn.Type.Params.List {
-						// type string
-						typeName := ""
-						isVariadic := false
-
-						if ident, ok := param.Type.(*ast.Ident); ok {
-							typeName = ident.Name
-						} else if sel, ok := param.Type.(*ast.SelectorExpr); ok {
-							// e.g. color.Color
-							if x, ok := sel.X.(*ast.Ident); ok {
-								typeName = x.Name + "." + sel.Sel.Name
+			for _, decl := range f.Decls {
+				switch decl := decl.(type) {
+				case *ast.GenDecl:
+					if decl.Tok != token.TYPE {
+						continue
+					}
+					for _, spec := range decl.Specs {
+						ts := spec.(*ast.TypeSpec)
+						doc := ts.Doc
+						if doc == nil && len(decl.Specs) == 1 {
+							doc = decl.Doc
+						}
+						if doc != nil {
+							d.types[ts.Name.Name] = describeCommand(doc.Text(), ts.Name.Name)
+						}
+					}
+				case *ast.FuncDecl:
+					if decl.Recv != nil || !strings.HasPrefix(decl.Name.Name, "New") || decl.Body == nil {
+						continue
+					}
+					d.constructors[toSnakeCase(strings.TrimPrefix(decl.Name.Name, "New"))] = decl.Name.Name
+					if decl.Doc != nil {
+						d.funcs[decl.Name.Name] = describeCommand(decl.Doc.Text(), decl.Name.Name)
+					}
+					ast.Inspect(decl.Body, func(n ast.Node) bool {
+						switch n := n.(type) {
+						case *ast.UnaryExpr:
+							lit, ok := n.X.(*ast.CompositeLit)
+							if !ok || n.Op != token.AND {
+								break
 							}
-						} else if ell, ok := param.Type.(*ast.Ellipsis); ok {
-							isVariadic = true
-							if ident, ok := ell.Elt.(*ast.Ident); ok {
-								typeName = "..." + ident.Name
-							} else if _, ok := ell.Elt.(*ast.FuncType); ok {
-								// ...func(any)
-								typeName = "...func(any)"
+							if id, ok := lit.Type.(*ast.Ident); ok {
+								d.builds[decl.Name.Name] = append(d.builds[decl.Name.Name], id.Name)
 							}
-						}
-
-						// Handle parameter names
-						for range param.Names {
-							if isVariadic {
-								if typeName == "...func(any)" {
-									// Skip this arg in CLI requirements
-									continue
+						case *ast.ReturnStmt:
+							if len(n.Results) != 1 {
+								break
+							}
+							if call, ok := n.Results[0].(*ast.CallExpr); ok {
+								if id, ok := call.Fun.(*ast.Ident); ok && strings.HasPrefix(id.Name, "New") {
+									d.calls[decl.Name.Name] = id.Name
 								}
-								// Treat other variadics as unsupported for now, or strings
-								args = append(args, typeName)
-							} else if i == 0 && typeName == "image.Image" {
-								takesInput = true
-							} else {
-								args = append(args, typeName)
 							}
 						}
-					}
+						return true
+					})
 				}
-
-				commands = append(commands, Command{
-					Name:      cmdName,
-					FuncName:  fn.Name.Name,
-					Args:      args,
-					TakesInput: takesInput,
-				})
-				return true
-			})
+			}
 		}
 	}
+	return d
+}
 
-	// Sort commands by name for stable output
-	sort.Slice(commands, func(i, j int) bool {
-		return commands[i].Name < commands[j].Name
-	})
-
-	// Generate file content
-	var sb strings.Builder
-	sb.WriteString("// Code generated by cmd/bootstrap/main.go; DO NOT EDIT.\n")
-	sb.WriteString("package pattern_cli\n\n")
-	sb.WriteString("import (\n")
-	sb.WriteString("\t\"fmt\"\n")
-	sb.WriteString("\t\"image\"\n")
-	sb.WriteString("\t\"strconv\"\n")
-	sb.WriteString("\t\"github.com/arran4/go-pattern/dsl\"\n")
-	sb.WriteString("\t\"github.com/arran4/go-pattern\"\n")
-	sb.WriteString(")\n\n")
-
-	sb.WriteString("func RegisterGeneratedCommands(fm dsl.FuncMap) {\n")
-
-	for _, cmd := range commands {
-		sb.WriteString(fmt.Sprintf("\tfm[\"%s\"] = func(args []string, input image.Image) (image.Image, error) {\n", cmd.Name))
-
-		// Check arg count
-		sb.WriteString(fmt.Sprintf("\t\tif len(args) < %d {\n", len(cmd.Args)))
-		sb.WriteString(fmt.Sprintf("\t\t\treturn nil, fmt.Errorf(\"%s requires %d arguments\")\n", cmd.Name, len(cmd.Args)))
-		sb.WriteString("\t\t}\n")
-
-		// Check support first
-		supported := true
-		for _, argType := range cmd.Args {
-			switch argType {
-			case "int", "float64", "bool", "color.Color", "string":
-				// supported
-			default:
-				supported = false
+// constructor describes the pattern fn builds: by fn's doc comment, else that of
+// a type it builds, else that of the constructor it calls.
+func (d *docIndex) constructor(fn string) string {
+	for seen := map[string]bool{}; fn != "" && !seen[fn]; fn = d.calls[fn] {
+		seen[fn] = true
+		if desc := d.funcs[fn]; !isStubDoc(desc) {
+			return desc
+		}
+		for _, t := range d.builds[fn] {
+			if desc := d.types[t]; !isStubDoc(desc) {
+				return desc
 			}
 		}
+	}
+	return ""
+}
 
-		if supported {
-			// Parse args
-			callArgs := []string{}
-			if cmd.TakesInput {
-				sb.WriteString("\t\tif input == nil {\n")
-				sb.WriteString(fmt.Sprintf("\t\t\treturn nil, fmt.Errorf(\"%s requires an input image\")\n", cmd.Name))
-				sb.WriteString("\t\t}\n")
-				callArgs = append(callArgs, "input")
-			}
+// describeParams describes a registered pattern with no doc comment by its
+// parameters.
+func describeParams(t *pattern.PatternType) string {
+	var params []string
+	for _, p := range t.Params {
+		if p.Doc != "" {
+			params = append(params, fmt.Sprintf("%s (%s)", p.Name, strings.TrimSuffix(p.Doc, ".")))
+		}
+	}
+	if len(params) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("A %s set by %s.", t.Category, strings.Join(params, ", "))
+}
 
-			for i, argType := range cmd.Args {
-				varName := fmt.Sprintf("arg%d", i)
-				switch argType {
-				case "int":
-					sb.WriteString(fmt.Sprintf("\t\t%s, err := strconv.Atoi(args[%d])\n", varName, i))
-					sb.WriteString("\t\tif err != nil {\n")
-					sb.WriteString(fmt.Sprintf("\t\t\treturn nil, fmt.Errorf(\"argument %d must be int: %%v\", err)\n", i))
-					sb.WriteString("\t\t}\n")
-					callArgs = append(callArgs, varName)
-				case "float64":
-					sb.WriteString(fmt.Sprintf("\t\t%s, err := strconv.ParseFloat(args[%d], 64)\n", varName, i))
-					sb.WriteString("\t\tif err != nil {\n")
-					sb.WriteString(fmt.Sprintf("\t\t\treturn nil, fmt.Errorf(\"argument %d must be float: %%v\", err)\n", i))
-					sb.WriteString("\t\t}\n")
-					callArgs = append(callArgs, varName)
-				case "bool":
-					sb.WriteString(fmt.Sprintf("\t\t%s, err := strconv.ParseBool(args[%d])\n", varName, i))
-					sb.WriteString("\t\tif err != nil {\n")
-					sb.WriteString(fmt.Sprintf("\t\t\treturn nil, fmt.Errorf(\"argument %d must be bool: %%v\", err)\n", i))
-					sb.WriteString("\t\t}\n")
-					callArgs = append(callArgs, varName)
-				case "color.Color":
-					sb.WriteString(fmt.Sprintf("\t\t%s, err := parseColor(args[%d])\n", varName, i))
-					sb.WriteString("\t\tif err != nil {\n")
-					sb.WriteString(fmt.Sprintf("\t\t\treturn nil, fmt.Errorf(\"argument %d must be color: %%v\", err)\n", i))
-					sb.WriteString("\t\t}\n")
-					callArgs = append(callArgs, varName)
-				case "string":
-					callArgs = append(callArgs, fmt.Sprintf("args[%d]", i))
+// constructorUsage returns the code of an example that builds its pattern: the
+// statements before it starts writing the image out, and the expression of a
+// final return.
+func constructorUsage(fn *ast.FuncDecl, fset *token.FileSet, content []byte) string {
+	start := fset.Position(fn.Body.Lbrace).Offset + 1
+	end := fset.Position(fn.Body.Rbrace).Offset
+	var tail string
+	for _, stmt := range fn.Body.List {
+		if ret, ok := stmt.(*ast.ReturnStmt); ok {
+			end = fset.Position(ret.Pos()).Offset
+			if len(ret.Results) == 1 {
+				if _, ok := ret.Results[0].(*ast.Ident); !ok {
+					tail = "\t" + string(content[fset.Position(ret.Results[0].Pos()).Offset:fset.Position(ret.Results[0].End()).Offset])
 				}
 			}
+			break
+		}
+		if writesOutput(stmt) {
+			end = fset.Position(stmt.Pos()).Offset
+			break
+		}
+	}
+	// Comments that lead into the code left out go with it.
+	lines := strings.Split(string(content[start:end]), "\n")
+	for len(lines) > 0 {
+		if last := strings.TrimSpace(lines[len(lines)-1]); last != "" && !strings.HasPrefix(last, "//") {
+			break
+		}
+		lines = lines[:len(lines)-1]
+	}
+	if tail != "" {
+		lines = append(lines, tail)
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
 
-			sb.WriteString(fmt.Sprintf("\t\treturn pattern.%s(%s), nil\n", cmd.FuncName, strings.Join(callArgs, ", ")))
-		} else {
-			sb.WriteString(fmt.Sprintf("\t\treturn nil, fmt.Errorf(\"command %s has unsupported argument types\")\n", cmd.Name))
+// writesOutput reports whether stmt uses the fmt, os or png packages or an
+// example's output filename, which examples only do to write their image out.
+func writesOutput(stmt ast.Stmt) bool {
+	found := false
+	ast.Inspect(stmt, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			if x, ok := n.X.(*ast.Ident); ok && (x.Name == "fmt" || x.Name == "os" || x.Name == "png") {
+				found = true
+			}
+		case *ast.Ident:
+			if strings.HasSuffix(n.Name, "OutputFilename") {
+				found = true
+			}
 		}
+		return !found
+	})
+	return found
+}
 
-		sb.WriteString("\t}\n")
+// isTitle reports whether a line is a heading, such as "Retro VHS Effect", with
+// no word starting in lower case.
+func isTitle(line string) bool {
+	for _, word := range strings.Fields(line) {
+		if r, _ := utf8.DecodeRuneInString(word); unicode.IsLower(r) {
+			return false
+		}
 	}
+	return !strings.HasSuffix(line, ".")
+}
 
-	sb.WriteString("}\n")
-
-	return os.WriteFile(outfile, []byte(sb.String()), 0644)
+// goString writes s as a Go string literal, raw when it can be.
+func goString(s string) string {
+	if strings.ContainsAny(s, "`\r") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
 }
 
+// toSnakeCase converts a Go name to snake case, keeping acronyms such as PCB and
+// VHS together.
 func toSnakeCase(s string) string {
 	var sb strings.Builder
-	for i, r := range s {
+	runes := []rune(s)
+	for i, r := range runes {
 		if unicode.IsUpper(r) {
-			if i > 0 {
+			if i > 0 && (!unicode.IsUpper(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
 				sb.WriteRune('_')
 			}
 			sb.WriteRune(unicode.ToLower(r))
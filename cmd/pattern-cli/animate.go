@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/arran4/go-pattern/pkg/pattern-cli"
+)
+
+var _ Cmd = (*animateCmd)(nil)
+
+type animateCmd struct {
+	*RootCmd
+	Flags *flag.FlagSet
+
+	pipeline string
+	graph    string
+	width    int
+	height   int
+	bounds   string
+	frames   int
+	fps      float64
+	format   string
+	palette  string
+	dither   string
+	out      string
+
+	SubCommands map[string]Cmd
+}
+
+func (c *animateCmd) Usage() {
+	err := executeUsage(os.Stderr, "animate_usage.txt", c)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating usage: %s\n", err)
+	}
+}
+
+func (c *animateCmd) Execute(args []string) error {
+	if len(args) > 0 {
+		if cmd, ok := c.SubCommands[args[0]]; ok {
+			return cmd.Execute(args[1:])
+		}
+	}
+	err := c.Flags.Parse(args)
+	if err != nil {
+		return NewUserError(err, fmt.Sprintf("flag parse error %s", err.Error()))
+	}
+	return pattern_cli.Animate(c.pipeline, c.graph, c.width, c.height, c.bounds, c.frames, c.fps, c.format, c.palette, c.dither, c.out)
+}
+
+func (c *RootCmd) NewanimateCmd() *animateCmd {
+	set := flag.NewFlagSet("animate", flag.ContinueOnError)
+	v := &animateCmd{
+		RootCmd:     c,
+		Flags:       set,
+		SubCommands: make(map[string]Cmd),
+	}
+
+	set.StringVar(&v.pipeline, "pipeline", "", "The pipeline to animate")
+	set.StringVar(&v.graph, "graph", "", "A JSON or YAML graph file to animate, or - for standard input")
+	set.IntVar(&v.width, "width", 0, "The width to render at")
+	set.IntVar(&v.height, "height", 0, "The height to render at")
+	set.StringVar(&v.bounds, "bounds", "", "The bounds to render, as minX,minY,maxX,maxY")
+	set.IntVar(&v.frames, "frames", 30, "The number of frames")
+	set.Float64Var(&v.fps, "fps", 30, "The frames shown each second")
+	set.StringVar(&v.format, "format", "", "The output format: gif or apng (default apng for .png and .apng files, otherwise gif)")
+	set.StringVar(&v.palette, "palette", "", "The GIF palette: a name such as plan9, websafe or cga, or a list of colours (default plan9)")
+	set.StringVar(&v.dither, "dither", "", "The dither reducing GIF frames to the palette (default bayer8x8_dither)")
+	set.StringVar(&v.out, "out", "", "The file to write, or - for standard output")
+
+	set.Usage = v.Usage
+
+	return v
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/arran4/go-pattern/pkg/pattern-cli"
+)
+
+var _ Cmd = (*describeCmd)(nil)
+
+type describeCmd struct {
+	*RootCmd
+	Flags *flag.FlagSet
+
+	name string
+
+	SubCommands map[string]Cmd
+}
+
+func (c *describeCmd) Usage() {
+	err := executeUsage(os.Stderr, "describe_usage.txt", c)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating usage: %s\n", err)
+	}
+}
+
+func (c *describeCmd) Execute(args []string) error {
+	if len(args) > 0 {
+		if cmd, ok := c.SubCommands[args[0]]; ok {
+			return cmd.Execute(args[1:])
+		}
+	}
+	err := c.Flags.Parse(args)
+	if err != nil {
+		return NewUserError(err, fmt.Sprintf("flag parse error %s", err.Error()))
+	}
+	if c.Flags.NArg() != 1 {
+		c.Usage()
+		return NewUserError(nil, "describe takes a single command name")
+	}
+	c.name = c.Flags.Arg(0)
+	return pattern_cli.Describe(c.name)
+}
+
+func (c *RootCmd) NewdescribeCmd() *describeCmd {
+	set := flag.NewFlagSet("describe", flag.ContinueOnError)
+	v := &describeCmd{
+		RootCmd:     c,
+		Flags:       set,
+		SubCommands: make(map[string]Cmd),
+	}
+
+	set.Usage = v.Usage
+
+	return v
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/arran4/go-pattern/pkg/pattern-cli"
+)
+
+var _ Cmd = (*renderCmd)(nil)
+
+type renderCmd struct {
+	*RootCmd
+	Flags *flag.FlagSet
+
+	pipeline string
+	graph    string
+	width    int
+	height   int
+	bounds   string
+	format   string
+	quality  int
+	out      string
+
+	SubCommands map[string]Cmd
+}
+
+func (c *renderCmd) Usage() {
+	err := executeUsage(os.Stderr, "render_usage.txt", c)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating usage: %s\n", err)
+	}
+}
+
+func (c *renderCmd) Execute(args []string) error {
+	if len(args) > 0 {
+		if cmd, ok := c.SubCommands[args[0]]; ok {
+			return cmd.Execute(args[1:])
+		}
+	}
+	err := c.Flags.Parse(args)
+	if err != nil {
+		return NewUserError(err, fmt.Sprintf("flag parse error %s", err.Error()))
+	}
+	return pattern_cli.Render(c.pipeline, c.graph, c.width, c.height, c.bounds, c.format, c.quality, c.out)
+}
+
+func (c *RootCmd) NewrenderCmd() *renderCmd {
+	set := flag.NewFlagSet("render", flag.ContinueOnError)
+	v := &renderCmd{
+		RootCmd:     c,
+		Flags:       set,
+		SubCommands: make(map[string]Cmd),
+	}
+
+	set.StringVar(&v.pipeline, "pipeline", "", "The pipeline to render")
+	set.StringVar(&v.graph, "graph", "", "A JSON or YAML graph file to render, or - for standard input")
+	set.IntVar(&v.width, "width", 0, "The width to render at")
+	set.IntVar(&v.height, "height", 0, "The height to render at")
+	set.StringVar(&v.bounds, "bounds", "", "The bounds to render, as minX,minY,maxX,maxY")
+	set.StringVar(&v.format, "format", "", "The output format: png, jpeg, gif, bmp, tiff, or ansi, sixel or kitty to draw in the terminal (default from --out, or png)")
+	set.IntVar(&v.quality, "quality", 0, "The JPEG quality, 1 to 100")
+	set.StringVar(&v.out, "out", "", "The file to write, or - for standard output (the default for terminal formats)")
+
+	set.Usage = v.Usage
+
+	return v
+}
@@ -65,6 +65,16 @@ func NewRoot(name, version, commit, date string) (*RootCmd, error) {
 
 	c.Commands["run"] = c.NewrunCmd()
 
+	c.Commands["render"] = c.NewrenderCmd()
+
+	c.Commands["list"] = c.NewlistCmd()
+
+	c.Commands["describe"] = c.NewdescribeCmd()
+
+	c.Commands["serve"] = c.NewserveCmd()
+
+	c.Commands["animate"] = c.NewanimateCmd()
+
 	return c, nil
 }
 
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/arran4/go-pattern/pkg/pattern-cli"
+)
+
+var _ Cmd = (*serveCmd)(nil)
+
+type serveCmd struct {
+	*RootCmd
+	Flags *flag.FlagSet
+
+	addr   string
+	script string
+
+	SubCommands map[string]Cmd
+}
+
+func (c *serveCmd) Usage() {
+	err := executeUsage(os.Stderr, "serve_usage.txt", c)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error generating usage: %s\n", err)
+	}
+}
+
+func (c *serveCmd) Execute(args []string) error {
+	if len(args) > 0 {
+		if cmd, ok := c.SubCommands[args[0]]; ok {
+			return cmd.Execute(args[1:])
+		}
+	}
+	err := c.Flags.Parse(args)
+	if err != nil {
+		return NewUserError(err, fmt.Sprintf("flag parse error %s", err.Error()))
+	}
+	return pattern_cli.Serve(c.addr, c.script)
+}
+
+func (c *RootCmd) NewserveCmd() *serveCmd {
+	set := flag.NewFlagSet("serve", flag.ContinueOnError)
+	v := &serveCmd{
+		RootCmd:     c,
+		Flags:       set,
+		SubCommands: make(map[string]Cmd),
+	}
+
+	set.StringVar(&v.addr, "addr", "localhost:8080", "The address to listen on")
+	set.StringVar(&v.script, "script", "", "A script file to preview on /live, reloading when it changes")
+
+	set.Usage = v.Usage
+
+	return v
+}
@@ -32,19 +32,22 @@ func (c *ColorMap) At(x, y int) color.Color {
 		return color.Black
 	}
 
-	// Get source color
-	srcColor := c.Source.At(x, y)
+	if len(c.Stops) == 0 {
+		return c.Source.At(x, y) // No mapping, return original
+	}
 
 	// Convert to grayscale intensity [0, 1]
-	// We use 16-bit grayscale for better precision to avoid banding
-	gray := color.Gray16Model.Convert(srcColor).(color.Gray16)
-	t := float64(gray.Y) / 65535.0
-
-	// Find the stops surrounding t
-	if len(c.Stops) == 0 {
-		return srcColor // No mapping, return original
+	// Scalar fields provide the value at full precision; otherwise
+	// we use 16-bit grayscale for better precision to avoid banding
+	var t float64
+	if f, ok := c.Source.(ScalarField); ok {
+		t = f.ValueAt(float64(x), float64(y))
+	} else {
+		gray := color.Gray16Model.Convert(c.Source.At(x, y)).(color.Gray16)
+		t = float64(gray.Y) / 65535.0
 	}
 
+	// Find the stops surrounding t
 	// Handle edge cases
 	if t <= c.Stops[0].Position {
 		return c.Stops[0].Color
@@ -90,3 +93,24 @@ func NewColorMap(source image.Image, stops ...ColorStop) image.Image {
 		Stops:  sortedStops,
 	}
 }
+
+func init() {
+	RegisterPattern(&PatternType{
+		Name:     "color_map",
+		Category: CategoryFilter,
+		Inputs:   []Input{{Name: "source"}},
+		Params: []Param{
+			{Name: "stops", Type: ParamColorStops, Doc: "Colours at positions between 0 and 1 of the source intensity."},
+		},
+		Sample: &ColorMap{},
+		New: func(a *Args) (image.Image, error) {
+			return applyOps(NewColorMap(a.Input("source"), a.ColorStops("stops")...), a.Options), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			p := img.(*ColorMap)
+			a.SetInput("source", p.Source)
+			a.Set("stops", p.Stops)
+			return nil
+		},
+	})
+}
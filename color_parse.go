@@ -0,0 +1,278 @@
+package pattern
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/colornames"
+)
+
+// ParseColor parses a colour written as text, as accepted throughout the DSL, the
+// graph format and the pattern registry:
+//
+//   - "#rgb", "#rgba", "#rrggbb" or "#rrggbbaa"
+//   - rgb(r, g, b) or rgba(r, g, b, a), with channels from 0 to 255 or as percentages
+//   - hsl(h, s, l) or hsla(h, s, l, a), with the hue in degrees (or deg, rad, grad
+//     or turn) and the saturation and lightness as percentages
+//   - oklch(l, c, h), with the lightness from 0 to 1 or as a percentage, the chroma
+//     from 0 (100% is 0.4) and the hue as for hsl
+//   - gray(v) or gray(v, a), with the level from 0 to 255 or as a percentage, and
+//     the X11 names gray0 to gray100
+//   - "transparent" or an SVG colour name
+//
+// Function arguments may also be separated by spaces, with the alpha after a slash,
+// as in rgb(255 0 0 / 50%). Alpha is from 0 to 1 or a percentage. Colours are not
+// premultiplied, as in CSS; opaque colours are returned as color.RGBA, or color.Gray
+// for gray levels, and translucent ones as color.NRGBA. Names are not case sensitive.
+func ParseColor(s string) (color.Color, error) {
+	s = strings.TrimSpace(s)
+	name := strings.ToLower(s)
+	switch {
+	case strings.HasPrefix(name, "#"):
+		return parseHexColor(s, name[1:])
+	case strings.ContainsAny(name, "()"):
+		open := strings.IndexByte(name, '(')
+		if open < 0 || !strings.HasSuffix(name, ")") || strings.Count(name, "(") != 1 || strings.Count(name, ")") != 1 {
+			return nil, colorError(s, "unbalanced parentheses")
+		}
+		return parseColorFunc(s, strings.TrimSpace(name[:open]), name[open+1:len(name)-1])
+	case name == "transparent":
+		return color.RGBA{}, nil
+	}
+	if c, ok := colornames.Map[name]; ok {
+		return c, nil
+	}
+	for _, prefix := range []string{"gray", "grey"} {
+		if level, ok := strings.CutPrefix(name, prefix); ok && level != "" {
+			v, err := strconv.Atoi(level)
+			if err != nil || v < 0 || v > 100 {
+				return nil, colorError(s, "gray levels are gray0 to gray100")
+			}
+			return color.Gray{Y: uint8(math.Round(float64(v) * 255 / 100))}, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown colour %q", s)
+}
+
+func colorError(s, format string, args ...any) error {
+	return fmt.Errorf("invalid colour %q: %s", s, fmt.Sprintf(format, args...))
+}
+
+func parseHexColor(s, hex string) (color.Color, error) {
+	if len(hex) == 3 || len(hex) == 4 {
+		var b strings.Builder
+		for _, r := range hex {
+			b.WriteRune(r)
+			b.WriteRune(r)
+		}
+		hex = b.String()
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	if len(hex) != 8 {
+		return nil, colorError(s, "expected 3, 4, 6 or 8 hex digits")
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return nil, colorError(s, "%q is not hexadecimal", strings.TrimPrefix(s, "#"))
+	}
+	return colorFrom(float64(v>>24), float64(v>>16&0xff), float64(v>>8&0xff), float64(v&0xff)/255), nil
+}
+
+// parseColorFunc parses the arguments of one of the colour functions.
+func parseColorFunc(s, fn, args string) (color.Color, error) {
+	switch fn {
+	case "rgb", "rgba", "hsl", "hsla", "oklch", "gray", "grey":
+	default:
+		return nil, colorError(s, "unknown colour function %q", fn)
+	}
+	values, alpha, err := colorArgs(s, args)
+	if err != nil {
+		return nil, err
+	}
+	want := 3
+	if fn == "gray" || fn == "grey" {
+		want = 1
+	}
+	if alpha == "" && len(values) == want+1 {
+		alpha, values = values[want], values[:want]
+	}
+	if len(values) != want {
+		return nil, colorError(s, "%s takes %d values and an optional alpha, got %d", fn, want, len(values))
+	}
+	a := 1.0
+	if alpha != "" {
+		if a, err = colorComponent(s, "alpha", alpha, 1, 1, 1); err != nil {
+			return nil, err
+		}
+	}
+	switch fn {
+	case "rgb", "rgba":
+		var rgb [3]float64
+		for i, name := range []string{"red", "green", "blue"} {
+			if rgb[i], err = colorComponent(s, name, values[i], 255, 1, 255); err != nil {
+				return nil, err
+			}
+		}
+		return colorFrom(rgb[0], rgb[1], rgb[2], a), nil
+	case "hsl", "hsla":
+		h, err := hue(s, values[0])
+		if err != nil {
+			return nil, err
+		}
+		sat, err := colorComponent(s, "saturation", values[1], 1, 0.01, 100)
+		if err != nil {
+			return nil, err
+		}
+		l, err := colorComponent(s, "lightness", values[2], 1, 0.01, 100)
+		if err != nil {
+			return nil, err
+		}
+		r, g, b := hslToRGB(h, sat, l)
+		return colorFrom(r*255, g*255, b*255, a), nil
+	case "oklch":
+		l, err := colorComponent(s, "lightness", values[0], 1, 1, 1)
+		if err != nil {
+			return nil, err
+		}
+		c, err := colorComponent(s, "chroma", values[1], 0.4, 1, math.Inf(1))
+		if err != nil {
+			return nil, err
+		}
+		h, err := hue(s, values[2])
+		if err != nil {
+			return nil, err
+		}
+		r, g, b := oklchToRGB(l, c, h)
+		return colorFrom(r*255, g*255, b*255, a), nil
+	default: // gray
+		v, err := colorComponent(s, "level", values[0], 255, 1, 255)
+		if err != nil {
+			return nil, err
+		}
+		if a == 1 {
+			return color.Gray{Y: uint8(math.Round(v))}, nil
+		}
+		return colorFrom(v, v, v, a), nil
+	}
+}
+
+// colorArgs splits the arguments of a colour function, accepting both "r, g, b, a"
+// and "r g b / a". The alpha is only separated out when it follows a slash.
+func colorArgs(s, args string) (values []string, alpha string, err error) {
+	if strings.Contains(args, ",") {
+		if strings.Contains(args, "/") {
+			return nil, "", colorError(s, "use either commas or a slash before the alpha, not both")
+		}
+		for _, v := range strings.Split(args, ",") {
+			if v = strings.TrimSpace(v); v == "" {
+				return nil, "", colorError(s, "missing value")
+			}
+			values = append(values, v)
+		}
+		return values, "", nil
+	}
+	main, alpha, slash := strings.Cut(args, "/")
+	if alpha = strings.TrimSpace(alpha); slash && (alpha == "" || strings.Contains(alpha, "/")) {
+		return nil, "", colorError(s, "expected a single alpha after '/'")
+	}
+	return strings.Fields(main), alpha, nil
+}
+
+// colorComponent parses a number, scaled by unit, or a percentage, where 100% is
+// full, checking that the number is between 0 and max.
+func colorComponent(s, name, v string, full, unit, max float64) (float64, error) {
+	pct := strings.HasSuffix(v, "%")
+	f, err := strconv.ParseFloat(strings.TrimSuffix(v, "%"), 64)
+	if err != nil || math.IsNaN(f) {
+		return 0, colorError(s, "%s %q is not a number", name, v)
+	}
+	switch {
+	case pct:
+		if f < 0 || f > 100 {
+			return 0, colorError(s, "%s %s is outside 0%% to 100%%", name, v)
+		}
+		return f / 100 * full, nil
+	case f < 0:
+		return 0, colorError(s, "%s %s is negative", name, v)
+	case f > max:
+		return 0, colorError(s, "%s %s is outside 0 to %v", name, v, max)
+	}
+	return f * unit, nil
+}
+
+// hue parses an angle, in degrees unless it has a unit, into [0, 360).
+func hue(s, v string) (float64, error) {
+	scale := 1.0
+	for _, u := range []struct {
+		suffix string
+		scale  float64
+	}{{"deg", 1}, {"grad", 0.9}, {"rad", 180 / math.Pi}, {"turn", 360}} {
+		if n, ok := strings.CutSuffix(v, u.suffix); ok {
+			v, scale = n, u.scale
+			break
+		}
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return 0, colorError(s, "hue %q is not an angle", v)
+	}
+	return math.Mod(math.Mod(f*scale, 360)+360, 360), nil
+}
+
+// colorFrom returns channels from 0 to 255 and an alpha from 0 to 1 as a colour,
+// rounding to 8 bits.
+func colorFrom(r, g, b, a float64) color.Color {
+	c := color.NRGBA{R: uint8(math.Round(r)), G: uint8(math.Round(g)), B: uint8(math.Round(b)), A: uint8(math.Round(a * 255))}
+	if c.A == 0xff {
+		return color.RGBA{R: c.R, G: c.G, B: c.B, A: 0xff}
+	}
+	return c
+}
+
+func hslToRGB(h, s, l float64) (r, g, b float64) {
+	c := (1 - math.Abs(2*l-1)) * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := l - c/2
+	switch {
+	case h < 60:
+		r, g, b = c, x, 0
+	case h < 120:
+		r, g, b = x, c, 0
+	case h < 180:
+		r, g, b = 0, c, x
+	case h < 240:
+		r, g, b = 0, x, c
+	case h < 300:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+	return r + m, g + m, b + m
+}
+
+// oklchToRGB converts OKLCH to sRGB, clipping colours outside the sRGB gamut.
+func oklchToRGB(l, c, h float64) (r, g, b float64) {
+	rad := h * math.Pi / 180
+	ca, cb := c*math.Cos(rad), c*math.Sin(rad)
+	lc := math.Pow(l+0.3963377774*ca+0.2158037573*cb, 3)
+	mc := math.Pow(l-0.1055613458*ca-0.0638541728*cb, 3)
+	sc := math.Pow(l-0.0894841775*ca-1.2914855480*cb, 3)
+	r = srgbEncode(4.0767416621*lc - 3.3077115913*mc + 0.2309699292*sc)
+	g = srgbEncode(-1.2684380046*lc + 2.6097574011*mc - 0.3413193965*sc)
+	b = srgbEncode(-0.0041960863*lc - 0.7034186147*mc + 1.7076147010*sc)
+	return r, g, b
+}
+
+// srgbEncode applies the sRGB transfer function to a linear value, clamped to [0, 1].
+func srgbEncode(v float64) float64 {
+	v = math.Max(0, math.Min(1, v))
+	if v <= 0.0031308 {
+		return 12.92 * v
+	}
+	return 1.055*math.Pow(v, 1/2.4) - 0.055
+}
@@ -0,0 +1,77 @@
+package pattern
+
+import (
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		in   string
+		want color.Color
+	}{
+		{"#f00", color.RGBA{255, 0, 0, 255}},
+		{"#F008", color.NRGBA{255, 0, 0, 0x88}},
+		{"#102030", color.RGBA{0x10, 0x20, 0x30, 255}},
+		{"#10203040", color.NRGBA{0x10, 0x20, 0x30, 0x40}},
+		{"rgb(255, 128, 0)", color.RGBA{255, 128, 0, 255}},
+		{"rgba(255, 128, 0, 0.5)", color.NRGBA{255, 128, 0, 128}},
+		{"RGB(100% 50% 0% / 25%)", color.NRGBA{255, 128, 0, 64}},
+		{"hsl(120, 100%, 50%)", color.RGBA{0, 255, 0, 255}},
+		{"hsla(0.5turn 100% 25% / 1)", color.RGBA{0, 128, 128, 255}},
+		{"hsl(-120deg, 100%, 50%)", color.RGBA{0, 0, 255, 255}},
+		{"oklch(62.8% 0.2577 29.23)", color.RGBA{255, 0, 0, 255}},
+		{"oklch(1 0 0)", color.RGBA{255, 255, 255, 255}},
+		{"oklch(0 0 0 / 50%)", color.NRGBA{0, 0, 0, 128}},
+		{"gray(128)", color.Gray{128}},
+		{"grey(50%, 0.5)", color.NRGBA{128, 128, 128, 128}},
+		{"gray50", color.Gray{128}},
+		{"transparent", color.RGBA{}},
+		{" Red ", color.RGBA{255, 0, 0, 255}},
+	}
+	for _, tt := range tests {
+		got, err := ParseColor(tt.in)
+		if err != nil {
+			t.Errorf("ParseColor(%q) failed: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseColor(%q): expected %#v, got %#v", tt.in, tt.want, got)
+		}
+	}
+}
+
+func TestParseColorErrors(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"#12", `invalid colour "#12": expected 3, 4, 6 or 8 hex digits`},
+		{"#12345g", `invalid colour "#12345g": "12345g" is not hexadecimal`},
+		{"rgb(300, 0, 0)", `invalid colour "rgb(300, 0, 0)": red 300 is outside 0 to 255`},
+		{"rgb(0, 0)", `invalid colour "rgb(0, 0)": rgb takes 3 values and an optional alpha, got 2`},
+		{"rgb(0, 0, x)", `invalid colour "rgb(0, 0, x)": blue "x" is not a number`},
+		{"rgb(nan, 0, 0)", `invalid colour "rgb(nan, 0, 0)": red "nan" is not a number`},
+		{"rgb(0, NaN%, 0)", `invalid colour "rgb(0, NaN%, 0)": green "nan%" is not a number`},
+		{"rgba(0, 0, 0, nan)", `invalid colour "rgba(0, 0, 0, nan)": alpha "nan" is not a number`},
+		{"rgba(0, 0, 0, 2)", `invalid colour "rgba(0, 0, 0, 2)": alpha 2 is outside 0 to 1`},
+		{"rgb(0 0 0 /)", `invalid colour "rgb(0 0 0 /)": expected a single alpha after '/'`},
+		{"rgb(0, 0, 0 / 1)", `invalid colour "rgb(0, 0, 0 / 1)": use either commas or a slash before the alpha, not both`},
+		{"hsl(0, 150%, 50%)", `invalid colour "hsl(0, 150%, 50%)": saturation 150% is outside 0% to 100%`},
+		{"hsl(north, 50%, 50%)", `invalid colour "hsl(north, 50%, 50%)": hue "north" is not an angle`},
+		{"hsl(nan, 50%, 50%)", `invalid colour "hsl(nan, 50%, 50%)": hue "nan" is not an angle`},
+		{"hsl(nandeg, 50%, 50%)", `invalid colour "hsl(nandeg, 50%, 50%)": hue "nan" is not an angle`},
+		{"oklch(0.5 -0.1 0)", `invalid colour "oklch(0.5 -0.1 0)": chroma -0.1 is negative`},
+		{"gray101", `invalid colour "gray101": gray levels are gray0 to gray100`},
+		{"rgb(0, 0, 0", `invalid colour "rgb(0, 0, 0": unbalanced parentheses`},
+		{"cmyk(0, 0, 0)", `invalid colour "cmyk(0, 0, 0)": unknown colour function "cmyk"`},
+		{"reddish", `unknown colour "reddish"`},
+	}
+	for _, tt := range tests {
+		_, err := ParseColor(tt.in)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("ParseColor(%q): expected error %q, got %v", tt.in, tt.want, err)
+		}
+	}
+}
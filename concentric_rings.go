@@ -66,3 +66,21 @@ func NewConcentricRings(colors []color.Color, ops ...func(any)) image.Image {
 	}
 	return p
 }
+
+func init() {
+	RegisterPattern(&PatternType{
+		Name:     "concentric_rings",
+		Category: CategoryGenerator,
+		Params: []Param{
+			{Name: "colors", Type: ParamColors, Doc: "Colours of the rings, from the centre out."},
+		},
+		Sample: &ConcentricRings{},
+		New: func(a *Args) (image.Image, error) {
+			return NewConcentricRings(a.Colors("colors"), a.Options...), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			a.Set("colors", img.(*ConcentricRings).Colors)
+			return nil
+		},
+	})
+}
@@ -11,9 +11,11 @@ var _ image.Image = (*ConcentricWater)(nil)
 
 // ConcentricWater renders concentric distance-field ripples with sine-driven height
 // that modulates both tint and inferred normals for a water-like look.
+// Animated with SetTime, the ripples move outwards one ring spacing a second.
 type ConcentricWater struct {
 	Null
 	Center
+	Time
 
 	RingSpacing      float64
 	Amplitude        float64
@@ -78,7 +80,7 @@ func (cw *ConcentricWater) heightAt(x, y float64) float64 {
 	dy := y - float64(cw.CenterY)
 	r := math.Hypot(dx, dy)
 
-	wave := math.Sin((r / spacing) * 2 * math.Pi)
+	wave := math.Sin((r/spacing - cw.Time.Time) * 2 * math.Pi)
 	atten := math.Exp(-falloff * r)
 
 	return amplitude * wave * atten
@@ -209,3 +211,7 @@ func ConcentricWaterLightDirection(x, y, z float64) func(any) {
 		}
 	}
 }
+
+func init() {
+	RegisterPattern(generatorType("concentric_water", &ConcentricWater{}, NewConcentricWater))
+}
@@ -8,6 +8,7 @@ import (
 // Ensure Crop implements the image.Image interface.
 var _ image.Image = (*Crop)(nil)
 
+// Crop shows its source through a rectangular window, transparent outside it.
 type Crop struct {
 	img  image.Image
 	rect image.Rectangle
@@ -45,3 +46,24 @@ func NewCrop(img image.Image, rect image.Rectangle) image.Image {
 		rect: rect,
 	}
 }
+
+func init() {
+	RegisterPattern(&PatternType{
+		Name:     "crop",
+		Category: CategoryFilter,
+		Inputs:   []Input{{Name: "source"}},
+		Params: []Param{
+			{Name: "rect", Type: ParamRect, Default: image.Rectangle{}, Doc: "Region of the source to keep."},
+		},
+		Sample: &Crop{},
+		New: func(a *Args) (image.Image, error) {
+			return applyOps(NewCrop(a.Input("source"), a.Rect("rect")), a.Options), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			p := img.(*Crop)
+			a.SetInput("source", p.img)
+			a.Set("rect", p.rect)
+			return nil
+		},
+	})
+}
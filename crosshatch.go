@@ -102,3 +102,8 @@ func NewDemoCrossHatch(ops ...func(any)) image.Image {
 	}, ops...)
 	return NewCrossHatch(ops...)
 }
+
+func init() {
+	RegisterPattern(generatorType("cross_hatch", &CrossHatch{}, NewCrossHatch))
+	RegisterPattern(generatorType("demo_cross_hatch", nil, NewDemoCrossHatch))
+}
@@ -0,0 +1,331 @@
+package pattern
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sync"
+)
+
+// VectorField is a continuous two dimensional field of vectors. Coordinates are in
+// the same pixel space as image.Image.At, but may be fractional.
+type VectorField interface {
+	VectorAt(x, y float64) (float64, float64)
+}
+
+// VectorFieldOf returns img as a VectorField. Images that already implement
+// VectorField are returned unchanged; any other image is read as a flow map, its
+// red and green channels mapped from [0, 1] to X and Y components in [-1, 1].
+func VectorFieldOf(img image.Image) VectorField {
+	if f, ok := img.(VectorField); ok {
+		return f
+	}
+	return &flowMapField{img: img}
+}
+
+// flowMapField reads the red and green channels of an image as a vector.
+type flowMapField struct {
+	img image.Image
+}
+
+func (f *flowMapField) VectorAt(x, y float64) (float64, float64) {
+	r, g, _, _ := sampleImage(f.img, x, y).RGBA()
+	return float64(r)/0xffff*2 - 1, float64(g)/0xffff*2 - 1
+}
+
+// Ensure CurlNoise implements the image.Image, ScalarField and VectorField interfaces.
+var _ image.Image = (*CurlNoise)(nil)
+var _ ScalarField = (*CurlNoise)(nil)
+var _ VectorField = (*CurlNoise)(nil)
+
+// CurlComponent selects the component of a CurlNoise that it draws.
+type CurlComponent int
+
+const (
+	CurlX CurlComponent = iota
+	CurlY
+)
+
+// curlComponentNames are the names of the CurlComponent values in the pattern registry.
+var curlComponentNames = []string{"x", "y"}
+
+// CurlNoise is the curl of a noise potential ψ, the vector (∂ψ/∂y, -∂ψ/∂x). The
+// field has no divergence, so whatever flows along it swirls without bunching up
+// or thinning out, as smoke and water do.
+//
+// The potential is OpenSimplexNoise of the Seed and Frequency, or the Potential
+// image when it is set. The derivatives are measured in noise cells of the
+// Frequency, which keeps the components roughly in [-1, 1]. A Potential that is
+// not a ScalarField is differenced over at least a pixel, as it is read a pixel
+// at a time.
+//
+// As an image, CurlNoise draws one component, mapped to [0, 1], so X and Y can be
+// given to Warp as DistortionX and DistortionY, which map them back.
+type CurlNoise struct {
+	Null
+	Seed
+	Frequency
+	Potential image.Image
+	Component CurlComponent
+}
+
+// curlScale brings the derivatives of OpenSimplex2 noise, in noise cells, to
+// roughly [-1, 1].
+const curlScale = 0.14
+
+// curlStep is the distance, in noise cells, over which the derivatives of the
+// potential are measured.
+const curlStep = 1.0 / 64
+
+// VectorAt returns the curl of the potential at (x, y).
+func (c *CurlNoise) VectorAt(x, y float64) (float64, float64) {
+	freq := c.Frequency.Frequency
+	if freq == 0 {
+		freq = 0.02
+	}
+	potential := func(x, y float64) float64 {
+		return openSimplex2(uint64(c.Seed.Seed), x*freq, y*freq)
+	}
+	h := curlStep / freq
+	if c.Potential != nil {
+		field := ScalarFieldOf(c.Potential)
+		potential = func(x, y float64) float64 {
+			return 2*field.ValueAt(x, y) - 1
+		}
+		// An ordinary image is read a whole pixel at a time, so a finer step
+		// would find no change.
+		if _, ok := c.Potential.(ScalarField); !ok && h < 1 {
+			h = 1
+		}
+	}
+	dx := (potential(x+h, y) - potential(x-h, y)) / (2 * h * freq)
+	dy := (potential(x, y+h) - potential(x, y-h)) / (2 * h * freq)
+	return dy * curlScale, -dx * curlScale
+}
+
+// ValueAt returns the Component of the field at (x, y), mapped from [-1, 1] to
+// [0, 1] but not clamped.
+func (c *CurlNoise) ValueAt(x, y float64) float64 {
+	vx, vy := c.VectorAt(x, y)
+	if c.Component == CurlY {
+		vx = vy
+	}
+	return (vx + 1) / 2
+}
+
+func (c *CurlNoise) ColorModel() color.Model {
+	return color.Gray16Model
+}
+
+func (c *CurlNoise) At(x, y int) color.Color {
+	return color.Gray16{Y: uint16(clamp01(c.ValueAt(float64(x), float64(y)))*65535 + 0.5)}
+}
+
+// X returns a copy of the field drawing its X component.
+func (c *CurlNoise) X() image.Image {
+	p := *c
+	p.Component = CurlX
+	return &p
+}
+
+// Y returns a copy of the field drawing its Y component.
+func (c *CurlNoise) Y() image.Image {
+	p := *c
+	p.Component = CurlY
+	return &p
+}
+
+// NewCurlNoise creates a new CurlNoise pattern. The frequency defaults to 0.02.
+func NewCurlNoise(ops ...func(any)) image.Image {
+	p := &CurlNoise{
+		Null: Null{
+			bounds: image.Rect(0, 0, 255, 255),
+		},
+	}
+	p.Frequency.Frequency = 0.02
+	for _, op := range ops {
+		op(p)
+	}
+	return p
+}
+
+// SetCurlComponent sets the component of the field a CurlNoise draws.
+func SetCurlComponent(v CurlComponent) func(any) {
+	return func(i any) {
+		if p, ok := i.(*CurlNoise); ok {
+			p.Component = v
+		}
+	}
+}
+
+// SetCurlPotential sets the image whose curl a CurlNoise is.
+func SetCurlPotential(img image.Image) func(any) {
+	return func(i any) {
+		if p, ok := i.(*CurlNoise); ok {
+			p.Potential = img
+		}
+	}
+}
+
+// Ensure Streamlines implements the image.Image interface.
+var _ image.Image = (*Streamlines)(nil)
+
+// Streamlines draws the flow of a vector field by averaging Source along the
+// streamline through each pixel, Steps points either way, StepSize pixels apart.
+// The streamline is traced at unit speed with the midpoint method, so the length
+// of the smear does not depend on the strength of the field.
+//
+// Over white noise this is line integral convolution, which draws the
+// streamlines themselves; over other images it smears them along the flow as
+// though they had been advected by it.
+//
+// The Field is read with VectorFieldOf the first time the pattern is drawn, so it
+// should not change after that.
+type Streamlines struct {
+	Null
+	Source   image.Image
+	Field    image.Image
+	Steps    int
+	StepSize float64
+
+	once  sync.Once
+	field VectorField
+}
+
+func (s *Streamlines) At(x, y int) color.Color {
+	if s.Source == nil {
+		return color.Transparent
+	}
+	if s.Field == nil {
+		return s.Source.At(x, y)
+	}
+	s.once.Do(func() {
+		s.field = VectorFieldOf(s.Field)
+	})
+	field := s.field
+	// direction returns the unit vector of the field at (x, y), or false where
+	// the field is still.
+	direction := func(x, y float64) (float64, float64, bool) {
+		vx, vy := field.VectorAt(x, y)
+		l := math.Hypot(vx, vy)
+		if l == 0 {
+			return 0, 0, false
+		}
+		return vx / l, vy / l, true
+	}
+
+	var r, g, b, a float64
+	add := func(px, py float64) {
+		cr, cg, cb, ca := sampleImage(s.Source, px, py).RGBA()
+		r, g, b, a = r+float64(cr), g+float64(cg), b+float64(cb), a+float64(ca)
+	}
+	cx, cy := float64(x)+0.5, float64(y)+0.5
+	add(cx, cy)
+	n := 1
+	for _, sign := range []float64{1, -1} {
+		px, py := cx, cy
+		h := sign * s.StepSize
+		for i := 0; i < s.Steps; i++ {
+			dx, dy, ok := direction(px, py)
+			if !ok {
+				break
+			}
+			mx, my, ok := direction(px+dx*h/2, py+dy*h/2)
+			if !ok {
+				break
+			}
+			px, py = px+mx*h, py+my*h
+			add(px, py)
+			n++
+		}
+	}
+	f := float64(n)
+	return color.RGBA64{uint16(r/f + 0.5), uint16(g/f + 0.5), uint16(b/f + 0.5), uint16(a/f + 0.5)}
+}
+
+// NewStreamlines creates a Streamlines pattern drawing source along field. It
+// takes 10 steps of a pixel either way by default.
+func NewStreamlines(source, field image.Image, ops ...func(any)) image.Image {
+	p := &Streamlines{
+		Null: Null{
+			bounds: image.Rect(0, 0, 255, 255),
+		},
+		Source:   source,
+		Field:    field,
+		Steps:    10,
+		StepSize: 1,
+	}
+	if source != nil {
+		p.bounds = source.Bounds()
+	}
+	for _, op := range ops {
+		op(p)
+	}
+	return p
+}
+
+// SetStreamlineSteps sets the number of steps Streamlines takes either way.
+func SetStreamlineSteps(n int) func(any) {
+	return func(i any) {
+		if p, ok := i.(*Streamlines); ok {
+			p.Steps = n
+		}
+	}
+}
+
+// SetStreamlineStepSize sets the length, in pixels, of each step Streamlines takes.
+func SetStreamlineStepSize(v float64) func(any) {
+	return func(i any) {
+		if p, ok := i.(*Streamlines); ok {
+			p.StepSize = v
+		}
+	}
+}
+
+func init() {
+	RegisterPattern(&PatternType{
+		Name:     "curl_noise",
+		Category: CategoryGenerator,
+		Inputs:   []Input{{Name: "potential", Optional: true}},
+		Params: []Param{
+			{Name: "component", Type: ParamEnum, Default: "x", Values: curlComponentNames, Doc: "Component of the field to draw."},
+		},
+		Sample: &CurlNoise{},
+		New: func(a *Args) (image.Image, error) {
+			p := NewCurlNoise().(*CurlNoise)
+			p.Potential = a.Input("potential")
+			p.Component = CurlComponent(a.Enum("component", curlComponentNames))
+			return applyOps(p, a.Options), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			p := img.(*CurlNoise)
+			a.SetInput("potential", p.Potential)
+			a.Set("component", enumName(curlComponentNames, int(p.Component)))
+			return nil
+		},
+	})
+	RegisterPattern(&PatternType{
+		Name:     "streamlines",
+		Category: CategoryFilter,
+		Inputs:   []Input{{Name: "source"}, {Name: "field"}},
+		Params: []Param{
+			{Name: "steps", Type: ParamInt, Default: 10, Min: 0, Max: 1000, Doc: "Steps traced either way along the streamline."},
+			{Name: "step_size", Type: ParamFloat, Default: 1.0, Doc: "Length of each step in pixels."},
+		},
+		Sample: &Streamlines{},
+		New: func(a *Args) (image.Image, error) {
+			p := NewStreamlines(a.Input("source"), a.Input("field")).(*Streamlines)
+			p.Steps = a.Int("steps")
+			p.StepSize = a.Float("step_size")
+			return applyOps(p, a.Options), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			p := img.(*Streamlines)
+			a.SetInput("source", p.Source)
+			a.SetInput("field", p.Field)
+			a.Set("steps", p.Steps)
+			a.Set("step_size", p.StepSize)
+			return nil
+		},
+	})
+}
@@ -0,0 +1,106 @@
+package pattern
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+)
+
+var CurlNoiseOutputFilename = "curl_noise.png"
+var CurlNoiseZoomLevels = []int{}
+
+const CurlNoiseOrder = 22
+const CurlNoiseBaseLabel = "X"
+
+// CurlNoise Pattern
+// Generates a swirling, divergence free vector field from the curl of noise, drawn
+// one component at a time.
+func ExampleNewCurlNoise() {
+	// The X component; SetCurlComponent(CurlY) draws the other
+	i := NewCurlNoise(SetSeed(3), SetFrequency(0.02))
+	f, err := os.Create(CurlNoiseOutputFilename)
+	if err != nil {
+		panic(err)
+	}
+	defer func() {
+		if e := f.Close(); e != nil {
+			panic(e)
+		}
+	}()
+	if err = png.Encode(f, i); err != nil {
+		panic(err)
+	}
+}
+
+func GenerateCurlNoise(b image.Rectangle) image.Image {
+	return NewCurlNoise(SetBounds(b), SetSeed(3), SetFrequency(0.02))
+}
+
+func GenerateCurlNoiseReferences() (map[string]func(image.Rectangle) image.Image, []string) {
+	return map[string]func(image.Rectangle) image.Image{
+		"Y": func(b image.Rectangle) image.Image {
+			return NewCurlNoise(SetBounds(b), SetSeed(3), SetFrequency(0.02), SetCurlComponent(CurlY))
+		},
+		"Warped": func(b image.Rectangle) image.Image {
+			// The components displace a checkerboard as Warp's X and Y distortion.
+			curl := NewCurlNoise(SetSeed(3), SetFrequency(0.02)).(*CurlNoise)
+			checker := NewChecker(color.RGBA{200, 200, 200, 255}, color.RGBA{50, 50, 50, 255}, SetSpaceSize(15), SetBounds(b))
+			return NewWarp(checker, WarpDistortionX(curl.X()), WarpDistortionY(curl.Y()), WarpScale(15), SetBounds(b))
+		},
+	}, []string{"Y", "Warped"}
+}
+
+var StreamlinesOutputFilename = "streamlines.png"
+var StreamlinesZoomLevels = []int{}
+
+const StreamlinesOrder = 23
+const StreamlinesBaseLabel = "Streamlines"
+
+// Streamlines Pattern
+// Draws the flow of a vector field by smearing an image along its streamlines.
+// Over white noise, as here, this is line integral convolution.
+func ExampleNewStreamlines() {
+	noise := NewNoise(NoiseSeed(1))
+	flow := NewCurlNoise(SetSeed(3), SetFrequency(0.02))
+	i := NewStreamlines(noise, flow, SetStreamlineSteps(12))
+	f, err := os.Create(StreamlinesOutputFilename)
+	if err != nil {
+		panic(err)
+	}
+	defer func() {
+		if e := f.Close(); e != nil {
+			panic(e)
+		}
+	}()
+	if err = png.Encode(f, i); err != nil {
+		panic(err)
+	}
+}
+
+func GenerateStreamlines(b image.Rectangle) image.Image {
+	noise := NewNoise(SetBounds(b), NoiseSeed(1))
+	flow := NewCurlNoise(SetBounds(b), SetSeed(3), SetFrequency(0.02))
+	return NewStreamlines(noise, flow, SetStreamlineSteps(12), SetBounds(b))
+}
+
+func GenerateStreamlinesReferences() (map[string]func(image.Rectangle) image.Image, []string) {
+	return map[string]func(image.Rectangle) image.Image{
+		"Noise": func(b image.Rectangle) image.Image {
+			return NewNoise(SetBounds(b), NoiseSeed(1))
+		},
+		"Smeared": func(b image.Rectangle) image.Image {
+			// Over an ordinary image the flow smears it as though advected.
+			checker := NewChecker(color.RGBA{200, 60, 40, 255}, color.RGBA{240, 220, 160, 255}, SetSpaceSize(15), SetBounds(b))
+			flow := NewCurlNoise(SetBounds(b), SetSeed(3), SetFrequency(0.02))
+			return NewStreamlines(checker, flow, SetStreamlineSteps(20), SetBounds(b))
+		},
+	}, []string{"Noise", "Smeared"}
+}
+
+func init() {
+	RegisterGenerator("CurlNoise", GenerateCurlNoise)
+	RegisterReferences("CurlNoise", GenerateCurlNoiseReferences)
+	RegisterGenerator("Streamlines", GenerateStreamlines)
+	RegisterReferences("Streamlines", GenerateStreamlinesReferences)
+}
@@ -0,0 +1,105 @@
+package pattern
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestCurlNoiseDivergenceFree(t *testing.T) {
+	c := NewCurlNoise(SetSeed(3)).(*CurlNoise)
+	// Differences over the step the curl is measured with cancel exactly.
+	const h = curlStep / 0.02
+	var largest float64
+	for y := 0.0; y < 200; y += 13.7 {
+		for x := 0.0; x < 200; x += 11.3 {
+			vx1, _ := c.VectorAt(x+h, y)
+			vx0, _ := c.VectorAt(x-h, y)
+			_, vy1 := c.VectorAt(x, y+h)
+			_, vy0 := c.VectorAt(x, y-h)
+			div := (vx1-vx0)/(2*h) + (vy1-vy0)/(2*h)
+			if math.Abs(div) > 1e-9 {
+				t.Fatalf("divergence at (%v, %v) = %v", x, y, div)
+			}
+			vx, vy := c.VectorAt(x, y)
+			largest = math.Max(largest, math.Hypot(vx, vy))
+		}
+	}
+	if largest < 0.1 || largest > 2 {
+		t.Errorf("largest speed = %v, want roughly 1", largest)
+	}
+}
+
+func TestCurlNoiseComponents(t *testing.T) {
+	c := NewCurlNoise(SetSeed(8), SetFrequency(0.05)).(*CurlNoise)
+	x, y := c.X().(ScalarField), c.Y().(ScalarField)
+	for _, p := range [][2]float64{{0, 0}, {12.5, 40}, {100, 3}} {
+		vx, vy := c.VectorAt(p[0], p[1])
+		if got := 2*x.ValueAt(p[0], p[1]) - 1; math.Abs(got-vx) > 1e-12 {
+			t.Errorf("X at %v = %v, want %v", p, got, vx)
+		}
+		if got := 2*y.ValueAt(p[0], p[1]) - 1; math.Abs(got-vy) > 1e-12 {
+			t.Errorf("Y at %v = %v, want %v", p, got, vy)
+		}
+	}
+}
+
+func TestCurlNoiseImagePotential(t *testing.T) {
+	// A ramp rising one grey level a pixel, read as an ordinary image.
+	ramp := image.NewGray(image.Rect(0, 0, 256, 16))
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 256; x++ {
+			ramp.SetGray(x, y, color.Gray{Y: uint8(x)})
+		}
+	}
+	for _, freq := range []float64{0.02, 0.1, 0.5} {
+		c := NewCurlNoise(SetFrequency(freq)).(*CurlNoise)
+		c.Potential = ramp
+		want := -2.0 / 255 / freq * curlScale
+		for _, x := range []float64{10, 100.5, 200.25} {
+			vx, vy := c.VectorAt(x, 8)
+			if math.Abs(vx) > 1e-12 || math.Abs(vy-want) > 1e-9 {
+				t.Errorf("frequency %v: curl at (%v, 8) = (%v, %v), want (0, %v)", freq, x, vx, vy, want)
+			}
+		}
+	}
+}
+
+func TestStreamlines(t *testing.T) {
+	b := image.Rect(0, 0, 40, 40)
+	// The flow map points along x everywhere.
+	flow := NewRect(SetFillColor(color.RGBA{255, 128, 0, 255}), SetBounds(b))
+	vertical := NewVerticalLine(SetLineSize(1), SetSpaceSize(1), SetLineColor(color.White), SetSpaceColor(color.Black), SetBounds(b))
+	horizontal := NewHorizontalLine(SetLineSize(1), SetSpaceSize(1), SetLineColor(color.White), SetSpaceColor(color.Black), SetBounds(b))
+
+	// Stripes across the flow are smeared to grey; stripes along it are kept.
+	across := NewStreamlines(vertical, flow, SetStreamlineSteps(8))
+	along := NewStreamlines(horizontal, flow, SetStreamlineSteps(8))
+	for x := 10; x < 30; x++ {
+		if g := color.GrayModel.Convert(across.At(x, 20)).(color.Gray).Y; g < 100 || g > 155 {
+			t.Errorf("across the flow at x=%d = %d, want grey", x, g)
+		}
+	}
+	for y := 10; y < 30; y++ {
+		if !sameColor(along.At(20, y), horizontal.At(20, y)) {
+			t.Errorf("along the flow at y=%d = %v, want %v", y, along.At(20, y), horizontal.At(20, y))
+		}
+	}
+}
+
+func TestCurlNoiseGraph(t *testing.T) {
+	b := image.Rect(0, 0, 32, 32)
+	curl := NewCurlNoise(SetSeed(2), SetBounds(b))
+	img := NewStreamlines(NewNoise(SetNoiseAlgorithm(&HashNoise{Seed: 1}), SetBounds(b)), curl, SetStreamlineSteps(4))
+	var buf bytes.Buffer
+	if err := SaveGraph(&buf, img); err != nil {
+		t.Fatalf("SaveGraph failed: %v", err)
+	}
+	loaded, err := LoadGraph(&buf)
+	if err != nil {
+		t.Fatalf("LoadGraph failed: %v", err)
+	}
+	sameImage(t, img, loaded)
+}
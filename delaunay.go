@@ -0,0 +1,167 @@
+package pattern
+
+import (
+	"image"
+	"math"
+)
+
+// Triangle is a triangle of a triangulation, given by the indices of its corners
+// in the points triangulated.
+type Triangle [3]int
+
+// Delaunay triangulates points so that no point lies inside the circumcircle of
+// any triangle, which makes the triangles as close to equilateral as the points
+// allow. It is the dual of the unweighted Euclidean Voronoi diagram of the same
+// points: two points share an edge where their cells share a border.
+//
+// The triangles cover the convex hull of the points, each wound clockwise on
+// screen, where y grows downwards. Repeated points are triangulated once, at their
+// first index. Fewer than three distinct points, or points all on one line, give
+// no triangles.
+func Delaunay(points []image.Point) []Triangle {
+	if len(points) < 3 {
+		return nil
+	}
+	n := len(points)
+	xs := make([]float64, n)
+	ys := make([]float64, n)
+	for i, p := range points {
+		xs[i], ys[i] = float64(p.X), float64(p.Y)
+	}
+	orient := func(a, b int, p image.Point) int64 {
+		pa, pb := points[a], points[b]
+		return int64(pb.X-pa.X)*int64(p.Y-pa.Y) - int64(pb.Y-pa.Y)*int64(p.X-pa.X)
+	}
+	// The points are triangulated one at a time by the Bowyer-Watson algorithm.
+	// Each edge of the convex hull so far also makes a triangle with a point at
+	// infinity, whose circumcircle is the open half-plane beyond the edge, so
+	// points outside the hull are joined to it like those inside, and the hull is
+	// whole however thin its triangles.
+	var order []int
+	seen := make(map[image.Point]bool, n)
+	for i, p := range points {
+		if !seen[p] {
+			seen[p] = true
+			order = append(order, i)
+		}
+	}
+	if len(order) < 3 {
+		return nil
+	}
+	a, b := order[0], order[1]
+	first := -1
+	for k, c := range order[2:] {
+		if orient(a, b, points[c]) != 0 {
+			first = k + 2
+			break
+		}
+	}
+	if first < 0 {
+		return nil
+	}
+	c := order[first]
+	order = append(order[2:first], order[first+1:]...)
+	if orient(a, b, points[c]) < 0 {
+		a, b = b, a
+	}
+	const inf = -1
+
+	type triangle struct {
+		t      Triangle
+		cx, cy float64 // Circumcentre.
+		r2     float64 // Squared circumradius.
+	}
+	makeTriangle := func(a, b, c int) triangle {
+		// A triangle with the point at infinity keeps it last, with the edge of
+		// the hull before it.
+		switch inf {
+		case a:
+			return triangle{t: Triangle{b, c, inf}}
+		case b:
+			return triangle{t: Triangle{c, a, inf}}
+		case c:
+			return triangle{t: Triangle{a, b, inf}}
+		}
+		ax, ay := xs[a], ys[a]
+		bx, by := xs[b]-ax, ys[b]-ay
+		cx, cy := xs[c]-ax, ys[c]-ay
+		d := 2 * (bx*cy - by*cx)
+		if d == 0 {
+			// Degenerate triangles hold every point, so they are replaced as
+			// soon as another is added.
+			return triangle{t: Triangle{a, b, c}, r2: math.Inf(1)}
+		}
+		b2, c2 := bx*bx+by*by, cx*cx+cy*cy
+		ux := (cy*b2 - by*c2) / d
+		uy := (bx*c2 - cx*b2) / d
+		return triangle{t: Triangle{a, b, c}, cx: ax + ux, cy: ay + uy, r2: ux*ux + uy*uy}
+	}
+	// holds reports whether the circumcircle of t holds p. The corners of every
+	// triangle are wound the same way round, so the outside of a hull edge a, b
+	// is on the same side as the third corner of a triangle.
+	holds := func(t triangle, p image.Point) bool {
+		if t.t[2] != inf {
+			dx, dy := float64(p.X)-t.cx, float64(p.Y)-t.cy
+			return dx*dx+dy*dy < t.r2
+		}
+		a, b := t.t[0], t.t[1]
+		if o := orient(a, b, p); o != 0 {
+			return o > 0
+		}
+		// A point on the line of the edge is held only between its ends.
+		pa, pb := points[a], points[b]
+		return (p.X-pa.X)*(p.X-pb.X)+(p.Y-pa.Y)*(p.Y-pb.Y) < 0
+	}
+
+	triangles := []triangle{
+		makeTriangle(a, b, c),
+		makeTriangle(b, a, inf),
+		makeTriangle(c, b, inf),
+		makeTriangle(a, c, inf),
+	}
+	type edge [2]int
+	for _, i := range order {
+		p := points[i]
+		// The triangles whose circumcircles hold the point are removed, leaving a
+		// hole whose border is joined to it. New triangles keep the winding of
+		// those they replace.
+		var border []edge
+		count := map[edge]int{}
+		kept := triangles[:0]
+		for _, t := range triangles {
+			if !holds(t, p) {
+				kept = append(kept, t)
+				continue
+			}
+			for k := 0; k < 3; k++ {
+				a, b := t.t[k], t.t[(k+1)%3]
+				e := edge{a, b}
+				if a > b {
+					e = edge{b, a}
+				}
+				if count[e] == 0 {
+					border = append(border, edge{a, b})
+				}
+				count[e]++
+			}
+		}
+		triangles = kept
+		for _, e := range border {
+			key := e
+			if key[0] > key[1] {
+				key = edge{key[1], key[0]}
+			}
+			if count[key] == 1 {
+				triangles = append(triangles, makeTriangle(e[0], e[1], i))
+			}
+		}
+	}
+
+	var result []Triangle
+	for _, t := range triangles {
+		if t.t[2] != inf && !math.IsInf(t.r2, 1) {
+			result = append(result, t.t)
+		}
+	}
+	return result
+}
@@ -0,0 +1,137 @@
+package pattern
+
+import (
+	"image"
+	"sort"
+	"testing"
+)
+
+// triangleArea returns twice the signed area of t.
+func triangleArea(points []image.Point, t Triangle) int {
+	a, b, c := points[t[0]], points[t[1]], points[t[2]]
+	return (b.X-a.X)*(c.Y-a.Y) - (b.Y-a.Y)*(c.X-a.X)
+}
+
+// hullArea returns twice the area of the convex hull of points, found by the
+// monotone chain.
+func hullArea(points []image.Point) int {
+	sorted := append([]image.Point(nil), points...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].X != sorted[j].X {
+			return sorted[i].X < sorted[j].X
+		}
+		return sorted[i].Y < sorted[j].Y
+	})
+	cross := func(o, a, b image.Point) int {
+		return (a.X-o.X)*(b.Y-o.Y) - (a.Y-o.Y)*(b.X-o.X)
+	}
+	var hull []image.Point
+	for pass := 0; pass < 2; pass++ {
+		start := len(hull)
+		for _, p := range sorted {
+			for len(hull) >= start+2 && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+				hull = hull[:len(hull)-1]
+			}
+			hull = append(hull, p)
+		}
+		hull = hull[:len(hull)-1]
+		for i, j := 0, len(sorted)-1; i < j; i, j = i+1, j-1 {
+			sorted[i], sorted[j] = sorted[j], sorted[i]
+		}
+	}
+	area := 0
+	for i, p := range hull {
+		q := hull[(i+1)%len(hull)]
+		area += p.X*q.Y - p.Y*q.X
+	}
+	if area < 0 {
+		area = -area
+	}
+	return area
+}
+
+// scatteredPoints returns n points hashed into a w by h rectangle.
+func scatteredPoints(seed uint64, n, w, h int) []image.Point {
+	points := make([]image.Point, n)
+	for i := range points {
+		v := StableHash(i, int(seed), 23)
+		points[i] = image.Point{int(v % uint64(w)), int(v >> 32 % uint64(h))}
+	}
+	return points
+}
+
+func TestDelaunay(t *testing.T) {
+	tests := map[string][]image.Point{
+		"random":  randomSites(300),
+		"poisson": PoissonDiskPoints(image.Rect(0, 0, 200, 200), 12, 4, nil),
+		// A grid is full of points on the same circle.
+		"grid":       JitteredGridPoints(image.Rect(0, 0, 100, 100), 10, 0, 0),
+		"duplicates": append(randomSites(50), randomSites(20)...),
+		// Long thin sets have hull triangles with vast circumcircles.
+		"thin": scatteredPoints(0, 40, 1000, 30),
+	}
+	for name, points := range tests {
+		triangles := Delaunay(points)
+		if len(triangles) == 0 {
+			t.Fatalf("%s: no triangles", name)
+		}
+		edges := map[[2]int]int{}
+		for _, tri := range triangles {
+			if triangleArea(points, tri) <= 0 {
+				t.Fatalf("%s: triangle %v is not wound clockwise on screen", name, tri)
+			}
+			for k := 0; k < 3; k++ {
+				edges[[2]int{tri[k], tri[(k+1)%3]}]++
+			}
+			// No point may lie inside the circumcircle.
+			a, b, c := points[tri[0]], points[tri[1]], points[tri[2]]
+			for _, p := range points {
+				ax, ay := float64(a.X-p.X), float64(a.Y-p.Y)
+				bx, by := float64(b.X-p.X), float64(b.Y-p.Y)
+				cx, cy := float64(c.X-p.X), float64(c.Y-p.Y)
+				det := (ax*ax+ay*ay)*(bx*cy-cx*by) - (bx*bx+by*by)*(ax*cy-cx*ay) + (cx*cx+cy*cy)*(ax*by-bx*ay)
+				if det > 1e-6 {
+					t.Fatalf("%s: %v lies inside the circumcircle of %v", name, p, tri)
+				}
+			}
+		}
+		// Each edge is crossed once each way at most, so the triangles do not
+		// overlap.
+		for e, n := range edges {
+			if n > 1 {
+				t.Fatalf("%s: edge %v is used %d times the same way", name, e, n)
+			}
+		}
+	}
+
+	// The triangles of a grid tile the square.
+	grid := tests["grid"]
+	area := 0
+	for _, tri := range Delaunay(grid) {
+		area += triangleArea(grid, tri)
+	}
+	if area != 2*90*90 {
+		t.Errorf("grid triangles cover %v, want %v", float64(area)/2, 90*90)
+	}
+
+	// The triangles cover the convex hull of the points, however thin the
+	// triangles along its edges.
+	for seed := uint64(0); seed < 300; seed++ {
+		for _, points := range [][]image.Point{
+			scatteredPoints(seed, 40, 500, 500),
+			scatteredPoints(seed, 40, 1000, 30),
+		} {
+			area := 0
+			for _, tri := range Delaunay(points) {
+				area += triangleArea(points, tri)
+			}
+			if want := hullArea(points); area != want {
+				t.Fatalf("seed %d: triangles cover %v, want the hull's %v", seed, float64(area)/2, float64(want)/2)
+			}
+		}
+	}
+
+	if got := Delaunay([]image.Point{{0, 0}, {5, 5}, {10, 10}}); len(got) != 0 {
+		t.Errorf("collinear points gave %v, want no triangles", got)
+	}
+}
@@ -1,9 +1,11 @@
 package pattern
 
 import (
+	"errors"
 	"image"
 	"image/color"
 	"math"
+	"reflect"
 	"sync"
 )
 
@@ -469,3 +471,64 @@ func clamp(v float64) uint8 {
 	}
 	return uint8(v)
 }
+
+// paletteParam is the palette parameter of the registered dithers. Each dither falls
+// back to black and white when it is unset.
+var paletteParam = Param{Name: "palette", Type: ParamColors, Doc: "Colours to reduce to; black and white when unset."}
+
+// diffusionKernelNames names the predefined kernels in the pattern registry.
+var diffusionKernelNames = []string{
+	"floyd_steinberg", "jarvis_judice_ninke", "stucki", "atkinson", "burkes",
+	"sierra_lite", "sierra2", "sierra3", "stevenson_arce",
+}
+
+func diffusionKernels() []DiffusionKernel {
+	return []DiffusionKernel{FloydSteinberg, JarvisJudiceNinke, Stucki, Atkinson, Burkes, SierraLite, Sierra2, Sierra3, StevensonArce}
+}
+
+func init() {
+	RegisterPattern(&PatternType{
+		Name:     "bayer_dither",
+		Category: CategoryDither,
+		Inputs:   []Input{{Name: "source"}},
+		Params: []Param{
+			{Name: "size", Type: ParamInt, Default: 2, Min: 2, Max: 4, Doc: "Matrix size: 2 or 4."},
+		},
+		Sample: &BayerDither{},
+		New: func(a *Args) (image.Image, error) {
+			return NewBayerDither(a.Input("source"), a.Int("size"), a.Options...), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			p := img.(*BayerDither)
+			a.SetInput("source", p.Input)
+			a.Set("size", p.Size)
+			return nil
+		},
+	})
+	RegisterPattern(&PatternType{
+		Name:     "error_diffusion",
+		Category: CategoryDither,
+		Inputs:   []Input{{Name: "source"}},
+		Params: []Param{
+			{Name: "kernel", Type: ParamEnum, Default: "floyd_steinberg", Values: diffusionKernelNames, Doc: "Error diffusion kernel."},
+			paletteParam,
+		},
+		Sample: &ErrorDiffusion{},
+		New: func(a *Args) (image.Image, error) {
+			kernel := diffusionKernels()[a.Enum("kernel", diffusionKernelNames)]
+			return NewErrorDiffusion(a.Input("source"), kernel, a.Colors("palette"), a.Options...), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			p := img.(*ErrorDiffusion)
+			a.SetInput("source", p.img)
+			a.Set("palette", []color.Color(p.palette))
+			for i, k := range diffusionKernels() {
+				if reflect.DeepEqual(k, p.kernel) {
+					a.Set("kernel", diffusionKernelNames[i])
+					return nil
+				}
+			}
+			return errors.New("custom diffusion kernels cannot be serialised")
+		},
+	})
+}
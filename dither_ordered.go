@@ -1,10 +1,10 @@
 package pattern
 
 import (
+	"fmt"
 	"image"
 	"image/color"
 	"math"
-	"math/rand"
 )
 
 // OrderedDither applies ordered dithering using a threshold matrix.
@@ -281,80 +281,16 @@ func (d *RandomDither) At(x, y int) color.Color {
 
 // --- Blue Noise ---
 
-// generateBlueNoise generates a small (16x16) blue noise matrix using a simplified best-candidate algorithm.
-func generateBlueNoise(size int) []float64 {
-	if size > 32 {
-		size = 32
-	}
-	width := size
-	height := size
-
-	ranks := make([]int, width*height)
-	for i := range ranks {
-		ranks[i] = -1
-	}
-
-	// Pick first point
-	r := rand.New(rand.NewSource(1))
-	first := r.Intn(width * height)
-	ranks[first] = 0
-
-	points := []struct{ x, y int }{{first % width, first / width}}
-
-	for k := 1; k < width*height; k++ {
-		bestDist := -1.0
-		bestIdx := -1
-
-		// Check all candidates (pixels with rank -1)
-		for i := 0; i < width*height; i++ {
-			if ranks[i] != -1 {
-				continue
-			}
-			px, py := i%width, i/width
-
-			minDist := 1000000.0
-			for _, pt := range points {
-				// Toroidal distance
-				dx := float64(abs(px - pt.x))
-				dy := float64(abs(py - pt.y))
-				if dx > float64(width)/2 {
-					dx = float64(width) - dx
-				}
-				if dy > float64(height)/2 {
-					dy = float64(height) - dy
-				}
-				d := dx*dx + dy*dy
-				if d < minDist {
-					minDist = d
-				}
-			}
-
-			if minDist > bestDist {
-				bestDist = minDist
-				bestIdx = i
-			}
-		}
-
-		ranks[bestIdx] = k
-		points = append(points, struct{ x, y int }{bestIdx % width, bestIdx / width})
-	}
-
-	return normalizeMatrix(ranks)
-}
-
-func abs(x int) int {
-	if x < 0 {
-		return -x
-	}
-	return x
-}
-
 // NewBlueNoiseDither creates a blue noise dither pattern.
-// Uses a generated 16x16 Blue Noise mask.
+// Uses a 64x64 void-and-cluster blue noise mask from BlueNoiseMask.
 func NewBlueNoiseDither(img image.Image, palette color.Palette, ops ...func(any)) image.Image {
-	size := 16
-	matrix := generateBlueNoise(size)
-	return NewOrderedDither(img, matrix, size, palette, 0, ops...)
+	size := 64
+	mask := BlueNoiseMask(size, 1)
+	ranks := make([]int, len(mask))
+	for i, rank := range mask {
+		ranks[i] = int(rank)
+	}
+	return NewOrderedDither(img, normalizeMatrix(ranks), size, palette, 0, ops...)
 }
 
 // --- Multi-Scale Ordered Dither ---
@@ -452,3 +388,101 @@ func (d *MultiScaleOrderedDither) At(x, y int) color.Color {
 
 	return d.palette.Convert(target)
 }
+
+// orderedDitherType describes a dither to a palette with a fixed threshold matrix.
+// The ordered dithers share the OrderedDither type, so they are not given a Sample
+// and cannot be saved as graphs.
+func orderedDitherType(name string, newFn func(img image.Image, palette color.Palette, ops ...func(any)) image.Image) *PatternType {
+	return &PatternType{
+		Name:     name,
+		Category: CategoryDither,
+		Inputs:   []Input{{Name: "source"}},
+		Params:   []Param{paletteParam},
+		New: func(a *Args) (image.Image, error) {
+			return newFn(a.Input("source"), a.Colors("palette"), a.Options...), nil
+		},
+	}
+}
+
+func init() {
+	RegisterPattern(orderedDitherType("bayer2x2_dither", NewBayer2x2Dither))
+	RegisterPattern(orderedDitherType("bayer4x4_dither", NewBayer4x4Dither))
+	RegisterPattern(orderedDitherType("bayer8x8_dither", NewBayer8x8Dither))
+	RegisterPattern(orderedDitherType("blue_noise_dither", NewBlueNoiseDither))
+	RegisterPattern(&PatternType{
+		Name:     "ordered_dither",
+		Category: CategoryDither,
+		Inputs:   []Input{{Name: "source"}},
+		Params: []Param{
+			{Name: "matrix", Type: ParamFloats, Default: Bayer2x2, Doc: "Thresholds from 0 to 1, row by row."},
+			{Name: "dim", Type: ParamInt, Default: 2, Min: 1, Max: 64, Doc: "Width and height of the matrix."},
+			paletteParam,
+			{Name: "spread", Type: ParamFloat, Default: 0.0, Min: 0, Max: 255, Doc: "Strength of the dithering; 0 picks one for the palette size."},
+		},
+		Sample: &OrderedDither{},
+		New: func(a *Args) (image.Image, error) {
+			matrix, dim := a.Floats("matrix"), a.Int("dim")
+			if len(matrix) != dim*dim {
+				return nil, fmt.Errorf("a matrix of %d values is not %d by %d", len(matrix), dim, dim)
+			}
+			return NewOrderedDither(a.Input("source"), matrix, dim, a.Colors("palette"), a.Float("spread"), a.Options...), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			p := img.(*OrderedDither)
+			a.SetInput("source", p.img)
+			a.Set("matrix", p.matrix)
+			a.Set("dim", p.dim)
+			a.Set("palette", []color.Color(p.palette))
+			a.Set("spread", p.spread)
+			return nil
+		},
+	})
+	RegisterPattern(&PatternType{
+		Name:     "halftone_dither",
+		Category: CategoryDither,
+		Inputs:   []Input{{Name: "source"}},
+		Params: []Param{
+			{Name: "size", Type: ParamInt, Default: 4, Min: 2, Max: 64, Doc: "Size of the dot grid."},
+			paletteParam,
+		},
+		New: func(a *Args) (image.Image, error) {
+			return NewHalftoneDither(a.Input("source"), a.Int("size"), a.Colors("palette"), a.Options...), nil
+		},
+	})
+	RegisterPattern(&PatternType{
+		Name:     "random_dither",
+		Category: CategoryDither,
+		Inputs:   []Input{{Name: "source"}},
+		Params: []Param{
+			paletteParam,
+			{Name: "seed", Type: ParamInt, Default: 0, Doc: "Seed of the noise."},
+		},
+		Sample: &RandomDither{},
+		New: func(a *Args) (image.Image, error) {
+			return NewRandomDither(a.Input("source"), a.Colors("palette"), int64(a.Int("seed")), a.Options...), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			p := img.(*RandomDither)
+			a.SetInput("source", p.img)
+			a.Set("palette", []color.Color(p.palette))
+			a.Set("seed", int(p.seed))
+			return nil
+		},
+	})
+	RegisterPattern(&PatternType{
+		Name:     "multi_scale_ordered_dither",
+		Category: CategoryDither,
+		Inputs:   []Input{{Name: "source"}},
+		Params:   []Param{paletteParam},
+		Sample:   &MultiScaleOrderedDither{},
+		New: func(a *Args) (image.Image, error) {
+			return NewMultiScaleOrderedDither(a.Input("source"), a.Colors("palette"), a.Options...), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			p := img.(*MultiScaleOrderedDither)
+			a.SetInput("source", p.img)
+			a.Set("palette", []color.Color(p.palette))
+			return nil
+		},
+	})
+}
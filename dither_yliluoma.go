@@ -559,3 +559,43 @@ func (p *KnollDither) getLuma(idx int) float64 {
 	r, g, b, _ := c.RGBA()
 	return float64(r)*0.299 + float64(g)*0.587 + float64(b)*0.114
 }
+
+// matrixDitherType describes a dither to a palette with a threshold matrix of the
+// given size, reading the source, palette and size back with fields.
+func matrixDitherType(name string, sample image.Image, newFn func(img image.Image, palette color.Palette, size int, ops ...func(any)) image.Image, fields func(img image.Image) (image.Image, []color.Color, int)) *PatternType {
+	return &PatternType{
+		Name:     name,
+		Category: CategoryDither,
+		Inputs:   []Input{{Name: "source"}},
+		Params: []Param{
+			paletteParam,
+			{Name: "size", Type: ParamInt, Default: 8, Min: 2, Max: 8, Doc: "Matrix size: 2, 4 or 8."},
+		},
+		Sample: sample,
+		New: func(a *Args) (image.Image, error) {
+			return newFn(a.Input("source"), a.Colors("palette"), a.Int("size"), a.Options...), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			source, palette, size := fields(img)
+			a.SetInput("source", source)
+			a.Set("palette", palette)
+			a.Set("size", size)
+			return nil
+		},
+	}
+}
+
+func init() {
+	RegisterPattern(matrixDitherType("yliluoma1_dither", &Yliluoma1Dither{}, NewYliluoma1Dither, func(img image.Image) (image.Image, []color.Color, int) {
+		p := img.(*Yliluoma1Dither)
+		return p.Input, p.Palette, p.Size
+	}))
+	RegisterPattern(matrixDitherType("yliluoma2_dither", &Yliluoma2Dither{}, NewYliluoma2Dither, func(img image.Image) (image.Image, []color.Color, int) {
+		p := img.(*Yliluoma2Dither)
+		return p.Input, p.Palette, p.Size
+	}))
+	RegisterPattern(matrixDitherType("knoll_dither", &KnollDither{}, NewKnollDither, func(img image.Image) (image.Image, []color.Color, int) {
+		p := img.(*KnollDither)
+		return p.Input, p.Palette, p.Size
+	}))
+}
@@ -0,0 +1,50 @@
+package pattern
+
+import (
+	"bytes"
+	"image"
+	"math"
+	"testing"
+)
+
+func TestDomainWarpNoise(t *testing.T) {
+	base := &OpenSimplexNoise{Seed: 6}
+	// With no strength the warp leaves the base as it is.
+	still := &DomainWarpNoise{Base: base, Depth: 2, Strengths: []float64{0.0001, 0}}
+	for x := 0.0; x < 100; x += 7 {
+		if got, want := still.ValueAt(x, 9), base.ValueAt(x, 9); math.Abs(got-want) > 1e-3 {
+			t.Fatalf("unwarped value at (%v, 9) = %v, want %v", x, got, want)
+		}
+	}
+
+	// One level moves the base by the warp found at the point.
+	one := &DomainWarpNoise{Base: base, Depth: 1, Strengths: []float64{30}}
+	for x := 0.0; x < 100; x += 7 {
+		v, wx, wy := one.Warp(x, 9)
+		if want := base.ValueAt(x+30*wx, 9+30*wy); v != want {
+			t.Fatalf("value at (%v, 9) = %v, want %v", x, v, want)
+		}
+		if wx < -1 || wx > 1 || wy < -1 || wy > 1 {
+			t.Fatalf("warp at (%v, 9) = (%v, %v), outside [-1, 1]", x, wx, wy)
+		}
+	}
+
+	deep := &DomainWarpNoise{Base: base, Depth: 3}
+	if deep.ValueAt(20, 20) == one.ValueAt(20, 20) {
+		t.Error("deeper warps give the same value")
+	}
+}
+
+func TestDomainWarpNoiseGraph(t *testing.T) {
+	b := image.Rect(0, 0, 32, 24)
+	img := NewNoise(SetBounds(b), SetNoiseAlgorithm(&DomainWarpNoise{Depth: 2, Strengths: []float64{40, 20}}), SetSeed(3))
+	var buf bytes.Buffer
+	if err := SaveGraph(&buf, img); err != nil {
+		t.Fatalf("SaveGraph failed: %v", err)
+	}
+	loaded, err := LoadGraph(&buf)
+	if err != nil {
+		t.Fatalf("LoadGraph failed: %v\n%s", err, buf.String())
+	}
+	sameImage(t, img, loaded)
+}
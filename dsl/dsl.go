@@ -1,45 +1,367 @@
 package dsl
 
 import (
+	"errors"
+	"fmt"
+	"image"
 	"strings"
 )
 
+// Arg is a command argument. Named arguments, written name=value, have a Name.
+// An argument is either a Value, a $name reference to a variable or a
+// parenthesised sub-pipeline; the last two are images.
+type Arg struct {
+	Name  string
+	Value string
+	// Var is the variable referenced by a $name argument.
+	Var string
+	// Sub is a parenthesised pipeline, run without an input.
+	Sub *Pipeline
+	// Image is what a Var or Sub argument evaluated to. The interpreter sets it
+	// before calling the command.
+	Image image.Image
+}
+
+// IsImage reports whether the argument is an image rather than a value.
+func (a Arg) IsImage() bool {
+	return a.Var != "" || a.Sub != nil || a.Image != nil
+}
+
+func (a Arg) String() string {
+	var v string
+	switch {
+	case a.Var != "":
+		v = "$" + a.Var
+	case a.Sub != nil:
+		v = "(" + a.Sub.String() + ")"
+	default:
+		v = quote(a.Value)
+	}
+	if a.Name != "" {
+		return a.Name + "=" + v
+	}
+	return v
+}
+
+// Args are the arguments of a command, in the order given.
+type Args []Arg
+
+// Positional returns the values of args, failing if any of them are named or
+// images.
+func (a Args) Positional() ([]string, error) {
+	values, images, err := a.Split()
+	if err != nil {
+		return nil, err
+	}
+	if len(images) > 0 {
+		return nil, errors.New("unexpected image argument")
+	}
+	return values, nil
+}
+
+// Split returns the values and the images of args, each in order, failing if
+// any of them are named.
+func (a Args) Split() ([]string, []image.Image, error) {
+	var values []string
+	var images []image.Image
+	for _, arg := range a {
+		if arg.Name != "" {
+			return nil, nil, fmt.Errorf("unexpected named argument %s", arg.Name)
+		}
+		if arg.IsImage() {
+			images = append(images, arg.Image)
+		} else {
+			values = append(values, arg.Value)
+		}
+	}
+	return values, images, nil
+}
+
+// SplitNamed separates the positional arguments from the named ones.
+func (a Args) SplitNamed() (positional, named Args) {
+	for _, arg := range a {
+		if arg.Name == "" {
+			positional = append(positional, arg)
+		} else {
+			named = append(named, arg)
+		}
+	}
+	return positional, named
+}
+
+// Lookup returns the value of the named argument.
+func (a Args) Lookup(name string) (string, bool) {
+	for _, arg := range a {
+		if arg.Name == name {
+			return arg.Value, true
+		}
+	}
+	return "", false
+}
+
 type Command struct {
 	Name string
-	Args []string
+	Args Args
+	// Var is set instead of Name when a pipeline starts with a $name reference,
+	// which outputs the variable's image.
+	Var string
+	// Pos is where the command starts in the script it was parsed from.
+	Pos Pos
+}
+
+func (c Command) String() string {
+	if c.Var != "" {
+		return "$" + c.Var
+	}
+	parts := []string{c.Name}
+	for _, arg := range c.Args {
+		parts = append(parts, arg.String())
+	}
+	return strings.Join(parts, " ")
 }
 
 type Pipeline []Command
 
+// Statement is a pipeline in a script. A let statement, written
+// let name = pipeline, binds the result to a variable.
+type Statement struct {
+	Let      string
+	Pipeline Pipeline
+	Pos      Pos
+}
+
+func (s Statement) String() string {
+	if s.Let != "" {
+		return "let " + s.Let + " = " + s.Pipeline.String()
+	}
+	return s.Pipeline.String()
+}
+
+// Script is a sequence of statements, written one per line or separated by ';'.
+type Script []Statement
+
+// Parse parses a single pipeline of commands separated by '|'.
 func Parse(input string) (Pipeline, error) {
-	var p Pipeline
-	parts := strings.Split(input, "|")
-	for _, part := range parts {
-		trimmed := strings.TrimSpace(part)
-		if trimmed == "" {
-			continue
+	s, err := ParseScript(input)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(s) == 0:
+		return nil, nil
+	case len(s) > 1:
+		return nil, &Error{Pos: s[1].Pos, Err: errors.New("expected a single pipeline")}
+	case s[0].Let != "":
+		return nil, &Error{Pos: s[0].Pos, Err: errors.New("expected a pipeline, not a let statement")}
+	}
+	return s[0].Pipeline, nil
+}
+
+// ParseScript parses a script of pipelines. A '|' at the end of a line or at the
+// start of the next continues the pipeline, as does a trailing backslash. Empty statements
+// and commands are skipped.
+func ParseScript(input string) (Script, error) {
+	p := &parser{lex: newLexer(input)}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	var s Script
+	for p.tok.kind != tokEOF {
+		st, err := p.statement()
+		if err != nil {
+			return nil, err
+		}
+		if len(st.Pipeline) > 0 {
+			s = append(s, st)
+		}
+	}
+	return s, nil
+}
+
+type parser struct {
+	lex *lexer
+	tok token
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lex.token()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+// continues reports whether the next line, skipping blank ones, starts with '|'
+// and so carries on the current pipeline.
+func (p *parser) continues() bool {
+	l := *p.lex
+	for {
+		tok, err := l.token()
+		if err != nil || tok.kind != tokEnd || tok.value != "\n" {
+			return err == nil && tok.kind == tokPipe
+		}
+	}
+}
+
+func (p *parser) statement() (Statement, error) {
+	st := Statement{Pos: p.tok.pos}
+	if p.tok.kind == tokWord && p.tok.value == "let" {
+		if err := p.advance(); err != nil {
+			return st, err
+		}
+		switch {
+		case p.tok.kind == tokNamed:
+			st.Let = p.tok.value
+		case p.tok.kind == tokWord && isName(p.tok.value):
+			st.Let = p.tok.value
+			if err := p.advance(); err != nil {
+				return st, err
+			}
+			if p.tok.kind != tokAssign {
+				return st, &Error{Pos: p.tok.pos, Err: fmt.Errorf("expected '=' after let %s", st.Let)}
+			}
+		default:
+			return st, &Error{Pos: p.tok.pos, Err: errors.New("expected a variable name after let")}
+		}
+		if err := p.advance(); err != nil {
+			return st, err
 		}
-		fields := strings.Fields(trimmed)
-		if len(fields) == 0 {
-			continue
+	}
+	var err error
+	if st.Pipeline, err = p.pipeline(); err != nil {
+		return st, err
+	}
+	if st.Let != "" && len(st.Pipeline) == 0 {
+		return st, &Error{Pos: st.Pos, Err: fmt.Errorf("let %s has no pipeline", st.Let)}
+	}
+	return st, nil
+}
+
+// pipeline parses commands up to the end of a statement.
+func (p *parser) pipeline() (Pipeline, error) {
+	var pl Pipeline
+	for {
+		switch p.tok.kind {
+		case tokEOF, tokClose:
+			return pl, nil
+		case tokEnd:
+			if p.lex.depth > 0 {
+				return nil, &Error{Pos: p.tok.pos, Err: errors.New("expected ')'")}
+			}
+			if p.tok.value == "\n" && len(pl) > 0 && p.continues() {
+				if err := p.advance(); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			return pl, p.advance()
+		case tokPipe:
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			// A pipe at the end of a line continues onto the next.
+			for p.tok.kind == tokEnd && p.tok.value == "\n" {
+				if err := p.advance(); err != nil {
+					return nil, err
+				}
+			}
+		case tokWord:
+			if !isName(p.tok.value) {
+				return nil, &Error{Pos: p.tok.pos, Err: fmt.Errorf("invalid command name %q", p.tok.value)}
+			}
+			cmd, err := p.command()
+			if err != nil {
+				return nil, err
+			}
+			pl = append(pl, cmd)
+		case tokRef:
+			if len(pl) > 0 {
+				return nil, &Error{Pos: p.tok.pos, Err: errors.New("a variable can only start a pipeline")}
+			}
+			pl = append(pl, Command{Var: p.tok.value, Pos: p.tok.pos})
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			switch p.tok.kind {
+			case tokPipe, tokEnd, tokClose, tokEOF:
+			default:
+				return nil, &Error{Pos: p.tok.pos, Err: fmt.Errorf("unexpected argument to $%s", pl[0].Var)}
+			}
+		default:
+			return nil, &Error{Pos: p.tok.pos, Err: errors.New("expected a command name")}
+		}
+	}
+}
+
+func (p *parser) command() (Command, error) {
+	cmd := Command{Name: p.tok.value, Pos: p.tok.pos}
+	for {
+		if err := p.advance(); err != nil {
+			return cmd, err
+		}
+		var name string
+		if p.tok.kind == tokNamed {
+			name = p.tok.value
+			if _, dup := cmd.Args.Lookup(name); dup {
+				return cmd, &Error{Pos: p.tok.pos, Err: fmt.Errorf("duplicate argument %s", name)}
+			}
+			if err := p.advance(); err != nil {
+				return cmd, err
+			}
 		}
-		cmd := Command{
-			Name: fields[0],
-			Args: fields[1:],
+		arg := Arg{Name: name}
+		switch p.tok.kind {
+		case tokWord, tokString:
+			arg.Value = p.tok.value
+		case tokRef:
+			arg.Var = p.tok.value
+		case tokOpen:
+			sub, err := p.sub()
+			if err != nil {
+				return cmd, err
+			}
+			arg.Sub = &sub
+		case tokAssign:
+			return cmd, &Error{Pos: p.tok.pos, Err: errors.New("unexpected '='")}
+		default:
+			return cmd, nil
 		}
-		p = append(p, cmd)
+		cmd.Args = append(cmd.Args, arg)
 	}
-	return p, nil
+}
+
+// sub parses a parenthesised sub-pipeline, leaving the ')' as the current token.
+func (p *parser) sub() (Pipeline, error) {
+	open := p.tok.pos
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	pl, err := p.pipeline()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case p.tok.kind != tokClose:
+		return nil, &Error{Pos: open, Err: errors.New("unclosed '('")}
+	case len(pl) == 0:
+		return nil, &Error{Pos: open, Err: errors.New("empty sub-pipeline")}
+	}
+	return pl, nil
 }
 
 func (p Pipeline) String() string {
 	var parts []string
 	for _, cmd := range p {
-		s := cmd.Name
-		if len(cmd.Args) > 0 {
-			s += " " + strings.Join(cmd.Args, " ")
-		}
-		parts = append(parts, s)
+		parts = append(parts, cmd.String())
 	}
 	return strings.Join(parts, " | ")
 }
+
+func (s Script) String() string {
+	var lines []string
+	for _, st := range s {
+		lines = append(lines, st.String())
+	}
+	return strings.Join(lines, "\n")
+}
@@ -1,7 +1,11 @@
 package dsl
 
 import (
+	"errors"
+	"image"
+	"image/color"
 	"testing"
+	"time"
 )
 
 func TestParseAndString(t *testing.T) {
@@ -82,3 +86,213 @@ func TestParseEdgeCases(t *testing.T) {
 		}
 	}
 }
+
+func TestParseArguments(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Args
+	}{
+		{`text "hello world"`, Args{{Value: "hello world"}}},
+		{`text "tab\tquote\"" 'back\slash'`, Args{{Value: "tab\tquote\""}, {Value: `back\slash`}}},
+		{`checker color1=red color2="#fff"`, Args{{Name: "color1", Value: "red"}, {Name: "color2", Value: "#fff"}}},
+		{`fill rgb(1, 2, 3) #fff`, Args{{Value: "rgb(1, 2, 3)"}, {Value: "#fff"}}},
+		{`fill red # a comment`, Args{{Value: "red"}}},
+		{`text ""`, Args{{Value: ""}}},
+	}
+	for _, tt := range tests {
+		p, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if len(p) != 1 || len(p[0].Args) != len(tt.want) {
+			t.Errorf("Parse(%q): expected one command with args %v, got %v", tt.input, tt.want, p)
+			continue
+		}
+		for i, arg := range p[0].Args {
+			if arg != tt.want[i] {
+				t.Errorf("Parse(%q): arg %d expected %+v, got %+v", tt.input, i, tt.want[i], arg)
+			}
+		}
+		if s := p.String(); s != mustParse(t, s).String() {
+			t.Errorf("String of %q is not stable: %q", tt.input, s)
+		}
+	}
+}
+
+func mustParse(t *testing.T, s string) Pipeline {
+	t.Helper()
+	p, err := Parse(s)
+	if err != nil {
+		t.Fatalf("Parse(%q) failed: %v", s, err)
+	}
+	return p
+}
+
+func TestParseScript(t *testing.T) {
+	input := `# a script
+checker red blue | zoom 4 ; null
+gradient \
+  black white |
+  rotate 90
+text hi
+
+  | zoom 2
+`
+	s, err := ParseScript(input)
+	if err != nil {
+		t.Fatalf("ParseScript failed: %v", err)
+	}
+	want := "checker red blue | zoom 4\nnull\ngradient black white | rotate 90\ntext hi | zoom 2"
+	if got := s.String(); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if pos := s[2].Pipeline[1].Pos; pos != (Pos{Line: 5, Col: 3}) {
+		t.Errorf("Expected rotate at line 5, column 3, got %s", pos)
+	}
+	if _, err := Parse("a; b"); err == nil {
+		t.Error("Expected Parse to reject more than one pipeline")
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{`text "open`, `line 1, column 6: unterminated string`},
+		{"a\nb \"\\q\"", `line 2, column 3: invalid escape in string "\q"`},
+		{"a\n  fill rgb(1, 2", `line 2, column 11: unclosed '('`},
+		{`a x=`, `line 1, column 5: missing value for x`},
+		{`a x=1 x=2`, `line 1, column 7: duplicate argument x`},
+		{`a 1x=2`, `line 1, column 3: invalid argument name "1x"`},
+		{`"a" b`, `line 1, column 1: expected a command name`},
+		{`a )`, `line 1, column 3: unexpected ')'`},
+		{"a (b", `line 1, column 3: unclosed '('`},
+		{"a ()", `line 1, column 3: empty sub-pipeline`},
+		{"a (b; c)", `line 1, column 5: expected ')'`},
+		{"a $1x", `line 1, column 3: invalid variable name "$1x"`},
+		{"let = a", `line 1, column 5: expected a variable name after let`},
+		{"let x a", `line 1, column 7: expected '=' after let x`},
+		{"let x =", `line 1, column 1: let x has no pipeline`},
+		{"a = b", `line 1, column 3: unexpected '='`},
+		{"a | $x", `line 1, column 5: a variable can only start a pipeline`},
+		{"$x y", `line 1, column 4: unexpected argument to $x`},
+		{`#'0'"000"`, `line 1, column 1: invalid command name "#"`},
+		{"a | 1x", `line 1, column 5: invalid command name "1x"`},
+	}
+	for _, tt := range tests {
+		_, err := ParseScript(tt.input)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("ParseScript(%q): expected error %q, got %v", tt.input, tt.want, err)
+		}
+	}
+}
+
+func TestParseVariables(t *testing.T) {
+	input := `let base = noise perlin | colormap
+let shine=checker
+blend $base (gradient black white |
+  rotate 90) mode=overlay fg=$shine
+$base | rotate 90`
+	s, err := ParseScript(input)
+	if err != nil {
+		t.Fatalf("ParseScript failed: %v", err)
+	}
+	if len(s) != 4 || s[0].Let != "base" || s[1].Let != "shine" || s[2].Let != "" {
+		t.Fatalf("Expected lets of base and shine then a pipeline, got %v", s)
+	}
+	args := s[2].Pipeline[0].Args
+	if args[0].Var != "base" || args[1].Sub == nil || len(*args[1].Sub) != 2 || args[3].Name != "fg" || args[3].Var != "shine" {
+		t.Errorf("Unexpected blend arguments %v", args)
+	}
+	want := "let base = noise perlin | colormap\nlet shine = checker\nblend $base (gradient black white | rotate 90) mode=overlay fg=$shine\n$base | rotate 90"
+	if got := s.String(); got != want {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+	if again, err := ParseScript(want); err != nil || again.String() != want {
+		t.Errorf("String is not stable: %v, %v", again, err)
+	}
+	if _, err := Parse("let x = a"); err == nil {
+		t.Error("Expected Parse to reject a let statement")
+	}
+}
+
+func TestInterpreter(t *testing.T) {
+	red := image.NewUniform(color.RGBA{R: 255, A: 255})
+	blue := image.NewUniform(color.RGBA{B: 255, A: 255})
+	var got []image.Image
+	fm := FuncMap{
+		"red":  func(args Args, input image.Image) (image.Image, error) { return red, nil },
+		"blue": func(args Args, input image.Image) (image.Image, error) { return blue, nil },
+		"pick": func(args Args, input image.Image) (image.Image, error) {
+			values, images, err := args.Split()
+			if err != nil {
+				return nil, err
+			}
+			if len(values) != 1 {
+				return nil, errors.New("pick takes an index")
+			}
+			got = images
+			return images[values[0][0]-'0'], nil
+		},
+	}
+	in := NewInterpreter(fm)
+	s, err := ParseScript("let r = red\npick $r (red | blue) 1")
+	if err != nil {
+		t.Fatalf("ParseScript failed: %v", err)
+	}
+	img, err := in.Run(s, nil)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+	if img != blue || len(got) != 2 || got[0] != red || in.Vars["r"] != red {
+		t.Errorf("Expected the blue sub-pipeline to be picked, got %v from %v", img, got)
+	}
+	s, _ = ParseScript("$r")
+	if img, err := in.Run(s, nil); err != nil || img != red {
+		t.Errorf("Expected the pipeline to output $r, got %v, %v", img, err)
+	}
+	s, _ = ParseScript("pick $r 0")
+	if img, err := in.Run(s, nil); err != nil || img != red {
+		t.Errorf("Expected $r to persist between runs, got %v, %v", img, err)
+	}
+	s, _ = ParseScript("red\n  pick $nope 0")
+	if _, err := in.Run(s, nil); err == nil || err.Error() != "line 2, column 3: undefined variable $nope" {
+		t.Errorf("Expected an undefined variable error, got %v", err)
+	}
+	s, _ = ParseScript("pick (nope) 0")
+	if _, err := in.Run(s, nil); err == nil || err.Error() != "line 1, column 7: unknown command: nope" {
+		t.Errorf("Expected the sub-pipeline's error, got %v", err)
+	}
+}
+
+func TestQuoteRoundTrip(t *testing.T) {
+	for _, v := range []string{"", "plain", "two words", "a|b", "x=1", `"q"`, "#", "# c", "#fff", "(a)", `\`, "line\nbreak", "semi;colon", "$x", "a)"} {
+		p := Pipeline{{Name: "cmd", Args: Args{{Value: v}, {Name: "n", Value: v}}}}
+		got := mustParse(t, p.String())
+		if len(got) != 1 || len(got[0].Args) != 2 || got[0].Args[0] != p[0].Args[0] || got[0].Args[1] != p[0].Args[1] {
+			t.Errorf("Value %q did not round trip through %q: %v", v, p.String(), got)
+		}
+	}
+}
+
+func TestParseStrayBackslashCR(t *testing.T) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s, err := ParseScript("checker \\\rrotate 90")
+		if err != nil {
+			t.Errorf("ParseScript failed: %v", err)
+			return
+		}
+		if len(s) != 1 || len(s[0].Pipeline) != 1 || len(s[0].Pipeline[0].Args) != 3 || s[0].Pipeline[0].Args[0].Value != `\` {
+			t.Errorf("Expected a lone backslash argument, got %v", s)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("ParseScript did not return on a backslash followed by a lone carriage return")
+	}
+}
@@ -5,21 +5,91 @@ import (
 	"image"
 )
 
-type CommandFunc func(args []string, input image.Image) (image.Image, error)
+type CommandFunc func(args Args, input image.Image) (image.Image, error)
 type FuncMap map[string]CommandFunc
 
-func (p Pipeline) Execute(fm FuncMap, initial image.Image) (image.Image, error) {
+// Interpreter runs scripts, keeping the variables bound by let statements from
+// one run to the next.
+type Interpreter struct {
+	Funcs FuncMap
+	Vars  map[string]image.Image
+}
+
+func NewInterpreter(fm FuncMap) *Interpreter {
+	return &Interpreter{Funcs: fm, Vars: map[string]image.Image{}}
+}
+
+// Run runs each statement of the script in turn, starting from initial, and
+// returns the result of the last.
+func (in *Interpreter) Run(s Script, initial image.Image) (image.Image, error) {
+	var img image.Image
+	for _, st := range s {
+		var err error
+		if img, err = in.Pipeline(st.Pipeline, initial); err != nil {
+			return nil, err
+		}
+		if st.Let != "" {
+			in.Vars[st.Let] = img
+		}
+	}
+	return img, nil
+}
+
+// Pipeline runs the commands of p in turn, each taking the result of the last.
+func (in *Interpreter) Pipeline(p Pipeline, initial image.Image) (image.Image, error) {
 	var img image.Image = initial
 	var err error
 	for _, cmd := range p {
-		fn, ok := fm[cmd.Name]
+		if img, err = in.command(cmd, img); err != nil {
+			return nil, err
+		}
+	}
+	return img, nil
+}
+
+// command evaluates the image arguments of cmd and calls it.
+func (in *Interpreter) command(cmd Command, input image.Image) (image.Image, error) {
+	if cmd.Var != "" {
+		img, ok := in.Vars[cmd.Var]
 		if !ok {
-			return nil, fmt.Errorf("unknown command: %s", cmd.Name)
+			return nil, &Error{Pos: cmd.Pos, Err: fmt.Errorf("undefined variable $%s", cmd.Var)}
 		}
-		img, err = fn(cmd.Args, img)
-		if err != nil {
-			return nil, fmt.Errorf("command %s failed: %w", cmd.Name, err)
+		return img, nil
+	}
+	fn, ok := in.Funcs[cmd.Name]
+	if !ok {
+		return nil, &Error{Pos: cmd.Pos, Err: fmt.Errorf("unknown command: %s", cmd.Name)}
+	}
+	args := make(Args, len(cmd.Args))
+	for i, arg := range cmd.Args {
+		switch {
+		case arg.Var != "":
+			img, ok := in.Vars[arg.Var]
+			if !ok {
+				return nil, &Error{Pos: cmd.Pos, Err: fmt.Errorf("undefined variable $%s", arg.Var)}
+			}
+			arg.Image = img
+		case arg.Sub != nil:
+			img, err := in.Pipeline(*arg.Sub, nil)
+			if err != nil {
+				return nil, err
+			}
+			arg.Image = img
 		}
+		args[i] = arg
+	}
+	img, err := fn(args, input)
+	if err != nil {
+		return nil, &Error{Pos: cmd.Pos, Err: fmt.Errorf("command %s failed: %w", cmd.Name, err)}
 	}
 	return img, nil
 }
+
+func (p Pipeline) Execute(fm FuncMap, initial image.Image) (image.Image, error) {
+	return NewInterpreter(fm).Pipeline(p, initial)
+}
+
+// Execute runs the script with a new Interpreter.
+func (s Script) Execute(fm FuncMap, initial image.Image) (image.Image, error) {
+	return NewInterpreter(fm).Run(s, initial)
+}
@@ -0,0 +1,310 @@
+package dsl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Pos is a position in a script. Lines and columns start at 1; columns count runes.
+type Pos struct {
+	Line, Col int
+}
+
+func (p Pos) String() string {
+	return fmt.Sprintf("line %d, column %d", p.Line, p.Col)
+}
+
+// Error is a parse or execution error at a position in a script.
+type Error struct {
+	Pos Pos
+	Err error
+}
+
+func (e *Error) Error() string {
+	if e.Pos.Line == 0 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %v", e.Pos, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+type tokenKind int
+
+const (
+	tokEOF    tokenKind = iota
+	tokWord             // A bare word.
+	tokString           // A quoted string, already unescaped.
+	tokNamed            // The name of a name=value argument, without the '='.
+	tokPipe             // |
+	tokEnd              // The end of a statement: a newline or ';'.
+	tokRef              // A $name reference to a variable, without the '$'.
+	tokOpen             // ( starting a sub-pipeline.
+	tokClose            // ) ending a sub-pipeline.
+	tokAssign           // = on its own, as in let.
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   Pos
+}
+
+// lexer splits a script into tokens.
+//
+// Words run until whitespace or one of | ; = " ' and may contain balanced
+// parentheses, so rgb(1, 2, 3) is a single word, while a ( at the start of a
+// token opens a sub-pipeline and $ references a variable. Double quoted strings take Go
+// escapes; single quoted strings are taken literally. A # at the start of a token
+// followed by whitespace begins a comment, leaving bare colours such as #fff as words.
+// A backslash at the end of a line joins it to the next. Newlines inside a
+// sub-pipeline's parentheses are blanks.
+type lexer struct {
+	src  string
+	off  int
+	line int
+	col  int
+	// depth is the number of sub-pipelines open.
+	depth int
+}
+
+func newLexer(src string) *lexer {
+	return &lexer{src: src, line: 1, col: 1}
+}
+
+func (l *lexer) pos() Pos {
+	return Pos{Line: l.line, Col: l.col}
+}
+
+func (l *lexer) peek() rune {
+	if l.off >= len(l.src) {
+		return -1
+	}
+	r, _ := utf8.DecodeRuneInString(l.src[l.off:])
+	return r
+}
+
+func (l *lexer) peekAt(n int) rune {
+	off := l.off
+	for ; n > 0 && off < len(l.src); n-- {
+		_, size := utf8.DecodeRuneInString(l.src[off:])
+		off += size
+	}
+	if off >= len(l.src) {
+		return -1
+	}
+	r, _ := utf8.DecodeRuneInString(l.src[off:])
+	return r
+}
+
+func (l *lexer) next() rune {
+	r, size := utf8.DecodeRuneInString(l.src[l.off:])
+	l.off += size
+	if r == '\n' {
+		l.line++
+		l.col = 1
+	} else {
+		l.col++
+	}
+	return r
+}
+
+func (l *lexer) errorf(pos Pos, format string, args ...any) error {
+	return &Error{Pos: pos, Err: fmt.Errorf(format, args...)}
+}
+
+// skipSpace skips blanks, comments and line continuations, stopping at a newline.
+func (l *lexer) skipSpace() {
+	for {
+		r := l.peek()
+		switch {
+		case r == -1 || r == '\n' && l.depth == 0:
+			return
+		case unicode.IsSpace(r):
+			l.next()
+		case r == '\\' && (l.peekAt(1) == '\n' || l.peekAt(1) == '\r' && l.peekAt(2) == '\n'):
+			l.next()
+			for l.peek() != '\n' {
+				l.next()
+			}
+			l.next()
+		case r == '#' && isCommentEnd(l.peekAt(1)):
+			for r := l.peek(); r != '\n' && r != -1; r = l.peek() {
+				l.next()
+			}
+		default:
+			return
+		}
+	}
+}
+
+func isCommentEnd(r rune) bool {
+	return r == -1 || r == '#' || unicode.IsSpace(r)
+}
+
+func (l *lexer) token() (token, error) {
+	l.skipSpace()
+	pos := l.pos()
+	switch r := l.peek(); r {
+	case -1:
+		return token{kind: tokEOF, pos: pos}, nil
+	case '\n', ';':
+		l.next()
+		return token{kind: tokEnd, value: string(r), pos: pos}, nil
+	case '|':
+		l.next()
+		return token{kind: tokPipe, pos: pos}, nil
+	case '"', '\'':
+		s, err := l.quoted()
+		return token{kind: tokString, value: s, pos: pos}, err
+	case '=':
+		l.next()
+		return token{kind: tokAssign, pos: pos}, nil
+	case '(':
+		l.next()
+		l.depth++
+		return token{kind: tokOpen, pos: pos}, nil
+	case ')':
+		if l.depth == 0 {
+			return token{}, l.errorf(pos, "unexpected ')'")
+		}
+		l.next()
+		l.depth--
+		return token{kind: tokClose, pos: pos}, nil
+	case '$':
+		l.next()
+		name, err := l.word()
+		if err != nil {
+			return token{}, err
+		}
+		if !isName(name) {
+			return token{}, l.errorf(pos, "invalid variable name %q", "$"+name)
+		}
+		return token{kind: tokRef, value: name, pos: pos}, nil
+	}
+	word, err := l.word()
+	if err != nil {
+		return token{}, err
+	}
+	if l.peek() == '=' {
+		if !isName(word) {
+			return token{}, l.errorf(pos, "invalid argument name %q", word)
+		}
+		l.next()
+		if r := l.peek(); r != '"' && r != '\'' && r != '(' && !isWordRune(r) {
+			return token{}, l.errorf(l.pos(), "missing value for %s", word)
+		}
+		return token{kind: tokNamed, value: word, pos: pos}, nil
+	}
+	return token{kind: tokWord, value: word, pos: pos}, nil
+}
+
+func isWordRune(r rune) bool {
+	switch r {
+	case -1, '|', ';', '=', '"', '\'':
+		return false
+	}
+	return !unicode.IsSpace(r)
+}
+
+func (l *lexer) word() (string, error) {
+	start := l.off
+	depth := 0
+	var open Pos
+	for {
+		r := l.peek()
+		if depth > 0 {
+			switch r {
+			case -1, '\n':
+				return "", l.errorf(open, "unclosed '('")
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+			l.next()
+			continue
+		}
+		if r == '(' {
+			open = l.pos()
+			depth++
+			l.next()
+			continue
+		}
+		if r == ')' {
+			if l.depth > 0 {
+				return l.src[start:l.off], nil
+			}
+			return "", l.errorf(l.pos(), "unexpected ')'")
+		}
+		if !isWordRune(r) || r == '\\' && (l.peekAt(1) == '\n' || l.peekAt(1) == '\r' && l.peekAt(2) == '\n') {
+			return l.src[start:l.off], nil
+		}
+		l.next()
+	}
+}
+
+func (l *lexer) quoted() (string, error) {
+	pos := l.pos()
+	start := l.off
+	q := l.next()
+	for {
+		r := l.peek()
+		switch {
+		case r == -1 || r == '\n':
+			return "", l.errorf(pos, "unterminated string")
+		case r == '\\' && q == '"':
+			l.next()
+			if l.peek() == -1 {
+				return "", l.errorf(pos, "unterminated string")
+			}
+			l.next()
+			continue
+		}
+		l.next()
+		if r == q {
+			break
+		}
+	}
+	raw := l.src[start:l.off]
+	if q == '\'' {
+		return raw[1 : len(raw)-1], nil
+	}
+	s, err := strconv.Unquote(raw)
+	if err != nil {
+		return "", l.errorf(pos, "invalid escape in string %s", raw)
+	}
+	return s, nil
+}
+
+// isName reports whether s can name an argument.
+func isName(s string) bool {
+	for i, r := range s {
+		if !(r == '_' || unicode.IsLetter(r) || i > 0 && (unicode.IsDigit(r) || r == '-')) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// quote returns s as it must be written in a script to read back as a single word.
+func quote(s string) string {
+	if s == "" || s[0] == '(' || s[0] == '$' || strings.HasPrefix(s, "#") && (len(s) == 1 || isCommentEnd(rune(s[1]))) {
+		return strconv.Quote(s)
+	}
+	l := newLexer(s)
+	if w, err := l.word(); err != nil || w != s {
+		return strconv.Quote(s)
+	}
+	for _, r := range s {
+		if r == '\\' || !unicode.IsPrint(r) {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
@@ -92,3 +92,10 @@ func NewDemoEdgeDetect(ops ...func(any)) image.Image {
 	zm := NewSimpleZoom(chk, 20, ops...)
 	return NewEdgeDetect(zm, ops...)
 }
+
+func init() {
+	RegisterPattern(filterType("edge_detect", &EdgeDetect{}, NewEdgeDetect, func(img image.Image) image.Image {
+		return img.(*EdgeDetect).img
+	}))
+	RegisterPattern(generatorType("demo_edge_detect", nil, NewDemoEdgeDetect))
+}
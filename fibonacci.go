@@ -131,3 +131,8 @@ func NewFibonacci(ops ...func(any)) image.Image {
 func NewDemoFibonacci(ops ...func(any)) image.Image {
 	return NewFibonacci(ops...)
 }
+
+func init() {
+	RegisterPattern(generatorType("fibonacci", &Fibonacci{}, NewFibonacci))
+	RegisterPattern(generatorType("demo_fibonacci", nil, NewDemoFibonacci))
+}
@@ -260,3 +260,8 @@ func NewDemoFineGrid(ops ...func(any)) image.Image {
 	args = append(args, ops...)
 	return NewFineGrid(args...)
 }
+
+func init() {
+	RegisterPattern(generatorType("fine_grid", &FineGrid{}, NewFineGrid))
+	RegisterPattern(generatorType("demo_fine_grid", nil, NewDemoFineGrid))
+}
@@ -11,8 +11,10 @@ var _ image.Image = (*Fog)(nil)
 
 // Fog renders a tinted, noise-driven fog with radial falloff.
 // The center stays clearer than the edges to focus the viewer's eye.
+// Animated with SetTime, the fog drifts right fogDrift pixels a second.
 type Fog struct {
 	Null
+	Time
 	FillColor
 	Density
 	FloatCenter
@@ -32,10 +34,13 @@ func (f *Fog) At(x, y int) color.Color {
 		return color.NRGBA{}
 	}
 
+	drift := f.Time.Time * fogDrift
 	noiseVal := 0.0
-	if f.algo != nil {
+	if field, ok := f.algo.(ScalarField); ok {
+		noiseVal = clampFloat(field.ValueAt(float64(x)-drift, float64(y)))
+	} else if f.algo != nil {
 		// Convert to grayscale to normalize arbitrary NoiseAlgorithms.
-		g := color.GrayModel.Convert(f.algo.At(x, y)).(color.Gray)
+		g := color.GrayModel.Convert(f.algo.At(x-int(math.Floor(drift)), y)).(color.Gray)
 		noiseVal = float64(g.Y) / 255.0
 	}
 
@@ -56,6 +61,9 @@ func (f *Fog) At(x, y int) color.Color {
 	}
 }
 
+// fogDrift is the speed, in pixels a second, at which animated fog drifts.
+const fogDrift = 16
+
 // SetNoiseAlgorithm allows swapping the underlying noise source.
 func (f *Fog) SetNoiseAlgorithm(algo NoiseAlgorithm) {
 	f.algo = algo
@@ -139,3 +147,7 @@ func NewFog(ops ...func(any)) image.Image {
 
 	return f
 }
+
+func init() {
+	RegisterPattern(generatorType("fog", &Fog{}, NewFog))
+}
@@ -0,0 +1,148 @@
+package pattern
+
+import (
+	"image/color"
+	"math"
+	"sync"
+)
+
+// Ensure FractalNoise implements the NoiseAlgorithm and ScalarField interfaces.
+var _ NoiseAlgorithm = (*FractalNoise)(nil)
+var _ ScalarField = (*FractalNoise)(nil)
+
+// FractalType selects how FractalNoise combines its octaves.
+type FractalType int
+
+const (
+	// FractalFBM sums the octaves: Fractional Brownian Motion.
+	FractalFBM FractalType = iota
+	// FractalBillow sums the absolute values of the octaves, giving puffy shapes
+	// with sharp creases between them.
+	FractalBillow
+	// FractalRidged is Musgrave's ridged multifractal: each octave is folded into
+	// sharp ridges, Offset - |n| squared, and weighted by the one before it times
+	// Gain, so detail gathers on the ridges as it does on mountains.
+	FractalRidged
+	// FractalTurbulence is Perlin's turbulence: the absolute values of the octaves
+	// each weighted by the inverse of their frequency, whatever the Persistence.
+	FractalTurbulence
+	// FractalHybrid is Musgrave's hybrid multifractal: each octave, raised by
+	// Offset, is weighted by the octaves before it, so valleys stay smooth while
+	// peaks get rough.
+	FractalHybrid
+)
+
+// fractalTypeNames are the names of the FractalType values in the pattern registry.
+var fractalTypeNames = []string{"fbm", "billow", "ridged", "turbulence", "hybrid"}
+
+// fractalShiftX and fractalShiftY move each octave away from the last, in base
+// pixels, so the octaves do not all line up at the origin.
+const (
+	fractalShiftX = 317.3
+	fractalShiftY = 149.9
+)
+
+// FractalNoise combines octaves of any base noise: each octave samples Base with
+// the coordinates scaled up by Lacunarity and its weight scaled down by
+// Persistence. Base should be a single octave noise whose values are in [0, 1];
+// it defaults to OpenSimplexNoise. The zero values of the other fields use their
+// defaults: 5 octaves, a Frequency multiplier of 1, a Lacunarity of 2, a
+// Persistence of 0.5, an Offset of 1 for ridged and 0.7 for hybrid noise, and a
+// Gain of 2.
+type FractalNoise struct {
+	Base        ScalarField
+	Type        FractalType
+	Octaves     int
+	Frequency   float64 // Multiplies the coordinates before the base scales them.
+	Lacunarity  float64
+	Persistence float64
+	Offset      float64 // Ridged and hybrid noise.
+	Gain        float64 // Ridged noise.
+
+	once sync.Once
+}
+
+func (f *FractalNoise) init() {
+	f.once.Do(func() {
+		if f.Base == nil {
+			f.Base = &OpenSimplexNoise{}
+		}
+		if f.Octaves == 0 {
+			f.Octaves = 5
+		}
+		if f.Frequency == 0 {
+			f.Frequency = 1
+		}
+		if f.Lacunarity == 0 {
+			f.Lacunarity = 2
+		}
+		if f.Persistence == 0 {
+			f.Persistence = 0.5
+		}
+		if f.Offset == 0 {
+			switch f.Type {
+			case FractalRidged:
+				f.Offset = 1
+			case FractalHybrid:
+				f.Offset = 0.7
+			}
+		}
+		if f.Gain == 0 {
+			f.Gain = 2
+		}
+	})
+}
+
+func (f *FractalNoise) At(x, y int) color.Color {
+	return color.Gray{Y: uint8(clamp01(f.ValueAt(float64(x), float64(y))) * 255)}
+}
+
+// ValueAt returns the combined octaves at (x, y), mapped to roughly [0, 1]
+// without clamping or quantisation.
+func (f *FractalNoise) ValueAt(x, y float64) float64 {
+	f.init()
+	var sum, norm float64
+	amplitude, frequency := 1.0, f.Frequency
+	// weight carries each octave of ridged and hybrid noise on to the next.
+	weight := 1.0
+	for i := 0; i < f.Octaves; i++ {
+		sx, sy := float64(i)*fractalShiftX, float64(i)*fractalShiftY
+		n := 2*f.Base.ValueAt(x*frequency+sx, y*frequency+sy) - 1
+		switch f.Type {
+		case FractalBillow, FractalTurbulence:
+			sum += math.Abs(n) * amplitude
+		case FractalRidged:
+			signal := f.Offset - math.Abs(n)
+			signal *= signal * weight
+			weight = clamp01(signal * f.Gain)
+			sum += signal * amplitude
+		case FractalHybrid:
+			signal := (n + f.Offset) * amplitude
+			if i == 0 {
+				sum, weight = signal, signal
+			} else {
+				sum += math.Min(weight, 1) * signal
+				weight *= signal
+			}
+		default:
+			sum += n * amplitude
+		}
+		norm += amplitude
+		if f.Type == FractalTurbulence {
+			amplitude /= f.Lacunarity
+		} else {
+			amplitude *= f.Persistence
+		}
+		frequency *= f.Lacunarity
+	}
+
+	switch f.Type {
+	case FractalBillow, FractalTurbulence:
+		return sum / norm
+	case FractalRidged:
+		return sum / (norm * f.Offset * f.Offset)
+	case FractalHybrid:
+		return sum / (norm * (1 + f.Offset))
+	}
+	return (sum/norm + 1) * 0.5
+}
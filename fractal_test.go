@@ -0,0 +1,87 @@
+package pattern
+
+import (
+	"bytes"
+	"image"
+	"math"
+	"testing"
+)
+
+func TestFractalNoiseTypes(t *testing.T) {
+	values := map[FractalType][]float64{}
+	for typ := FractalFBM; typ <= FractalHybrid; typ++ {
+		f := &FractalNoise{Base: &PerlinNoise{Seed: 2}, Type: typ}
+		lo, hi := math.Inf(1), math.Inf(-1)
+		for y := 0.0; y < 128; y += 3 {
+			for x := 0.0; x < 128; x += 3 {
+				v := f.ValueAt(x, y)
+				values[typ] = append(values[typ], v)
+				lo, hi = math.Min(lo, v), math.Max(hi, v)
+			}
+		}
+		name := fractalTypeNames[typ]
+		if lo < -0.05 || hi > 1.05 {
+			t.Errorf("%s: values span [%v, %v], want roughly [0, 1]", name, lo, hi)
+		}
+		if hi-lo < 0.2 {
+			t.Errorf("%s: values span [%v, %v], want more contrast", name, lo, hi)
+		}
+		if g := f.At(10, 20); g != f.At(10, 20) {
+			t.Errorf("%s: At is not deterministic", name)
+		}
+	}
+	for typ := FractalBillow; typ <= FractalHybrid; typ++ {
+		same := true
+		for i, v := range values[typ] {
+			same = same && v == values[FractalFBM][i]
+		}
+		if same {
+			t.Errorf("%s is the same as fbm", fractalTypeNames[typ])
+		}
+	}
+}
+
+func TestFractalNoiseBase(t *testing.T) {
+	// A ridged fractal of one octave is the base folded into ridges.
+	base := &OpenSimplexNoise{Seed: 9}
+	f := &FractalNoise{Base: base, Type: FractalRidged, Octaves: 1}
+	for x := 0.0; x < 50; x += 1.5 {
+		n := 2*base.ValueAt(x, 7) - 1
+		want := (1 - math.Abs(n)) * (1 - math.Abs(n))
+		if got := f.ValueAt(x, 7); math.Abs(got-want) > 1e-12 {
+			t.Fatalf("ValueAt(%v, 7) = %v, want %v", x, got, want)
+		}
+	}
+
+	// Any scalar field will do as a base, and the noise seeds it.
+	w := NewWorleyNoise(SetSeed(1)).(*WorleyNoise)
+	n := NewNoise(SetNoiseAlgorithm(&FractalNoise{Base: w, Type: FractalBillow}), SetSeed(5))
+	if w.Seed.Seed != 5 {
+		t.Errorf("Worley base seed = %d, want 5", w.Seed.Seed)
+	}
+	if _, ok := n.(ScalarField); !ok {
+		t.Error("fractal noise is not a scalar field")
+	}
+}
+
+func TestFractalNoiseGraph(t *testing.T) {
+	b := image.Rect(0, 0, 40, 30)
+	img := NewNoise(SetBounds(b), SetNoiseAlgorithm(&FractalNoise{
+		Base: &OpenSimplexNoise{Seed: 4, Frequency: 0.05, Octaves: 1},
+		Type: FractalHybrid,
+	}))
+	var buf bytes.Buffer
+	if err := SaveGraph(&buf, img); err != nil {
+		t.Fatalf("SaveGraph failed: %v", err)
+	}
+	loaded, err := LoadGraph(&buf)
+	if err != nil {
+		t.Fatalf("LoadGraph failed: %v", err)
+	}
+	sameImage(t, img, loaded)
+
+	multi := NewNoise(SetNoiseAlgorithm(&FractalNoise{Base: &PerlinNoise{Octaves: 3}}))
+	if err := SaveGraph(&buf, multi); err == nil {
+		t.Error("SaveGraph of a fractal over several octaves succeeded")
+	}
+}
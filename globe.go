@@ -112,6 +112,10 @@ func (p *Globe) At(x, y int) color.Color {
 		// Map UV to source bounds
 		src := p.FillImageSource.FillImageSource
 		sb := src.Bounds()
+		if s, ok := src.(Sampler); ok {
+			// Continuous textures are sampled at the exact UV position.
+			return s.SampleAt(float64(sb.Min.X)+u*float64(sb.Dx()), float64(sb.Min.Y)+v*float64(sb.Dy()))
+		}
 		sx := int(u * float64(sb.Dx()))
 		sy := int(v * float64(sb.Dy()))
 		// Add Min
@@ -185,3 +189,7 @@ func NewGlobe(ops ...func(any)) image.Image {
 	}
 	return p
 }
+
+func init() {
+	RegisterPattern(generatorType("globe", &Globe{}, NewGlobe))
+}
@@ -372,3 +372,8 @@ var runeGlyphs = [][]string{
 		"..#..",
 	},
 }
+
+func init() {
+	RegisterPattern(generatorType("glyph_ring", &GlyphRing{}, NewGlyphRing))
+	RegisterPattern(generatorType("demo_glyph_ring", nil, NewDemoGlyphRing))
+}
@@ -4,6 +4,9 @@ go 1.24.0
 
 toolchain go1.24.3
 
-require golang.org/x/image v0.34.0
+require (
+	golang.org/x/image v0.34.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require golang.org/x/text v0.32.0 // indirect
@@ -31,3 +31,12 @@ func NewGoLogo() image.Image {
 	}
 	return img
 }
+
+func init() {
+	RegisterPattern(generatorType("gopher", nil, func(ops ...func(any)) image.Image {
+		return applyOps(NewGopher(), ops)
+	}))
+	RegisterPattern(generatorType("go_logo", nil, func(ops ...func(any)) image.Image {
+		return applyOps(NewGoLogo(), ops)
+	}))
+}
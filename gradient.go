@@ -225,3 +225,57 @@ func lerpColor(c1, c2 color.Color, t float64) color.Color {
 
 	return color.RGBA64{R: r, G: g, B: b, A: a}
 }
+
+func init() {
+	RegisterPattern(&PatternType{
+		Name:     "linear_gradient",
+		Category: CategoryGenerator,
+		Params: []Param{
+			{Name: "vertical", Type: ParamBool, Default: false, Doc: "Run the gradient top to bottom."},
+		},
+		Sample: &LinearGradient{},
+		New: func(a *Args) (image.Image, error) {
+			p := NewLinearGradient().(*LinearGradient)
+			p.Vertical = a.Bool("vertical")
+			return applyOps(p, a.Options), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			a.Set("vertical", img.(*LinearGradient).Vertical)
+			return nil
+		},
+	})
+	RegisterPattern(&PatternType{
+		Name:     "radial_gradient",
+		Category: CategoryGenerator,
+		Params: []Param{
+			{Name: "use_float_center", Type: ParamBool, Default: false, Doc: "Position the centre with FloatCenter."},
+		},
+		Sample: &RadialGradient{},
+		New: func(a *Args) (image.Image, error) {
+			p := NewRadialGradient().(*RadialGradient)
+			p.UseFloatCenter = a.Bool("use_float_center")
+			return applyOps(p, a.Options), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			a.Set("use_float_center", img.(*RadialGradient).UseFloatCenter)
+			return nil
+		},
+	})
+	RegisterPattern(&PatternType{
+		Name:     "conic_gradient",
+		Category: CategoryGenerator,
+		Params: []Param{
+			{Name: "use_float_center", Type: ParamBool, Default: false, Doc: "Position the centre with FloatCenter."},
+		},
+		Sample: &ConicGradient{},
+		New: func(a *Args) (image.Image, error) {
+			p := NewConicGradient().(*ConicGradient)
+			p.UseFloatCenter = a.Bool("use_float_center")
+			return applyOps(p, a.Options), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			a.Set("use_float_center", img.(*ConicGradient).UseFloatCenter)
+			return nil
+		},
+	})
+}
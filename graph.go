@@ -0,0 +1,584 @@
+package pattern
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Graph is the serialisable form of a pattern graph. Each node names a pattern type,
+// the arguments and options it is constructed with and the nodes it takes as input.
+// Output is the id of the node that produces the final image; when empty the last
+// node is used.
+//
+// Graphs are written as JSON by SaveGraph and as YAML by SaveGraphYAML. LoadGraph
+// reads either.
+type Graph struct {
+	Nodes  []*GraphNode `json:"nodes" yaml:"nodes"`
+	Output string       `json:"output,omitempty" yaml:"output,omitempty"`
+}
+
+// GraphNode describes a single pattern in a Graph.
+//
+// Inputs maps an input slot, such as "source", or an image option, such as
+// "FillImageSource", to the ids of the nodes feeding it. Params holds the
+// pattern's constructor arguments and other type specific settings. Options holds
+// the values of the shared Set* options, keyed by name without the Set prefix,
+// for example {"SpaceSize": 10, "FillColor": "#ff0000"}. Bounds is written as
+// [minX, minY, maxX, maxY].
+type GraphNode struct {
+	ID      string               `json:"id" yaml:"id"`
+	Type    string               `json:"type" yaml:"type"`
+	Bounds  []int                `json:"bounds,omitempty" yaml:"bounds,omitempty,flow"`
+	Inputs  map[string]GraphRefs `json:"inputs,omitempty" yaml:"inputs,omitempty"`
+	Params  map[string]any       `json:"params,omitempty" yaml:"params,omitempty"`
+	Options map[string]any       `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+// GraphRefs is a list of node ids. A single id may be written as a plain string.
+type GraphRefs []string
+
+func (g *GraphRefs) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*g = GraphRefs{s}
+		return nil
+	}
+	var l []string
+	if err := json.Unmarshal(data, &l); err != nil {
+		return fmt.Errorf("graph: input must be a node id or a list of node ids")
+	}
+	*g = l
+	return nil
+}
+
+func (g GraphRefs) MarshalJSON() ([]byte, error) {
+	if len(g) == 1 {
+		return json.Marshal(g[0])
+	}
+	return json.Marshal([]string(g))
+}
+
+func (g GraphRefs) MarshalYAML() (any, error) {
+	if len(g) == 1 {
+		return g[0], nil
+	}
+	return []string(g), nil
+}
+
+// LoadGraph reads a JSON or YAML pattern graph from r and builds its output image.
+func LoadGraph(r io.Reader) (image.Image, error) {
+	g, err := DecodeGraph(r)
+	if err != nil {
+		return nil, err
+	}
+	return g.Build()
+}
+
+// SaveGraph writes the pattern graph that produces img to w as JSON.
+func SaveGraph(w io.Writer, img image.Image) error {
+	g, err := EncodeGraph(img)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// SaveGraphYAML writes the pattern graph that produces img to w as YAML.
+func SaveGraphYAML(w io.Writer, img image.Image) error {
+	g, err := EncodeGraph(img)
+	if err != nil {
+		return err
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(g); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// DecodeGraph reads a Graph from r. Documents starting with '{' are read as JSON,
+// anything else as YAML.
+func DecodeGraph(r io.Reader) (*Graph, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && trimmed[0] != '{' {
+		// YAML is converted through JSON so both formats decode values the same way.
+		var doc any
+		if err := yaml.Unmarshal(trimmed, &doc); err != nil {
+			return nil, fmt.Errorf("graph: %w", err)
+		}
+		if trimmed, err = json.Marshal(doc); err != nil {
+			return nil, fmt.Errorf("graph: %w", err)
+		}
+	}
+	var g Graph
+	if err := json.Unmarshal(trimmed, &g); err != nil {
+		return nil, fmt.Errorf("graph: %w", err)
+	}
+	return &g, nil
+}
+
+// Build constructs the patterns in the graph and returns the output image.
+// Nodes are built once, so a node used as the input of several others is shared.
+func (g *Graph) Build() (image.Image, error) {
+	b := &graphBuilder{
+		nodes:    map[string]*GraphNode{},
+		built:    map[string]image.Image{},
+		building: map[string]bool{},
+	}
+	for _, n := range g.Nodes {
+		if n.ID == "" {
+			return nil, fmt.Errorf("graph: node of type %q has no id", n.Type)
+		}
+		if _, dup := b.nodes[n.ID]; dup {
+			return nil, fmt.Errorf("graph: duplicate node id %q", n.ID)
+		}
+		b.nodes[n.ID] = n
+	}
+	output := g.Output
+	if output == "" {
+		if len(g.Nodes) == 0 {
+			return nil, errors.New("graph: no nodes")
+		}
+		output = g.Nodes[len(g.Nodes)-1].ID
+	}
+	return b.build(output)
+}
+
+// graphBuilder builds the nodes of a Graph, memoising shared nodes.
+type graphBuilder struct {
+	nodes    map[string]*GraphNode
+	built    map[string]image.Image
+	building map[string]bool
+}
+
+func (b *graphBuilder) build(id string) (image.Image, error) {
+	if img, ok := b.built[id]; ok {
+		return img, nil
+	}
+	n, ok := b.nodes[id]
+	if !ok {
+		return nil, fmt.Errorf("graph: unknown node %q", id)
+	}
+	if b.building[id] {
+		return nil, fmt.Errorf("graph: node %q is part of a cycle", id)
+	}
+	t, ok := LookupPattern(n.Type)
+	if !ok {
+		return nil, fmt.Errorf("graph: node %q: unknown type %q", id, n.Type)
+	}
+	b.building[id] = true
+	defer delete(b.building, id)
+
+	var a Args
+	if n.Bounds != nil {
+		if len(n.Bounds) != 4 {
+			return nil, fmt.Errorf("graph: node %q: bounds must have 4 values", id)
+		}
+		a.Options = append(a.Options, SetBounds(image.Rect(n.Bounds[0], n.Bounds[1], n.Bounds[2], n.Bounds[3])))
+	}
+	// setters records the options given, to check they apply once the pattern is built.
+	var setters []string
+	for _, slot := range slices.Sorted(maps.Keys(n.Inputs)) {
+		opt, isOption := graphOptions[slot]
+		isOption = isOption && opt.setImage != nil
+		if t.Input(slot) == nil && !isOption {
+			return nil, fmt.Errorf("graph: node %q: type %q has no input %q", id, n.Type, slot)
+		}
+		var imgs []image.Image
+		for _, ref := range n.Inputs[slot] {
+			img, err := b.build(ref)
+			if err != nil {
+				return nil, err
+			}
+			imgs = append(imgs, img)
+		}
+		if !isOption {
+			a.SetInput(slot, imgs...)
+			continue
+		}
+		if len(imgs) != 1 {
+			return nil, fmt.Errorf("graph: node %q: input %q takes a single node", id, slot)
+		}
+		a.Options = append(a.Options, opt.setImage(imgs[0]))
+		setters = append(setters, slot)
+	}
+	for _, name := range slices.Sorted(maps.Keys(n.Options)) {
+		opt, ok := graphOptions[name]
+		if !ok {
+			return nil, fmt.Errorf("graph: node %q: unknown option %q", id, name)
+		}
+		if opt.decode == nil {
+			return nil, fmt.Errorf("graph: node %q: option %q must be given as an input", id, name)
+		}
+		op, err := opt.decode(n.Options[name])
+		if err != nil {
+			return nil, fmt.Errorf("graph: node %q: option %q: %w", id, name, err)
+		}
+		a.Options = append(a.Options, op)
+		setters = append(setters, name)
+	}
+	for _, name := range slices.Sorted(maps.Keys(n.Params)) {
+		if t.Param(name) == nil {
+			return nil, fmt.Errorf("graph: node %q: type %q has no parameter %q", id, n.Type, name)
+		}
+		if v := n.Params[name]; v != nil {
+			a.Set(name, v)
+		}
+	}
+
+	img, err := t.Build(a)
+	if err != nil {
+		return nil, fmt.Errorf("graph: node %q: %w", id, err)
+	}
+	for _, name := range setters {
+		if !hasSetter(img, "Set"+name) {
+			return nil, fmt.Errorf("graph: node %q: option %q does not apply to type %q", id, name, n.Type)
+		}
+	}
+	b.built[id] = img
+	return img, nil
+}
+
+// EncodeGraph describes img, and the patterns it is built from, as a Graph.
+// It fails if any pattern in the chain cannot be serialised, for example a
+// NewGeneric pattern wrapping a Go function.
+func EncodeGraph(img image.Image) (*Graph, error) {
+	e := &graphEncoder{
+		graph:  &Graph{},
+		ids:    map[image.Image]string{},
+		counts: map[string]int{},
+	}
+	id, err := e.encode(img)
+	if err != nil {
+		return nil, err
+	}
+	e.graph.Output = id
+	return e.graph, nil
+}
+
+// graphEncoder walks a pattern chain, emitting inputs before the nodes that use them.
+type graphEncoder struct {
+	graph  *Graph
+	ids    map[image.Image]string
+	counts map[string]int
+}
+
+func (e *graphEncoder) encode(img image.Image) (string, error) {
+	if img == nil {
+		return "", errors.New("graph: cannot encode a nil image")
+	}
+	shareable := reflect.TypeOf(img).Comparable()
+	if shareable {
+		if id, ok := e.ids[img]; ok {
+			return id, nil
+		}
+	}
+	t, ok := PatternTypeOf(img)
+	if !ok || (t.Encode == nil && len(t.Inputs) > 0) {
+		return "", fmt.Errorf("graph: %T cannot be serialised", img)
+	}
+	n := &GraphNode{Type: t.Name}
+	var a Args
+	if t.Encode != nil {
+		if err := t.Encode(img, &a); err != nil {
+			return "", fmt.Errorf("graph: %w", err)
+		}
+	}
+	for _, slot := range slices.Sorted(maps.Keys(a.Inputs)) {
+		for _, in := range a.Inputs[slot] {
+			if err := e.input(n, slot, in); err != nil {
+				return "", err
+			}
+		}
+	}
+	// Parameters left at their default are omitted.
+	for _, p := range t.Params {
+		v, ok := a.Values[p.Name]
+		if !ok {
+			continue
+		}
+		fv := p.Format(v)
+		if reflect.DeepEqual(fv, p.Format(p.Default)) {
+			continue
+		}
+		if n.Params == nil {
+			n.Params = map[string]any{}
+		}
+		n.Params[p.Name] = fv
+	}
+	def := defaultPattern(t)
+	if err := e.encodeOptions(n, img, def); err != nil {
+		return "", err
+	}
+	if _, ok := img.(hasBounds); ok && (def == nil || img.Bounds() != def.Bounds()) {
+		b := img.Bounds()
+		n.Bounds = []int{b.Min.X, b.Min.Y, b.Max.X, b.Max.Y}
+	}
+
+	e.counts[n.Type]++
+	n.ID = fmt.Sprintf("%s%d", n.Type, e.counts[n.Type])
+	e.graph.Nodes = append(e.graph.Nodes, n)
+	if shareable {
+		e.ids[img] = n.ID
+	}
+	return n.ID, nil
+}
+
+// input encodes img and connects it to slot of n.
+func (e *graphEncoder) input(n *GraphNode, slot string, img image.Image) error {
+	id, err := e.encode(img)
+	if err != nil {
+		return err
+	}
+	if n.Inputs == nil {
+		n.Inputs = map[string]GraphRefs{}
+	}
+	n.Inputs[slot] = append(n.Inputs[slot], id)
+	return nil
+}
+
+// encodeOptions records the embedded option structs of img that differ from those
+// of the freshly constructed def.
+func (e *graphEncoder) encodeOptions(n *GraphNode, img, def image.Image) error {
+	v := reflect.ValueOf(img)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil
+	}
+	var dv reflect.Value
+	if def != nil && reflect.TypeOf(def) == v.Type() {
+		dv = reflect.ValueOf(def).Elem()
+	}
+	for _, name := range slices.Sorted(maps.Keys(graphOptions)) {
+		opt := graphOptions[name]
+		f := optionField(v.Elem(), opt.typ)
+		if !f.IsValid() {
+			continue
+		}
+		if opt.setImage != nil {
+			src, _ := f.Field(0).Interface().(image.Image)
+			if src == nil {
+				continue
+			}
+			if err := e.input(n, name, src); err != nil {
+				return err
+			}
+			continue
+		}
+		if dv.IsValid() {
+			if reflect.DeepEqual(f.Interface(), optionField(dv, opt.typ).Interface()) {
+				continue
+			}
+		} else if f.IsZero() {
+			continue
+		}
+		if v := opt.encode(f); v != nil {
+			if n.Options == nil {
+				n.Options = map[string]any{}
+			}
+			n.Options[name] = v
+		}
+	}
+	return nil
+}
+
+// optionField returns the embedded option struct of type typ within v, if any.
+func optionField(v reflect.Value, typ reflect.Type) reflect.Value {
+	f := v.FieldByName(typ.Name())
+	if !f.IsValid() || f.Type() != typ {
+		return reflect.Value{}
+	}
+	return f
+}
+
+// defaultPattern builds t with no parameters, to compare option values against.
+// It returns nil if the type cannot be built without inputs.
+func defaultPattern(t *PatternType) image.Image {
+	img, err := t.Build(Args{})
+	if err != nil {
+		return nil
+	}
+	return img
+}
+
+// graphOption maps one of the shared Set* options onto the embedded struct it sets.
+// Image options have setImage instead of decode and encode, and are written as inputs.
+type graphOption struct {
+	typ      reflect.Type
+	decode   func(v any) (func(any), error)
+	encode   func(f reflect.Value) any
+	setImage func(img image.Image) func(any)
+}
+
+func intOption(sample any, set func(int) func(any)) graphOption {
+	return graphOption{
+		typ: reflect.TypeOf(sample),
+		decode: func(v any) (func(any), error) {
+			i, err := convertInt(v)
+			return set(i), err
+		},
+		encode: func(f reflect.Value) any { return int(f.Field(0).Int()) },
+	}
+}
+
+func floatOption(sample any, set func(float64) func(any)) graphOption {
+	return graphOption{
+		typ: reflect.TypeOf(sample),
+		decode: func(v any) (func(any), error) {
+			x, err := convertFloat(v)
+			return set(x), err
+		},
+		encode: func(f reflect.Value) any { return f.Field(0).Float() },
+	}
+}
+
+func colorOption(sample any, set func(color.Color) func(any)) graphOption {
+	return graphOption{
+		typ: reflect.TypeOf(sample),
+		decode: func(v any) (func(any), error) {
+			c, err := convertColor(v)
+			return set(c), err
+		},
+		encode: func(f reflect.Value) any {
+			c, _ := f.Field(0).Interface().(color.Color)
+			if c == nil {
+				return nil
+			}
+			return formatColor(c)
+		},
+	}
+}
+
+func imageOption(sample any, set func(image.Image) func(any)) graphOption {
+	return graphOption{typ: reflect.TypeOf(sample), setImage: set}
+}
+
+var graphOptions = map[string]graphOption{
+	"SpaceSize":      intOption(SpaceSize{}, SetSpaceSize),
+	"LineSize":       intOption(LineSize{}, SetLineSize),
+	"Radius":         intOption(Radius{}, SetRadius),
+	"Spacing":        intOption(Spacing{}, SetSpacing),
+	"BlockSize":      intOption(BlockSize{}, SetBlockSize),
+	"LatitudeLines":  intOption(LatitudeLines{}, SetLatitudeLines),
+	"LongitudeLines": intOption(LongitudeLines{}, SetLongitudeLines),
+	"Seed":           intOption(Seed{}, func(v int) func(any) { return SetSeed(int64(v)) }),
+	"MinRadius":      floatOption(MinRadius{}, SetMinRadius),
+	"MaxRadius":      floatOption(MaxRadius{}, SetMaxRadius),
+	"Density":        floatOption(Density{}, SetDensity),
+	"Phase":          floatOption(Phase{}, SetPhase),
+	"Time":           floatOption(Time{}, SetTime),
+	"Loop":           floatOption(Loop{}, SetLoop),
+	"Angle":          floatOption(Angle{}, SetAngle),
+	"Frequency":      floatOption(Frequency{}, SetFrequency),
+	"FrequencyX":     floatOption(FrequencyX{}, SetFrequencyX),
+	"FrequencyY":     floatOption(FrequencyY{}, SetFrequencyY),
+	"Tilt":           floatOption(Tilt{}, SetTilt),
+	"FalloffCurve":   floatOption(FalloffCurve{}, SetFalloffCurve),
+	"FillColor":      colorOption(FillColor{}, SetFillColor),
+	"LineColor":      colorOption(LineColor{}, SetLineColor),
+	"SpaceColor":     colorOption(SpaceColor{}, SetSpaceColor),
+	"StartColor":     colorOption(StartColor{}, SetStartColor),
+	"EndColor":       colorOption(EndColor{}, SetEndColor),
+	"TrueColor":      colorOption(TrueColor{}, SetTrueColor),
+	"FalseColor":     colorOption(FalseColor{}, SetFalseColor),
+	"AntiAlias": {
+		typ: reflect.TypeOf(AntiAlias{}),
+		decode: func(v any) (func(any), error) {
+			b, ok := v.(bool)
+			if !ok {
+				return nil, fmt.Errorf("expected true or false, got %v", v)
+			}
+			return SetAntiAlias(b), nil
+		},
+		encode: func(f reflect.Value) any { return f.Field(0).Bool() },
+	},
+	"Angles": {
+		typ: reflect.TypeOf(Angles{}),
+		decode: func(v any) (func(any), error) {
+			l, err := convertFloats(v)
+			return SetAngles(l...), err
+		},
+		encode: func(f reflect.Value) any { return f.Field(0).Interface() },
+	},
+	"Palette": {
+		typ: reflect.TypeOf(Palette{}),
+		decode: func(v any) (func(any), error) {
+			l, err := convertColors(v)
+			return SetPalette(l...), err
+		},
+		encode: func(f reflect.Value) any {
+			return formatColors(f.Field(0).Interface().([]color.Color))
+		},
+	},
+	"Period": {
+		typ: reflect.TypeOf(Period{}),
+		decode: func(v any) (func(any), error) {
+			l, err := convertInts(v, ",")
+			if err != nil || len(l) != 2 {
+				return nil, fmt.Errorf("expected [x, y], got %v", v)
+			}
+			return SetPeriod(l[0], l[1]), nil
+		},
+		encode: func(f reflect.Value) any {
+			return []int{int(f.Field(0).Int()), int(f.Field(1).Int())}
+		},
+	},
+	"Center": {
+		typ: reflect.TypeOf(Center{}),
+		decode: func(v any) (func(any), error) {
+			l, err := convertInts(v, ",")
+			if err != nil || len(l) != 2 {
+				return nil, fmt.Errorf("expected [x, y], got %v", v)
+			}
+			return SetCenter(l[0], l[1]), nil
+		},
+		encode: func(f reflect.Value) any {
+			return []int{int(f.Field(0).Int()), int(f.Field(1).Int())}
+		},
+	},
+	"FloatCenter": {
+		typ: reflect.TypeOf(FloatCenter{}),
+		decode: func(v any) (func(any), error) {
+			l, err := convertFloats(v)
+			if err != nil || len(l) != 2 {
+				return nil, fmt.Errorf("expected [x, y], got %v", v)
+			}
+			return SetFloatCenter(l[0], l[1]), nil
+		},
+		encode: func(f reflect.Value) any {
+			return []float64{f.Field(0).Float(), f.Field(1).Float()}
+		},
+	},
+	"Expiry": {
+		typ: reflect.TypeOf(Expiry{}),
+		decode: func(v any) (func(any), error) {
+			s, _ := v.(string)
+			d, err := time.ParseDuration(s)
+			return SetExpiry(d), err
+		},
+		encode: func(f reflect.Value) any { return time.Duration(f.Field(0).Int()).String() },
+	},
+	"FillImageSource":  imageOption(FillImageSource{}, SetFillImageSource),
+	"SpaceImageSource": imageOption(SpaceImageSource{}, SetSpaceImageSource),
+	"LineImageSource":  imageOption(LineImageSource{}, SetLineImageSource),
+}
@@ -0,0 +1,125 @@
+package pattern
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+func sameImage(t *testing.T, a, b image.Image) {
+	t.Helper()
+	if a.Bounds() != b.Bounds() {
+		t.Fatalf("Bounds differ: %v and %v", a.Bounds(), b.Bounds())
+	}
+	r := a.Bounds()
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if !sameColor(a.At(x, y), b.At(x, y)) {
+				t.Fatalf("Pixel (%d, %d) differs: %v and %v", x, y, a.At(x, y), b.At(x, y))
+			}
+		}
+	}
+}
+
+func TestGraphRoundTrip(t *testing.T) {
+	b := image.Rect(0, 0, 64, 48)
+	noise := NewNoise(SetNoiseAlgorithm(&PerlinNoise{Seed: 3, Octaves: 2, Frequency: 0.05}), SetBounds(b))
+	terrain := NewColorMap(noise,
+		ColorStop{Position: 0, Color: color.RGBA{0, 0, 128, 255}},
+		ColorStop{Position: 0.5, Color: color.RGBA{34, 139, 34, 255}},
+		ColorStop{Position: 1, Color: color.NRGBA{255, 255, 255, 128}},
+	)
+	checker := NewChecker(color.Black, color.White, SetSpaceSize(8), SetBounds(b))
+	dots := NewPolka(SetRadius(3), SetSpacing(12), SetFillImageSource(terrain), SetBounds(b))
+	img := NewBlend(NewBlend(terrain, checker, BlendOverlay, SetBounds(b)), dots, BlendNormal, SetBounds(b))
+
+	var first bytes.Buffer
+	if err := SaveGraph(&first, img); err != nil {
+		t.Fatalf("SaveGraph failed: %v", err)
+	}
+	loaded, err := LoadGraph(bytes.NewReader(first.Bytes()))
+	if err != nil {
+		t.Fatalf("LoadGraph failed: %v\n%s", err, first.String())
+	}
+	sameImage(t, img, loaded)
+
+	// The shared terrain node is written once.
+	if n := strings.Count(first.String(), `"type": "color_map"`); n != 1 {
+		t.Errorf("Expected one color_map node, got %d", n)
+	}
+
+	var second bytes.Buffer
+	if err := SaveGraph(&second, loaded); err != nil {
+		t.Fatalf("SaveGraph of loaded graph failed: %v", err)
+	}
+	if first.String() != second.String() {
+		t.Errorf("Expected stable output, got\n%s\nthen\n%s", first.String(), second.String())
+	}
+
+	var y bytes.Buffer
+	if err := SaveGraphYAML(&y, img); err != nil {
+		t.Fatalf("SaveGraphYAML failed: %v", err)
+	}
+	fromYAML, err := LoadGraph(&y)
+	if err != nil {
+		t.Fatalf("LoadGraph of YAML failed: %v", err)
+	}
+	sameImage(t, img, fromYAML)
+}
+
+func TestLoadGraphYAML(t *testing.T) {
+	doc := `
+nodes:
+  - id: bg
+    type: checker
+    bounds: [0, 0, 20, 20]
+    params: {color1: "hsl(0, 100%, 50%)", color2: "rgb(0 0 255)"}
+    options: {SpaceSize: 5}
+  - id: out
+    type: rotate
+    inputs: {source: bg}
+    params: {degrees: 90}
+output: out
+`
+	img, err := LoadGraph(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("LoadGraph failed: %v", err)
+	}
+	want := NewRotate(NewChecker(color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255}, SetSpaceSize(5), SetBounds(image.Rect(0, 0, 20, 20))), 90)
+	sameImage(t, want, img)
+}
+
+func TestLoadGraphErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want string
+	}{
+		{"UnknownType", `{"nodes":[{"id":"a","type":"nope"}]}`, `unknown type "nope"`},
+		{"UnknownNode", `{"nodes":[{"id":"a","type":"rotate","inputs":{"source":"b"}}]}`, `unknown node "b"`},
+		{"Cycle", `{"nodes":[{"id":"a","type":"rotate","inputs":{"source":"a"}}]}`, `cycle`},
+		{"UnknownOption", `{"nodes":[{"id":"a","type":"checker","options":{"Sparkle":1}}]}`, `unknown option "Sparkle"`},
+		{"InapplicableOption", `{"nodes":[{"id":"a","type":"polka","options":{"SpaceSize":2}}]}`, `option "SpaceSize" does not apply to type "polka"`},
+		{"UnknownParam", `{"nodes":[{"id":"a","type":"checker","params":{"colour1":"red"}}]}`, `no parameter "colour1"`},
+		{"BadColor", `{"nodes":[{"id":"a","type":"checker","params":{"color1":"#12"}}]}`, `invalid colour "#12"`},
+		{"BadEnum", `{"nodes":[{"id":"a","type":"blend","params":{"mode":"dodge"}}]}`, `expected one of`},
+		{"Duplicate", `{"nodes":[{"id":"a","type":"null"},{"id":"a","type":"null"}]}`, `duplicate node id "a"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := LoadGraph(strings.NewReader(tt.doc))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestSaveGraphUnsupported(t *testing.T) {
+	img := NewRotate(image.NewRGBA(image.Rect(0, 0, 4, 4)), 90)
+	if err := SaveGraph(&bytes.Buffer{}, img); err == nil || !strings.Contains(err.Error(), "*image.RGBA") {
+		t.Errorf("Expected an error naming the unsupported type, got %v", err)
+	}
+}
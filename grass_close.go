@@ -289,3 +289,7 @@ func NewGrassClose(ops ...func(any)) image.Image {
 	}
 	return p
 }
+
+func init() {
+	RegisterPattern(generatorType("grass_close", &GrassClose{}, NewGrassClose))
+}
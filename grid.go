@@ -25,6 +25,7 @@ type Bounded interface {
 // Ensure Grid implements image.Image
 var _ image.Image = (*Grid)(nil)
 
+// Grid lays images out in rows and columns of cells.
 type Grid struct {
 	bounds      image.Rectangle
 	rows        map[int]map[int]image.Image
@@ -385,3 +386,29 @@ func (g *Grid) layout() {
 	g.cellWidths = colWidths
 	g.rowHeights = rowHeights
 }
+
+func init() {
+	RegisterPattern(&PatternType{
+		Name:     "grid",
+		Category: CategoryCompositor,
+		Inputs:   []Input{{Name: "cells", Variadic: true}},
+		Params: []Param{
+			{Name: "columns", Type: ParamInt, Default: 0, Doc: "Cells in each row, filled in turn; 0 puts them all in one row."},
+		},
+		New: func(a *Args) (image.Image, error) {
+			cells := a.InputList("cells")
+			cols := a.Int("columns")
+			if cols <= 0 {
+				cols = len(cells)
+			}
+			var ops []any
+			for i, c := range cells {
+				ops = append(ops, CellPos(i%cols, i/cols, c))
+			}
+			for _, op := range a.Options {
+				ops = append(ops, op)
+			}
+			return NewGrid(ops...), nil
+		},
+	})
+}
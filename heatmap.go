@@ -5,14 +5,17 @@ import (
 	"image/color"
 )
 
-// Ensure Heatmap implements the image.Image interface.
+// Ensure Heatmap implements the image.Image and ScalarField interfaces.
 var _ image.Image = (*Heatmap)(nil)
+var _ ScalarField = (*Heatmap)(nil)
 
 // HeatmapFunc is the function signature for the heatmap generator.
 // It accepts logical coordinates (x, y) and returns a scalar value z.
 type HeatmapFunc func(x, y float64) float64
 
 // Heatmap generates a color gradient based on a 2D scalar function.
+// It is not in the pattern registry, as its Func is Go code that no parameter can
+// express.
 type Heatmap struct {
 	Null
 	StartColor
@@ -29,23 +32,37 @@ func (h *Heatmap) At(x, y int) color.Color {
 	if b.Empty() {
 		return color.RGBA{}
 	}
+	return lerpColor(h.StartColor.StartColor, h.EndColor.EndColor, h.position(float64(x), float64(y)))
+}
+
+// ValueAt returns the luminance of the colour at pixel (x, y), at full precision.
+// Values outside the Z range are not clamped, but continue the ramp between the
+// luminances of StartColor and EndColor.
+func (h *Heatmap) ValueAt(x, y float64) float64 {
+	start := getLuminanceForMaterial(h.StartColor.StartColor)
+	end := getLuminanceForMaterial(h.EndColor.EndColor)
+	return start + h.position(x, y)*(end-start)
+}
+
+// position returns the function value at pixel (x, y) normalised by the Z range,
+// so MinZ maps to 0 and MaxZ maps to 1.
+func (h *Heatmap) position(x, y float64) float64 {
+	b := h.Bounds()
 
 	// Map pixel coordinates to logical coordinates
-	// We map the center of the pixel or the top-left?
-	// Usually mapping the coordinate directly is fine.
 	// x spans from b.Min.X to b.Max.X (exclusive)
 	// We want to map [0, width) to [MinX, MaxX)
 
 	width := float64(b.Dx())
 	height := float64(b.Dy())
 
-	if width == 0 || height == 0 {
-		return color.RGBA{}
+	if width == 0 || height == 0 || h.Func == nil {
+		return 0
 	}
 
 	// Normalized coordinates 0..1
-	nx := float64(x-b.Min.X) / width
-	ny := float64(y-b.Min.Y) / height
+	nx := (x - float64(b.Min.X)) / width
+	ny := (y - float64(b.Min.Y)) / height
 
 	// Logical coordinates
 	u := h.MinX + nx*(h.MaxX-h.MinX)
@@ -55,14 +72,10 @@ func (h *Heatmap) At(x, y int) color.Color {
 
 	// Normalize val to t for interpolation
 	// val between MinZ and MaxZ -> t between 0 and 1
-	var t float64
 	if h.MaxZ == h.MinZ {
-		t = 0.5 // Avoid division by zero, return midpoint or similar
-	} else {
-		t = (val - h.MinZ) / (h.MaxZ - h.MinZ)
+		return 0.5 // Avoid division by zero, return midpoint or similar
 	}
-
-	return lerpColor(h.StartColor.StartColor, h.EndColor.EndColor, t)
+	return (val - h.MinZ) / (h.MaxZ - h.MinZ)
 }
 
 // NewHeatmap creates a new Heatmap pattern.
@@ -6,8 +6,9 @@ import (
 	"math"
 )
 
-// Ensure HexGrid implements the image.Image interface.
+// Ensure HexGrid implements the image.Image and Sampler interfaces.
 var _ image.Image = (*HexGrid)(nil)
+var _ Sampler = (*HexGrid)(nil)
 
 // HexGrid renders an axial-coordinate hexagonal grid with alternating colors
 // and a soft inner shadow near the cell edges.
@@ -35,6 +36,16 @@ func (h *HexGrid) SetHexBevelDepth(depth float64) {
 }
 
 func (h *HexGrid) At(x, y int) color.Color {
+	return h.shade(float64(x), float64(y))
+}
+
+// SampleAt returns the colour at the continuous position (x, y).
+// At treats the integer pixel position as the sample point, so the pixel centre is shifted back.
+func (h *HexGrid) SampleAt(x, y float64) color.Color {
+	return h.shade(x-0.5, y-0.5)
+}
+
+func (h *HexGrid) shade(x, y float64) color.Color {
 	radius := h.Radius.Radius
 	if radius <= 0 {
 		radius = 24
@@ -60,8 +71,8 @@ func (h *HexGrid) At(x, y int) color.Color {
 	cy := float64(b.Min.Y+b.Max.Y) / 2.0
 
 	// Relative coordinates from center.
-	rx := float64(x) - cx
-	ry := float64(y) - cy
+	rx := x - cx
+	ry := y - cy
 
 	// Convert pixel coordinate to axial coordinate (pointy-top orientation).
 	q := (math.Sqrt(3)/3*rx - ry/3.0) / size
@@ -204,3 +215,29 @@ func SetHexBevelDepth(depth float64) func(any) {
 		}
 	}
 }
+
+func init() {
+	RegisterPattern(&PatternType{
+		Name:     "hex_grid",
+		Category: CategoryGenerator,
+		Params: []Param{
+			{Name: "palette", Type: ParamColors, Doc: "Colours of the cells."},
+			{Name: "bevel_depth", Type: ParamFloat, Default: 0.0, Min: 0, Max: 1, Doc: "Depth of the cell bevel."},
+		},
+		Sample: &HexGrid{},
+		New: func(a *Args) (image.Image, error) {
+			p := NewHexGrid().(*HexGrid)
+			if pal := a.Colors("palette"); pal != nil {
+				p.Palette = pal
+			}
+			p.BevelDepth = a.Float("bevel_depth")
+			return applyOps(p, a.Options), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			p := img.(*HexGrid)
+			a.Set("palette", p.Palette)
+			a.Set("bevel_depth", p.BevelDepth)
+			return nil
+		},
+	})
+}
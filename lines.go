@@ -3,12 +3,15 @@ package pattern
 import (
 	"image"
 	"image/color"
+	"math"
 )
 
-// Ensure HorizontalLine implements the image.Image interface.
+// Ensure HorizontalLine implements the image.Image and Sampler interfaces.
 var _ image.Image = (*HorizontalLine)(nil)
+var _ Sampler = (*HorizontalLine)(nil)
 
 // HorizontalLine is a pattern that draws horizontal lines.
+// Animated with SetTime, the lines move down one period a second.
 type HorizontalLine struct {
 	Null
 	SpaceSize
@@ -17,9 +20,14 @@ type HorizontalLine struct {
 	SpaceColor
 	LineImageSource
 	Phase
+	Time
+	AntiAlias
 }
 
 func (p *HorizontalLine) At(x, y int) color.Color {
+	if p.AntiAlias.AntiAlias {
+		return p.SampleAt(float64(x)+0.5, float64(y)+0.5)
+	}
 	ls := p.LineSize.LineSize
 	ss := p.SpaceSize.SpaceSize
 	period := ls + ss
@@ -28,7 +36,7 @@ func (p *HorizontalLine) At(x, y int) color.Color {
 	}
 
 	// Apply phase offset
-	offsetY := y - int(p.Phase.Phase)
+	offsetY := y - int(linePhase(p.Phase, p.Time, float64(period)))
 
 	// Handle negative coordinates correctly for modulo
 	mod := offsetY % period
@@ -49,6 +57,41 @@ func (p *HorizontalLine) At(x, y int) color.Color {
 	return color.RGBA{}
 }
 
+// SampleAt returns the colour at the continuous position (x, y).
+// Unlike At, the phase is not truncated to whole pixels. With AntiAlias set, the
+// line coverage of the one pixel square centred on (x, y) is computed exactly.
+func (p *HorizontalLine) SampleAt(x, y float64) color.Color {
+	ls := float64(p.LineSize.LineSize)
+	period := ls + float64(p.SpaceSize.SpaceSize)
+	if period == 0 {
+		return p.LineColor.LineColor
+	}
+
+	offset := y - linePhase(p.Phase, p.Time, period)
+
+	coverage := 0.0
+	if p.AntiAlias.AntiAlias {
+		coverage = bandCoverage(offset-0.5, offset+0.5, ls, period)
+	} else if offset-math.Floor(offset/period)*period < ls {
+		coverage = 1
+	}
+
+	line := p.LineColor.LineColor
+	if p.LineImageSource.LineImageSource != nil {
+		line = sampleImage(p.LineImageSource.LineImageSource, x, y)
+	}
+	switch coverage {
+	case 1:
+		return line
+	case 0:
+		if p.SpaceColor.SpaceColor != nil {
+			return p.SpaceColor.SpaceColor
+		}
+		return color.RGBA{}
+	}
+	return mixCoverage([]color.Color{line, p.SpaceColor.SpaceColor}, []float64{coverage, 1 - coverage})
+}
+
 // NewHorizontalLine creates a new HorizontalLine pattern.
 func NewHorizontalLine(ops ...func(any)) image.Image {
 	p := &HorizontalLine{
@@ -73,10 +116,12 @@ func NewDemoHorizontalLine(ops ...func(any)) image.Image {
 	return NewHorizontalLine(ops...)
 }
 
-// Ensure VerticalLine implements the image.Image interface.
+// Ensure VerticalLine implements the image.Image and Sampler interfaces.
 var _ image.Image = (*VerticalLine)(nil)
+var _ Sampler = (*VerticalLine)(nil)
 
 // VerticalLine is a pattern that draws vertical lines.
+// Animated with SetTime, the lines move right one period a second.
 type VerticalLine struct {
 	Null
 	SpaceSize
@@ -85,9 +130,14 @@ type VerticalLine struct {
 	SpaceColor
 	LineImageSource
 	Phase
+	Time
+	AntiAlias
 }
 
 func (p *VerticalLine) At(x, y int) color.Color {
+	if p.AntiAlias.AntiAlias {
+		return p.SampleAt(float64(x)+0.5, float64(y)+0.5)
+	}
 	ls := p.LineSize.LineSize
 	ss := p.SpaceSize.SpaceSize
 	period := ls + ss
@@ -96,7 +146,7 @@ func (p *VerticalLine) At(x, y int) color.Color {
 	}
 
 	// Apply phase offset
-	offsetX := x - int(p.Phase.Phase)
+	offsetX := x - int(linePhase(p.Phase, p.Time, float64(period)))
 
 	// Handle negative coordinates correctly for modulo
 	mod := offsetX % period
@@ -117,6 +167,41 @@ func (p *VerticalLine) At(x, y int) color.Color {
 	return color.RGBA{}
 }
 
+// SampleAt returns the colour at the continuous position (x, y).
+// Unlike At, the phase is not truncated to whole pixels. With AntiAlias set, the
+// line coverage of the one pixel square centred on (x, y) is computed exactly.
+func (p *VerticalLine) SampleAt(x, y float64) color.Color {
+	ls := float64(p.LineSize.LineSize)
+	period := ls + float64(p.SpaceSize.SpaceSize)
+	if period == 0 {
+		return p.LineColor.LineColor
+	}
+
+	offset := x - linePhase(p.Phase, p.Time, period)
+
+	coverage := 0.0
+	if p.AntiAlias.AntiAlias {
+		coverage = bandCoverage(offset-0.5, offset+0.5, ls, period)
+	} else if offset-math.Floor(offset/period)*period < ls {
+		coverage = 1
+	}
+
+	line := p.LineColor.LineColor
+	if p.LineImageSource.LineImageSource != nil {
+		line = sampleImage(p.LineImageSource.LineImageSource, x, y)
+	}
+	switch coverage {
+	case 1:
+		return line
+	case 0:
+		if p.SpaceColor.SpaceColor != nil {
+			return p.SpaceColor.SpaceColor
+		}
+		return color.RGBA{}
+	}
+	return mixCoverage([]color.Color{line, p.SpaceColor.SpaceColor}, []float64{coverage, 1 - coverage})
+}
+
 // NewVerticalLine creates a new VerticalLine pattern.
 func NewVerticalLine(ops ...func(any)) image.Image {
 	p := &VerticalLine{
@@ -140,3 +225,16 @@ func NewVerticalLine(ops ...func(any)) image.Image {
 func NewDemoVerticalLine(ops ...func(any)) image.Image {
 	return NewVerticalLine(ops...)
 }
+
+func init() {
+	RegisterPattern(generatorType("horizontal_line", &HorizontalLine{}, NewHorizontalLine))
+	RegisterPattern(generatorType("vertical_line", &VerticalLine{}, NewVerticalLine))
+	RegisterPattern(generatorType("demo_horizontal_line", nil, NewDemoHorizontalLine))
+	RegisterPattern(generatorType("demo_vertical_line", nil, NewDemoVerticalLine))
+}
+
+// linePhase is the offset of a line pattern with a period of the given length,
+// moved on one period for each second of time.
+func linePhase(phase Phase, time Time, period float64) float64 {
+	return phase.Phase + time.Time*period
+}
@@ -0,0 +1,345 @@
+package pattern
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"sync"
+)
+
+// Ensure LowPoly implements the image.Image interface.
+var _ image.Image = (*LowPoly)(nil)
+
+// LowPolyFill selects how LowPoly colours its triangles.
+type LowPolyFill int
+
+const (
+	// LowPolyCentroid fills each triangle with the colour of the source at its
+	// centroid.
+	LowPolyCentroid LowPolyFill = iota
+	// LowPolyAverage fills each triangle with the average colour of the source
+	// over it.
+	LowPolyAverage
+	// LowPolyGradient shades each triangle smoothly between the colours of the
+	// source at its corners.
+	LowPolyGradient
+)
+
+// lowPolyFillNames are the names of the LowPolyFill values in the pattern registry.
+var lowPolyFillNames = []string{"centroid", "average", "gradient"}
+
+// LowPoly draws Source as faceted triangles: the Delaunay triangulation of Points
+// and of points spaced around the edges of the bounds, so the facets cover the
+// whole image. Each is filled with a single colour of the source or a gradient
+// across it. Where StrokeWidth is positive the edges of the triangles are drawn in
+// StrokeColor.
+//
+// The triangulation, and the average colours, are worked out the first time the
+// pattern is drawn, so Points, Source and the bounds should not change after that.
+type LowPoly struct {
+	Null
+	Source      image.Image
+	Points      []image.Point
+	Fill        LowPolyFill
+	StrokeWidth float64
+	StrokeColor color.Color
+
+	once      sync.Once
+	vertices  []image.Point
+	triangles []Triangle
+	index     *lowPolyIndex
+	averages  []color.Color
+}
+
+// lowPolyIndex buckets triangles into the square cells of a grid that they
+// overlap, so finding the triangle under a pixel need only test a few.
+type lowPolyIndex struct {
+	minX, minY float64
+	size       float64
+	cols, rows int
+	cells      [][]int
+}
+
+func (l *LowPoly) init() {
+	l.once.Do(func() {
+		l.vertices = append(append([]image.Point(nil), l.Points...), l.edgePoints()...)
+		l.triangles = Delaunay(l.vertices)
+		l.index = l.buildIndex()
+		if l.Fill == LowPolyAverage && l.Source != nil {
+			l.averages = l.average()
+		}
+	})
+}
+
+// edgePoints returns points around the edges of the bounds, corners included,
+// about as far apart as the Points are, so the triangles along the edges are no
+// thinner than the rest.
+func (l *LowPoly) edgePoints() []image.Point {
+	b := l.Bounds()
+	spacing := math.Sqrt(float64(b.Dx()*b.Dy()) / float64(len(l.Points)+1))
+	var points []image.Point
+	side := func(from, to image.Point) {
+		length := math.Max(float64(to.X-from.X), float64(to.Y-from.Y))
+		n := int(math.Max(math.Round(length/spacing), 1))
+		for i := 0; i < n; i++ {
+			f := float64(i) / float64(n)
+			points = append(points, image.Pt(from.X+int(f*float64(to.X-from.X)), from.Y+int(f*float64(to.Y-from.Y))))
+		}
+	}
+	side(b.Min, image.Pt(b.Max.X, b.Min.Y))
+	side(image.Pt(b.Max.X, b.Min.Y), b.Max)
+	side(image.Pt(b.Min.X, b.Max.Y), b.Max)
+	side(b.Min, image.Pt(b.Min.X, b.Max.Y))
+	// The far corner ends the sides running towards it.
+	return append(points, b.Max)
+}
+
+func (l *LowPoly) buildIndex() *lowPolyIndex {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range l.vertices {
+		minX, minY = math.Min(minX, float64(p.X)), math.Min(minY, float64(p.Y))
+		maxX, maxY = math.Max(maxX, float64(p.X)), math.Max(maxY, float64(p.Y))
+	}
+	w, h := maxX-minX, maxY-minY
+	idx := &lowPolyIndex{minX: minX, minY: minY}
+	// Cells about the size of a triangle hold only a few of them each.
+	idx.size = math.Max(math.Sqrt(w*h/float64(len(l.triangles)+1)), 1)
+	idx.cols = int(w/idx.size) + 1
+	idx.rows = int(h/idx.size) + 1
+	idx.cells = make([][]int, idx.cols*idx.rows)
+	for i, t := range l.triangles {
+		tMinX, tMinY := math.Inf(1), math.Inf(1)
+		tMaxX, tMaxY := math.Inf(-1), math.Inf(-1)
+		for _, k := range t {
+			p := l.vertices[k]
+			tMinX, tMinY = math.Min(tMinX, float64(p.X)), math.Min(tMinY, float64(p.Y))
+			tMaxX, tMaxY = math.Max(tMaxX, float64(p.X)), math.Max(tMaxY, float64(p.Y))
+		}
+		for cy := int((tMinY - minY) / idx.size); cy <= int((tMaxY-minY)/idx.size); cy++ {
+			for cx := int((tMinX - minX) / idx.size); cx <= int((tMaxX-minX)/idx.size); cx++ {
+				idx.cells[cy*idx.cols+cx] = append(idx.cells[cy*idx.cols+cx], i)
+			}
+		}
+	}
+	return idx
+}
+
+// barycentric returns the barycentric coordinates of (x, y) in triangle t.
+func (l *LowPoly) barycentric(t Triangle, x, y float64) (float64, float64, float64) {
+	a, b, c := l.vertices[t[0]], l.vertices[t[1]], l.vertices[t[2]]
+	ax, ay := float64(a.X), float64(a.Y)
+	bx, by := float64(b.X)-ax, float64(b.Y)-ay
+	cx, cy := float64(c.X)-ax, float64(c.Y)-ay
+	px, py := x-ax, y-ay
+	d := bx*cy - by*cx
+	u := (px*cy - py*cx) / d
+	v := (bx*py - by*px) / d
+	return 1 - u - v, u, v
+}
+
+// triangleAt returns the index of the triangle containing (x, y), or -1. Of
+// triangles sharing an edge through the point, the first wins.
+func (l *LowPoly) triangleAt(x, y float64) int {
+	idx := l.index
+	cx, cy := int(math.Floor((x-idx.minX)/idx.size)), int(math.Floor((y-idx.minY)/idx.size))
+	if cx < 0 || cy < 0 || cx >= idx.cols || cy >= idx.rows {
+		return -1
+	}
+	for _, i := range idx.cells[cy*idx.cols+cx] {
+		if l.contains(l.triangles[i], x, y) {
+			return i
+		}
+	}
+	return -1
+}
+
+// contains reports whether (x, y) is inside t or on its edges. The corners are
+// whole pixels and the point a pixel centre, so the sides are measured exactly
+// and a point on an edge is in both triangles sharing it.
+func (l *LowPoly) contains(t Triangle, x, y float64) bool {
+	for k := 0; k < 3; k++ {
+		a, b := l.vertices[t[k]], l.vertices[t[(k+1)%3]]
+		ax, ay := float64(a.X), float64(a.Y)
+		if (float64(b.X)-ax)*(y-ay)-(float64(b.Y)-ay)*(x-ax) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// average returns the average colour of the source over each triangle.
+func (l *LowPoly) average() []color.Color {
+	sums := make([][4]float64, len(l.triangles))
+	counts := make([]float64, len(l.triangles))
+	b := l.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			i := l.triangleAt(float64(x)+0.5, float64(y)+0.5)
+			if i < 0 {
+				continue
+			}
+			r, g, bl, a := l.Source.At(x, y).RGBA()
+			sums[i][0] += float64(r)
+			sums[i][1] += float64(g)
+			sums[i][2] += float64(bl)
+			sums[i][3] += float64(a)
+			counts[i]++
+		}
+	}
+	averages := make([]color.Color, len(l.triangles))
+	for i, s := range sums {
+		if counts[i] == 0 {
+			// Slivers too thin to hold a pixel centre take their centroid colour.
+			averages[i] = l.centroidColor(l.triangles[i])
+			continue
+		}
+		n := counts[i]
+		averages[i] = color.RGBA64{uint16(s[0]/n + 0.5), uint16(s[1]/n + 0.5), uint16(s[2]/n + 0.5), uint16(s[3]/n + 0.5)}
+	}
+	return averages
+}
+
+// centroidColor returns the colour of the source at the centroid of t.
+func (l *LowPoly) centroidColor(t Triangle) color.Color {
+	var x, y float64
+	for _, k := range t {
+		x += float64(l.vertices[k].X)
+		y += float64(l.vertices[k].Y)
+	}
+	return l.Source.At(int(math.Floor(x/3)), int(math.Floor(y/3)))
+}
+
+func (l *LowPoly) At(x, y int) color.Color {
+	if l.Source == nil {
+		return color.Transparent
+	}
+	l.init()
+	px, py := float64(x)+0.5, float64(y)+0.5
+	i := l.triangleAt(px, py)
+	if i < 0 {
+		return color.Transparent
+	}
+	t := l.triangles[i]
+	if l.StrokeWidth > 0 && l.edgeDistance(t, px, py) < l.StrokeWidth/2 {
+		if l.StrokeColor == nil {
+			return color.Black
+		}
+		return l.StrokeColor
+	}
+	switch l.Fill {
+	case LowPolyAverage:
+		return l.averages[i]
+	case LowPolyGradient:
+		w0, w1, w2 := l.barycentric(t, px, py)
+		var sum [4]float64
+		for k, w := range [3]float64{w0, w1, w2} {
+			p := l.vertices[t[k]]
+			// Corners on the far edges of the bounds are read from just inside.
+			r, g, b, a := l.Source.At(l.clampX(p.X), l.clampY(p.Y)).RGBA()
+			sum[0] += w * float64(r)
+			sum[1] += w * float64(g)
+			sum[2] += w * float64(b)
+			sum[3] += w * float64(a)
+		}
+		return color.RGBA64{uint16(sum[0] + 0.5), uint16(sum[1] + 0.5), uint16(sum[2] + 0.5), uint16(sum[3] + 0.5)}
+	}
+	return l.centroidColor(t)
+}
+
+func (l *LowPoly) clampX(x int) int {
+	if b := l.Bounds(); x >= b.Max.X && b.Max.X > b.Min.X {
+		return b.Max.X - 1
+	}
+	return x
+}
+
+func (l *LowPoly) clampY(y int) int {
+	if b := l.Bounds(); y >= b.Max.Y && b.Max.Y > b.Min.Y {
+		return b.Max.Y - 1
+	}
+	return y
+}
+
+// edgeDistance returns the distance from (x, y) to the nearest edge of t.
+func (l *LowPoly) edgeDistance(t Triangle, x, y float64) float64 {
+	d := math.Inf(1)
+	for k := 0; k < 3; k++ {
+		a, b := l.vertices[t[k]], l.vertices[t[(k+1)%3]]
+		ax, ay := float64(a.X), float64(a.Y)
+		ex, ey := float64(b.X)-ax, float64(b.Y)-ay
+		s := clamp01(((x-ax)*ex + (y-ay)*ey) / (ex*ex + ey*ey))
+		d = math.Min(d, math.Hypot(x-ax-s*ex, y-ay-s*ey))
+	}
+	return d
+}
+
+// NewLowPoly creates a LowPoly pattern drawing source as triangles between points.
+// The bounds default to those of the source.
+func NewLowPoly(source image.Image, points []image.Point, ops ...func(any)) image.Image {
+	p := &LowPoly{
+		Null: Null{
+			bounds: image.Rect(0, 0, 255, 255),
+		},
+		Source:      source,
+		Points:      points,
+		StrokeColor: color.Black,
+	}
+	if source != nil {
+		p.bounds = source.Bounds()
+	}
+	for _, op := range ops {
+		op(p)
+	}
+	return p
+}
+
+// SetLowPolyFill sets how a LowPoly colours its triangles.
+func SetLowPolyFill(f LowPolyFill) func(any) {
+	return func(i any) {
+		if p, ok := i.(*LowPoly); ok {
+			p.Fill = f
+		}
+	}
+}
+
+// SetLowPolyStroke draws the edges of the triangles of a LowPoly width pixels wide.
+func SetLowPolyStroke(width float64, c color.Color) func(any) {
+	return func(i any) {
+		if p, ok := i.(*LowPoly); ok {
+			p.StrokeWidth = width
+			p.StrokeColor = c
+		}
+	}
+}
+
+func init() {
+	RegisterPattern(&PatternType{
+		Name:     "low_poly",
+		Category: CategoryFilter,
+		Inputs:   []Input{{Name: "source"}},
+		Params: []Param{
+			{Name: "points", Type: ParamPoints, Doc: "Triangle corners, besides those of the bounds."},
+			{Name: "fill", Type: ParamEnum, Default: "centroid", Values: lowPolyFillNames, Doc: "How triangles are coloured."},
+			{Name: "stroke_width", Type: ParamFloat, Default: 0.0, Min: 0, Doc: "Width of the triangle edges in pixels."},
+			{Name: "stroke_color", Type: ParamColor, Default: color.Black, Doc: "Colour of the triangle edges."},
+		},
+		Sample: &LowPoly{},
+		New: func(a *Args) (image.Image, error) {
+			p := NewLowPoly(a.Input("source"), a.Points("points")).(*LowPoly)
+			p.Fill = LowPolyFill(a.Enum("fill", lowPolyFillNames))
+			p.StrokeWidth = a.Float("stroke_width")
+			p.StrokeColor = a.Color("stroke_color")
+			return applyOps(p, a.Options), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			p := img.(*LowPoly)
+			a.SetInput("source", p.Source)
+			a.Set("points", p.Points)
+			a.Set("fill", enumName(lowPolyFillNames, int(p.Fill)))
+			a.Set("stroke_width", p.StrokeWidth)
+			a.Set("stroke_color", p.StrokeColor)
+			return nil
+		},
+	})
+}
@@ -0,0 +1,79 @@
+package pattern
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+)
+
+var LowPolyOutputFilename = "low_poly.png"
+var LowPolyZoomLevels = []int{}
+
+const LowPolyOrder = 26
+const LowPolyBaseLabel = "Centroid"
+
+// LowPoly Pattern
+// Draws an image as faceted triangles between scattered points, for low poly
+// backgrounds.
+func ExampleNewLowPoly() {
+	noise := NewNoise(SetNoiseAlgorithm(&PerlinNoise{Seed: 11, Frequency: 0.012, Octaves: 3, Persistence: 0.5}))
+	sky := NewColorMap(noise,
+		ColorStop{Position: 0.2, Color: color.RGBA{40, 30, 90, 255}},
+		ColorStop{Position: 0.45, Color: color.RGBA{170, 60, 120, 255}},
+		ColorStop{Position: 0.65, Color: color.RGBA{250, 140, 80, 255}},
+		ColorStop{Position: 0.85, Color: color.RGBA{255, 220, 140, 255}},
+	)
+	// Points spread evenly, about 24 pixels apart, make even facets
+	points := PoissonDiskPoints(sky.Bounds(), 24, 7, nil)
+	i := NewLowPoly(sky, points)
+	f, err := os.Create(LowPolyOutputFilename)
+	if err != nil {
+		panic(err)
+	}
+	defer func() {
+		if e := f.Close(); e != nil {
+			panic(e)
+		}
+	}()
+	if err = png.Encode(f, i); err != nil {
+		panic(err)
+	}
+}
+
+// lowPolySource is the dusk sky of ExampleNewLowPoly, within b.
+func lowPolySource(b image.Rectangle) image.Image {
+	noise := NewNoise(SetBounds(b), SetNoiseAlgorithm(&PerlinNoise{Seed: 11, Frequency: 0.012, Octaves: 3, Persistence: 0.5}))
+	return NewColorMap(noise,
+		ColorStop{Position: 0.2, Color: color.RGBA{40, 30, 90, 255}},
+		ColorStop{Position: 0.45, Color: color.RGBA{170, 60, 120, 255}},
+		ColorStop{Position: 0.65, Color: color.RGBA{250, 140, 80, 255}},
+		ColorStop{Position: 0.85, Color: color.RGBA{255, 220, 140, 255}},
+	)
+}
+
+func GenerateLowPoly(b image.Rectangle) image.Image {
+	return NewLowPoly(lowPolySource(b), PoissonDiskPoints(b, 24, 7, nil))
+}
+
+func GenerateLowPolyReferences() (map[string]func(image.Rectangle) image.Image, []string) {
+	return map[string]func(image.Rectangle) image.Image{
+		"Source": func(b image.Rectangle) image.Image {
+			return lowPolySource(b)
+		},
+		"Average": func(b image.Rectangle) image.Image {
+			return NewLowPoly(lowPolySource(b), PoissonDiskPoints(b, 24, 7, nil), SetLowPolyFill(LowPolyAverage))
+		},
+		"Gradient": func(b image.Rectangle) image.Image {
+			return NewLowPoly(lowPolySource(b), PoissonDiskPoints(b, 24, 7, nil), SetLowPolyFill(LowPolyGradient))
+		},
+		"Stroked": func(b image.Rectangle) image.Image {
+			return NewLowPoly(lowPolySource(b), PoissonDiskPoints(b, 24, 7, nil), SetLowPolyStroke(1, color.RGBA{255, 255, 255, 255}))
+		},
+	}, []string{"Source", "Average", "Gradient", "Stroked"}
+}
+
+func init() {
+	RegisterGenerator("LowPoly", GenerateLowPoly)
+	RegisterReferences("LowPoly", GenerateLowPolyReferences)
+}
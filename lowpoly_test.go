@@ -0,0 +1,87 @@
+package pattern
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestLowPolyCoverage(t *testing.T) {
+	b := image.Rect(0, 0, 80, 60)
+	src := NewRect(SetBounds(b), SetFillColor(color.RGBA{200, 100, 50, 255}))
+	for _, fill := range []LowPolyFill{LowPolyCentroid, LowPolyAverage, LowPolyGradient} {
+		l := NewLowPoly(src, PoissonDiskPoints(b, 9, 1, nil), SetLowPolyFill(fill))
+		// A flat source stays flat, and every pixel is covered.
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				if got := l.At(x, y); !sameColor(got, src.At(x, y)) {
+					t.Fatalf("%s: At(%d, %d) = %v, want %v", lowPolyFillNames[fill], x, y, got, src.At(x, y))
+				}
+			}
+		}
+	}
+}
+
+func TestLowPolyFill(t *testing.T) {
+	// With no points a square is split on a diagonal into two triangles.
+	b := image.Rect(0, 0, 10, 10)
+	src := NewGeneric(func(x, y int) color.Color {
+		return color.Gray{Y: uint8(x * 20)}
+	})
+	l := NewLowPoly(src, nil, SetBounds(b), SetLowPolyFill(LowPolyAverage)).(*LowPoly)
+	top, bottom := l.At(2, 2), l.At(7, 7)
+	if top == bottom {
+		t.Fatalf("both halves are %v", top)
+	}
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 10; x++ {
+			want := top
+			if l.triangleAt(float64(x)+0.5, float64(y)+0.5) != l.triangleAt(2.5, 2.5) {
+				want = bottom
+			}
+			if got := l.At(x, y); got != want {
+				t.Fatalf("At(%d, %d) = %v, want %v", x, y, got, want)
+			}
+		}
+	}
+
+	// A gradient follows a linear source exactly, though the far corners are read
+	// from the last pixel inside, 180 rather than 200.
+	l = NewLowPoly(src, nil, SetBounds(b), SetLowPolyFill(LowPolyGradient)).(*LowPoly)
+	for x := 0; x < 9; x++ {
+		if got, want := l.At(x, 4).(color.RGBA64).R, uint16((float64(x)+0.5)*18*0x101+0.5); got != want {
+			t.Errorf("gradient At(%d, 4) = %d, want %d", x, got, want)
+		}
+	}
+}
+
+func TestLowPolyStroke(t *testing.T) {
+	b := image.Rect(0, 0, 20, 20)
+	src := NewRect(SetBounds(b), SetFillColor(color.White))
+	l := NewLowPoly(src, []image.Point{{10, 10}}, SetLowPolyStroke(2, color.RGBA{255, 0, 0, 255}))
+	// The centre point is joined to every corner.
+	for _, p := range []image.Point{{5, 5}, {14, 14}, {5, 14}, {9, 9}} {
+		if got := l.At(p.X, p.Y); got != (color.RGBA{255, 0, 0, 255}) {
+			t.Errorf("At(%v) = %v, want the stroke", p, got)
+		}
+	}
+	if got := l.At(10, 3); !sameColor(got, color.White) {
+		t.Errorf("At(10, 3) = %v, want white", got)
+	}
+}
+
+func TestLowPolyGraph(t *testing.T) {
+	b := image.Rect(0, 0, 40, 30)
+	src := NewDemoVoronoi(SetBounds(b))
+	img := NewLowPoly(src, HaltonPoints(b, 12, 2), SetLowPolyFill(LowPolyAverage), SetLowPolyStroke(1, color.White))
+	var buf bytes.Buffer
+	if err := SaveGraph(&buf, img); err != nil {
+		t.Fatalf("SaveGraph failed: %v", err)
+	}
+	loaded, err := LoadGraph(&buf)
+	if err != nil {
+		t.Fatalf("LoadGraph failed: %v", err)
+	}
+	sameImage(t, img, loaded)
+}
@@ -21,7 +21,15 @@ func (ao *AmbientOcclusion) At(x, y int) color.Color {
 	// We check if neighbors are higher than the current pixel (plus a bias)
 	// and if so, how much they "occlude".
 
-	centerHeight := getLuminanceForMaterial(ao.Source.At(x, y))
+	field, isField := ao.Source.(ScalarField)
+	height := func(x, y int) float64 {
+		if isField {
+			return field.ValueAt(float64(x), float64(y))
+		}
+		return getLuminanceForMaterial(ao.Source.At(x, y))
+	}
+
+	centerHeight := height(x, y)
 
 	totalOcclusion := 0.0
 	samples := 0.0
@@ -43,7 +51,7 @@ func (ao *AmbientOcclusion) At(x, y int) color.Color {
 				continue
 			}
 
-			h := getLuminanceForMaterial(ao.Source.At(x+i, y+j))
+			h := height(x+i, y+j)
 
 			// Logic: If neighbor is higher, it might occlude.
 			// The occlusion depends on the height difference and distance.
@@ -211,3 +219,29 @@ func AOFromHeight(source image.Image, radius int) image.Image {
 func CurvatureFromHeight(source image.Image) image.Image {
 	return NewCurvature(source)
 }
+
+func init() {
+	RegisterPattern(&PatternType{
+		Name:     "ambient_occlusion",
+		Category: CategoryFilter,
+		Inputs:   []Input{{Name: "source"}},
+		Params: []Param{
+			{Name: "radius", Type: ParamInt, Default: 2, Min: 1, Max: 64, Doc: "Sampling radius of the height map."},
+		},
+		Sample: &AmbientOcclusion{},
+		New: func(a *Args) (image.Image, error) {
+			p := NewAmbientOcclusion(a.Input("source")).(*AmbientOcclusion)
+			p.Radius = a.Int("radius")
+			return applyOps(p, a.Options), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			p := img.(*AmbientOcclusion)
+			a.SetInput("source", p.Source)
+			a.Set("radius", p.Radius)
+			return nil
+		},
+	})
+	RegisterPattern(filterType("curvature", &Curvature{}, NewCurvature, func(img image.Image) image.Image {
+		return img.(*Curvature).Source
+	}))
+}
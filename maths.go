@@ -12,6 +12,8 @@ var _ image.Image = (*Maths)(nil)
 type MathsFunc func(x, y int) color.Color
 
 // Maths is a pattern that generates colors based on a provided function.
+// It is not in the pattern registry, as its Func is Go code that no parameter can
+// express.
 type Maths struct {
 	Null
 	Func MathsFunc
@@ -55,3 +55,26 @@ func NewMirror(img image.Image, horizontal, vertical bool, ops ...func(any)) ima
 	}
 	return m
 }
+
+func init() {
+	RegisterPattern(&PatternType{
+		Name:     "mirror",
+		Category: CategoryFilter,
+		Inputs:   []Input{{Name: "source"}},
+		Params: []Param{
+			{Name: "horizontal", Type: ParamBool, Default: false, Doc: "Flip left to right."},
+			{Name: "vertical", Type: ParamBool, Default: false, Doc: "Flip top to bottom."},
+		},
+		Sample: &Mirror{},
+		New: func(a *Args) (image.Image, error) {
+			return NewMirror(a.Input("source"), a.Bool("horizontal"), a.Bool("vertical"), a.Options...), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			p := img.(*Mirror)
+			a.SetInput("source", p.img)
+			a.Set("horizontal", p.horizontal)
+			a.Set("vertical", p.vertical)
+			return nil
+		},
+	})
+}
@@ -48,3 +48,21 @@ func NewModuloStripe(colors []color.Color, ops ...func(any)) image.Image {
 	}
 	return p
 }
+
+func init() {
+	RegisterPattern(&PatternType{
+		Name:     "modulo_stripe",
+		Category: CategoryGenerator,
+		Params: []Param{
+			{Name: "colors", Type: ParamColors, Default: []color.Color{color.Black, color.White}, Doc: "Colours of the diagonal stripes, used in turn."},
+		},
+		Sample: &ModuloStripe{},
+		New: func(a *Args) (image.Image, error) {
+			return NewModuloStripe(a.Colors("colors"), a.Options...), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			a.Set("colors", img.(*ModuloStripe).Colors)
+			return nil
+		},
+	})
+}
@@ -2,6 +2,7 @@ package pattern
 
 import (
 	"crypto/rand"
+	"fmt"
 	"image"
 	"image/color"
 	"math"
@@ -9,8 +10,9 @@ import (
 	"sync"
 )
 
-// Ensure Noise implements the image.Image interface.
+// Ensure Noise implements the image.Image and ScalarField interfaces.
 var _ image.Image = (*Noise)(nil)
+var _ ScalarField = (*Noise)(nil)
 
 // NoiseAlgorithm defines the source of randomness for the Noise pattern.
 type NoiseAlgorithm interface {
@@ -30,29 +32,84 @@ func (n *Noise) At(x, y int) color.Color {
 	return color.RGBA{0, 0, 0, 255}
 }
 
+// ValueAt returns the full precision noise value when the algorithm is a ScalarField,
+// otherwise the luminance of the algorithm's colour at the containing pixel.
+func (n *Noise) ValueAt(x, y float64) float64 {
+	if n.algo == nil {
+		return 0
+	}
+	if f, ok := n.algo.(ScalarField); ok {
+		return f.ValueAt(x, y)
+	}
+	return getLuminanceForMaterial(n.algo.At(int(math.Floor(x)), int(math.Floor(y))))
+}
+
 // SetSeedUint64 sets the seed for the noise algorithm.
 // It switches to HashNoise if the current algo is CryptoNoise.
 func (n *Noise) SetSeedUint64(v uint64) {
-	switch algo := n.algo.(type) {
-	case *CryptoNoise:
-		n.algo = &HashNoise{Seed: int64(v)}
-	case *HashNoise:
-		algo.Seed = int64(v)
-	case *PerlinNoise:
-		algo.Seed = int64(v)
-	}
+	n.algo = seedNoiseAlgorithm(n.algo, int64(v))
 }
 
 // SetSeed sets the seed for the noise algorithm.
 // It switches to HashNoise if the current algo is CryptoNoise.
 func (n *Noise) SetSeed(v int64) {
-	switch algo := n.algo.(type) {
+	n.algo = seedNoiseAlgorithm(n.algo, v)
+}
+
+// seedNoiseAlgorithm sets the seed of algo, or of the base of a FractalNoise or
+// DomainWarpNoise, and
+// returns it. CryptoNoise cannot be seeded, so HashNoise is returned in its place;
+// other algorithms are seeded if they take a seed option.
+func seedNoiseAlgorithm(algo NoiseAlgorithm, v int64) NoiseAlgorithm {
+	switch algo := algo.(type) {
 	case *CryptoNoise:
-		n.algo = &HashNoise{Seed: v}
+		return &HashNoise{Seed: v}
 	case *HashNoise:
 		algo.Seed = v
 	case *PerlinNoise:
 		algo.Seed = v
+	case *OpenSimplexNoise:
+		algo.Seed = v
+	case *ValueNoise:
+		algo.Seed = v
+	case *FractalNoise:
+		if base, ok := algo.Base.(NoiseAlgorithm); ok {
+			if base, ok := seedNoiseAlgorithm(base, v).(ScalarField); ok {
+				algo.Base = base
+			}
+		}
+	case *DomainWarpNoise:
+		if base, ok := algo.Base.(NoiseAlgorithm); ok {
+			if base, ok := seedNoiseAlgorithm(base, v).(ScalarField); ok {
+				algo.Base = base
+			}
+		}
+	default:
+		if s, ok := algo.(hasSeed); ok {
+			s.SetSeed(v)
+		}
+	}
+	return algo
+}
+
+// SetPeriod makes the noise tile every x by y pixels, when the algorithm can.
+func (n *Noise) SetPeriod(x, y int) {
+	if p, ok := n.algo.(hasPeriod); ok {
+		p.SetPeriod(x, y)
+	}
+}
+
+// SetTime moves the noise to t seconds, when the algorithm is animated.
+func (n *Noise) SetTime(t float64) {
+	if p, ok := n.algo.(hasTime); ok {
+		p.SetTime(t)
+	}
+}
+
+// SetLoop makes an animated algorithm repeat every loop seconds.
+func (n *Noise) SetLoop(loop float64) {
+	if p, ok := n.algo.(hasLoop); ok {
+		p.SetLoop(loop)
 	}
 }
 
@@ -140,13 +197,30 @@ func (h *HashNoise) At(x, y int) color.Color {
 	return color.Gray{Y: uint8(z)}
 }
 
+// ValueAt returns the hash value of the pixel containing (x, y) in [0, 1].
+func (h *HashNoise) ValueAt(x, y float64) float64 {
+	z := StableHash(int(math.Floor(x)), int(math.Floor(y)), uint64(h.Seed))
+	return float64(uint8(z)) / 255.0
+}
+
 // PerlinNoise implements Improved Perlin Noise with Fractional Brownian Motion (fBm).
+// ValueAt3 and ValueAt4 extend it to 3D and 4D gradient noise whose z = w = 0
+// slice is the 2D noise.
+//
+// With a Period, the noise tiles exactly: the frequency of each octave is adjusted
+// so a whole number of lattice cells fits the period. Animated with SetTime, the
+// noise moves through the third dimension one lattice cell a second. With a Loop as
+// well, time goes round a circle through the third and fourth dimensions instead,
+// so the animation repeats every Loop seconds.
 type PerlinNoise struct {
 	Seed        int64
 	Octaves     int
 	Persistence float64 // Alpha
 	Lacunarity  float64 // Beta
 	Frequency   float64
+	Period
+	Time
+	Loop
 
 	p    [512]int
 	once sync.Once
@@ -154,19 +228,7 @@ type PerlinNoise struct {
 
 func (n *PerlinNoise) init() {
 	n.once.Do(func() {
-		// Default parameters if zero
-		if n.Frequency == 0 {
-			n.Frequency = 0.02
-		}
-		if n.Octaves == 0 {
-			n.Octaves = 1
-		}
-		if n.Lacunarity == 0 {
-			n.Lacunarity = 2.0
-		}
-		if n.Persistence == 0 {
-			n.Persistence = 0.5
-		}
+		n.Frequency, n.Octaves, n.Lacunarity, n.Persistence = noiseDefaults(n.Frequency, n.Octaves, n.Lacunarity, n.Persistence)
 
 		r := mrand.New(mrand.NewSource(n.Seed))
 		perm := make([]int, 256)
@@ -185,15 +247,55 @@ func (n *PerlinNoise) init() {
 }
 
 func (n *PerlinNoise) At(x, y int) color.Color {
+	normalized := clamp01(n.ValueAt(float64(x), float64(y)))
+	c := uint8(normalized * 255)
+	return color.Gray{Y: c}
+}
+
+// ValueAt returns the fBm sum at (x, y) and the current time, mapped to roughly
+// [0, 1], without clamping or quantisation.
+func (n *PerlinNoise) ValueAt(x, y float64) float64 {
 	n.init()
+	if n.Time.Time == 0 {
+		return n.fbm(x, y, 0, 0)
+	}
+	// z and w are in pixels, like x and y, so time is scaled to one lattice cell
+	// a second at the base frequency.
+	if n.Loop.Loop > 0 {
+		// The circle passes through z = w = 0 at time 0, and is one Loop round.
+		r := n.Loop.Loop / (2 * math.Pi) / n.Frequency
+		a := 2 * math.Pi * n.Time.Time / n.Loop.Loop
+		return n.fbm(x, y, r*(math.Cos(a)-1), r*math.Sin(a))
+	}
+	return n.fbm(x, y, n.Time.Time/n.Frequency, 0)
+}
 
+// ValueAt3 returns 3D fBm noise at (x, y, z), mapped to roughly [0, 1]. All three
+// coordinates are in pixels.
+func (n *PerlinNoise) ValueAt3(x, y, z float64) float64 {
+	n.init()
+	return n.fbm(x, y, z, 0)
+}
+
+// ValueAt4 returns 4D fBm noise at (x, y, z, w), mapped to roughly [0, 1]. All four
+// coordinates are in pixels.
+func (n *PerlinNoise) ValueAt4(x, y, z, w float64) float64 {
+	n.init()
+	return n.fbm(x, y, z, w)
+}
+
+// fbm sums the octaves of the noise at (x, y, z, w) and maps the result to roughly
+// [0, 1].
+func (n *PerlinNoise) fbm(x, y, z, w float64) float64 {
 	var total float64
 	var maxAmplitude float64
 	amplitude := 1.0
 	frequency := n.Frequency
 
 	for i := 0; i < n.Octaves; i++ {
-		total += n.noise(float64(x)*frequency, float64(y)*frequency) * amplitude
+		cellsX, fx := periodCells(n.PeriodX, frequency)
+		cellsY, fy := periodCells(n.PeriodY, frequency)
+		total += n.noise(x*fx, y*fy, z*frequency, w*frequency, cellsX, cellsY) * amplitude
 		maxAmplitude += amplitude
 		amplitude *= n.Persistence
 		frequency *= n.Lacunarity
@@ -204,37 +306,132 @@ func (n *PerlinNoise) At(x, y int) color.Color {
 	val := total / maxAmplitude
 
 	// Map [-1, 1] to [0, 1]
-	normalized := (val + 1.0) * 0.5
-	if normalized < 0 {
-		normalized = 0
+	return (val + 1.0) * 0.5
+}
+
+// noise returns the gradient noise at (x, y, z, w) in lattice units. The lattice
+// wraps at cellsX and cellsY cells when those are positive. Corners whose weight
+// is zero are skipped, so the 2D noise costs no more than it did before z and w.
+func (n *PerlinNoise) noise(x, y, z, w float64, cellsX, cellsY int) float64 {
+	fx, fy, fz, fw := math.Floor(x), math.Floor(y), math.Floor(z), math.Floor(w)
+	X, Y, Z, W := int(fx), int(fy), int(fz), int(fw)
+	x, y, z, w = x-fx, y-fy, z-fz, w-fw
+
+	u := fade(x)
+	v := fade(y)
+
+	X0, X1 := wrapCell(X, cellsX), wrapCell(X+1, cellsX)
+	Y0, Y1 := wrapCell(Y, cellsY), wrapCell(Y+1, cellsY)
+	square := func(dz, dw int) float64 {
+		z, w := z-float64(dz), w-float64(dw)
+		return lerp(v, lerp(u, grad4(n.hash(X0, Y0, Z+dz, W+dw), x, y, z, w), grad4(n.hash(X1, Y0, Z+dz, W+dw), x-1, y, z, w)),
+			lerp(u, grad4(n.hash(X0, Y1, Z+dz, W+dw), x, y-1, z, w), grad4(n.hash(X1, Y1, Z+dz, W+dw), x-1, y-1, z, w)))
+	}
+	cube := func(dw int) float64 {
+		if z == 0 {
+			return square(0, dw)
+		}
+		return lerp(fade(z), square(0, dw), square(1, dw))
 	}
-	if normalized > 1 {
-		normalized = 1
+	if w == 0 {
+		return cube(0)
 	}
+	return lerp(fade(w), cube(0), cube(1))
+}
 
-	c := uint8(normalized * 255)
-	return color.Gray{Y: c}
+// hash returns the permutation hash of a lattice point. The planes z = 0 and w = 0
+// hash as the 2D noise does, which keeps that slice of the noise unchanged.
+func (n *PerlinNoise) hash(X, Y, Z, W int) int {
+	h := n.p[n.p[X&255]+(Y&255)]
+	if z := Z & 255; z != 0 {
+		h = n.p[h+n.p[z]]
+	}
+	if w := W & 255; w != 0 {
+		h = n.p[h+n.p[n.p[w]]]
+	}
+	return h
 }
 
-func (n *PerlinNoise) noise(x, y float64) float64 {
-	X := int(math.Floor(x)) & 255
-	Y := int(math.Floor(y)) & 255
+// grad4 extends grad with z and w components chosen by the next bits of the hash.
+func grad4(hash int, x, y, z, w float64) float64 {
+	g := grad(hash, x, y)
+	if hash&8 == 0 {
+		g += z
+	} else {
+		g -= z
+	}
+	if hash&16 == 0 {
+		g += w
+	} else {
+		g -= w
+	}
+	return g
+}
 
-	x -= math.Floor(x)
-	y -= math.Floor(y)
+// noiseDefaults replaces the zero fBm parameters of a noise algorithm with their
+// defaults.
+func noiseDefaults(frequency float64, octaves int, lacunarity, persistence float64) (float64, int, float64, float64) {
+	if frequency == 0 {
+		frequency = 0.02
+	}
+	if octaves == 0 {
+		octaves = 1
+	}
+	if lacunarity == 0 {
+		lacunarity = 2.0
+	}
+	if persistence == 0 {
+		persistence = 0.5
+	}
+	return frequency, octaves, lacunarity, persistence
+}
 
-	u := fade(x)
-	v := fade(y)
+// fbm sums octaves of noise, which is given the frequency of each octave and
+// returns values in [-1, 1], and maps the sum to [0, 1].
+func fbm(octaves int, frequency, lacunarity, persistence float64, noise func(frequency float64) float64) float64 {
+	var total, maxAmplitude float64
+	amplitude := 1.0
+	for i := 0; i < octaves; i++ {
+		total += float64(noise(frequency) * amplitude)
+		maxAmplitude += amplitude
+		amplitude *= persistence
+		frequency *= lacunarity
+	}
+	return (total/maxAmplitude + 1) * 0.5
+}
 
-	A := n.p[X] + Y
-	B := n.p[X+1] + Y
+// periodCells returns the number of whole cells of size 1/freq that fit most
+// closely in period pixels, and the frequency that makes them fit exactly. With no
+// period, freq is returned as it is.
+func periodCells(period int, freq float64) (int, float64) {
+	if period <= 0 {
+		return 0, freq
+	}
+	cells := int(math.Round(float64(period) * freq))
+	if cells < 1 {
+		cells = 1
+	}
+	return cells, float64(cells) / float64(period)
+}
 
-	return lerp(v, lerp(u, grad(n.p[A], x, y), grad(n.p[B], x-1, y)),
-		lerp(u, grad(n.p[A+1], x, y-1), grad(n.p[B+1], x-1, y-1)))
+// wrapCell wraps a cell coordinate into [0, cells), or returns it as it is when
+// cells is zero.
+func wrapCell(c, cells int) int {
+	if cells <= 0 {
+		return c
+	}
+	c %= cells
+	if c < 0 {
+		c += cells
+	}
+	return c
 }
 
+// fade is the quintic 6t⁵ - 15t⁴ + 10t³. The products are converted before they
+// are added so they are not fused into multiply-adds, keeping it the same on
+// every platform.
 func fade(t float64) float64 {
-	return t * t * t * (t*(t*6-15) + 10)
+	return t * t * t * (float64(t*(float64(t*6)-15)) + 10)
 }
 
 func lerp(t, a, b float64) float64 {
@@ -264,3 +461,171 @@ func grad(hash int, x, y float64) float64 {
 		return 0
 	}
 }
+
+// noiseAlgorithmNames are the names of the serialisable algorithms in the pattern
+// registry, and noiseFractalNames the ways they can be combined by FractalNoise.
+var (
+	noiseAlgorithmNames = []string{"crypto", "hash", "perlin", "opensimplex", "value"}
+	noiseFractalNames   = append([]string{"none"}, fractalTypeNames...)
+)
+
+func init() {
+	RegisterPattern(&PatternType{
+		Name:     "noise",
+		Category: CategoryGenerator,
+		Params: []Param{
+			{Name: "algorithm", Type: ParamEnum, Default: "crypto", Values: noiseAlgorithmNames, Doc: "Noise algorithm."},
+			{Name: "seed", Type: ParamInt, Default: 0, Doc: "Seed of every algorithm but crypto."},
+			{Name: "octaves", Type: ParamInt, Default: 0, Min: 0, Max: 16, Doc: "Octaves of the perlin, opensimplex and value algorithms, or of the fractal; 0 uses the default."},
+			{Name: "persistence", Type: ParamFloat, Default: 0.0, Doc: "Persistence of the perlin, opensimplex and value algorithms, or of the fractal; 0 uses the default."},
+			{Name: "lacunarity", Type: ParamFloat, Default: 0.0, Doc: "Lacunarity of the perlin, opensimplex and value algorithms, or of the fractal; 0 uses the default."},
+			{Name: "frequency", Type: ParamFloat, Default: 0.0, Doc: "Frequency of the perlin, opensimplex and value algorithms; 0 uses the default."},
+			{Name: "period_x", Type: ParamInt, Default: 0, Min: 0, Doc: "Width in pixels the perlin noise tiles at; 0 does not tile."},
+			{Name: "period_y", Type: ParamInt, Default: 0, Min: 0, Doc: "Height in pixels the perlin noise tiles at; 0 does not tile."},
+			{Name: "loop", Type: ParamFloat, Default: 0.0, Min: 0, Doc: "Seconds the animated perlin noise repeats after; 0 does not repeat."},
+			{Name: "fractal", Type: ParamEnum, Default: "none", Values: noiseFractalNames, Doc: "Combine single octaves of the algorithm with FractalNoise; none uses its own fBm."},
+			{Name: "offset", Type: ParamFloat, Default: 0.0, Doc: "Offset of ridged and hybrid fractals; 0 uses the default."},
+			{Name: "gain", Type: ParamFloat, Default: 0.0, Doc: "Gain of ridged fractals; 0 uses the default."},
+			{Name: "warp_depth", Type: ParamInt, Default: 0, Min: 0, Max: 8, Doc: "Levels of domain warping with DomainWarpNoise; 0 does not warp."},
+			{Name: "warp_strengths", Type: ParamFloats, Doc: "Domain warp strength of each level, in pixels; empty uses the default."},
+		},
+		Sample: &Noise{},
+		New: func(a *Args) (image.Image, error) {
+			p := NewNoise().(*Noise)
+			fractal := a.Enum("fractal", noiseFractalNames) - 1
+			octaves := a.Int("octaves")
+			if fractal >= 0 {
+				octaves = 1
+			}
+			switch a.String("algorithm") {
+			case "hash":
+				p.algo = &HashNoise{Seed: int64(a.Int("seed"))}
+			case "perlin":
+				p.algo = &PerlinNoise{
+					Seed:        int64(a.Int("seed")),
+					Octaves:     octaves,
+					Persistence: a.Float("persistence"),
+					Lacunarity:  a.Float("lacunarity"),
+					Frequency:   a.Float("frequency"),
+					Period:      Period{PeriodX: a.Int("period_x"), PeriodY: a.Int("period_y")},
+					Loop:        Loop{Loop: a.Float("loop")},
+				}
+			case "opensimplex":
+				p.algo = &OpenSimplexNoise{
+					Seed:        int64(a.Int("seed")),
+					Octaves:     octaves,
+					Persistence: a.Float("persistence"),
+					Lacunarity:  a.Float("lacunarity"),
+					Frequency:   a.Float("frequency"),
+				}
+			case "value":
+				p.algo = &ValueNoise{
+					Seed:        int64(a.Int("seed")),
+					Octaves:     octaves,
+					Persistence: a.Float("persistence"),
+					Lacunarity:  a.Float("lacunarity"),
+					Frequency:   a.Float("frequency"),
+				}
+			}
+			if fractal >= 0 {
+				base, ok := p.algo.(ScalarField)
+				if !ok {
+					return nil, fmt.Errorf("the %s algorithm cannot be the base of a fractal", a.String("algorithm"))
+				}
+				p.algo = &FractalNoise{
+					Base:        base,
+					Type:        FractalType(fractal),
+					Octaves:     a.Int("octaves"),
+					Lacunarity:  a.Float("lacunarity"),
+					Persistence: a.Float("persistence"),
+					Offset:      a.Float("offset"),
+					Gain:        a.Float("gain"),
+				}
+			}
+			if depth := a.Int("warp_depth"); depth > 0 {
+				base, ok := p.algo.(ScalarField)
+				if !ok {
+					return nil, fmt.Errorf("the %s algorithm cannot be domain warped", a.String("algorithm"))
+				}
+				p.algo = &DomainWarpNoise{Base: base, Depth: depth, Strengths: a.Floats("warp_strengths")}
+			}
+			return applyOps(p, a.Options), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			algo := img.(*Noise).algo
+			if w, ok := algo.(*DomainWarpNoise); ok {
+				w.init()
+				base, ok := w.Base.(NoiseAlgorithm)
+				if !ok {
+					return fmt.Errorf("domain warped noise over %T cannot be serialised", w.Base)
+				}
+				a.Set("warp_depth", w.Depth)
+				a.Set("warp_strengths", w.Strengths)
+				algo = base
+			}
+			if f, ok := algo.(*FractalNoise); ok {
+				f.init()
+				base, ok := f.Base.(NoiseAlgorithm)
+				if !ok || f.Frequency != 1 {
+					return fmt.Errorf("fractal noise over %T, with a frequency of %v, cannot be serialised", f.Base, f.Frequency)
+				}
+				if err := encodeNoiseAlgorithm(base, a); err != nil {
+					return err
+				}
+				if o, ok := a.Values["octaves"]; ok && o != 1 {
+					return fmt.Errorf("fractal noise over a base of %v octaves cannot be serialised", o)
+				}
+				a.Set("fractal", enumName(fractalTypeNames, int(f.Type)))
+				a.Set("octaves", f.Octaves)
+				a.Set("persistence", f.Persistence)
+				a.Set("lacunarity", f.Lacunarity)
+				a.Set("offset", f.Offset)
+				a.Set("gain", f.Gain)
+				return nil
+			}
+			return encodeNoiseAlgorithm(algo, a)
+		},
+	})
+}
+
+// encodeNoiseAlgorithm sets the noise parameters describing algo.
+func encodeNoiseAlgorithm(algo NoiseAlgorithm, a *Args) error {
+	switch algo := algo.(type) {
+	case *CryptoNoise:
+		a.Set("algorithm", "crypto")
+	case *HashNoise:
+		a.Set("algorithm", "hash")
+		a.Set("seed", int(algo.Seed))
+	case *PerlinNoise:
+		// Resolve the defaults so the output does not change once the noise is rendered.
+		algo.init()
+		a.Set("algorithm", "perlin")
+		a.Set("seed", int(algo.Seed))
+		a.Set("octaves", algo.Octaves)
+		a.Set("persistence", algo.Persistence)
+		a.Set("lacunarity", algo.Lacunarity)
+		a.Set("frequency", algo.Frequency)
+		a.Set("period_x", algo.PeriodX)
+		a.Set("period_y", algo.PeriodY)
+		a.Set("loop", algo.Loop.Loop)
+	case *OpenSimplexNoise:
+		algo.init()
+		a.Set("algorithm", "opensimplex")
+		a.Set("seed", int(algo.Seed))
+		a.Set("octaves", algo.Octaves)
+		a.Set("persistence", algo.Persistence)
+		a.Set("lacunarity", algo.Lacunarity)
+		a.Set("frequency", algo.Frequency)
+	case *ValueNoise:
+		algo.init()
+		a.Set("algorithm", "value")
+		a.Set("seed", int(algo.Seed))
+		a.Set("octaves", algo.Octaves)
+		a.Set("persistence", algo.Persistence)
+		a.Set("lacunarity", algo.Lacunarity)
+		a.Set("frequency", algo.Frequency)
+	default:
+		return fmt.Errorf("noise algorithm %T cannot be serialised", algo)
+	}
+	return nil
+}
@@ -12,7 +12,7 @@ var NoiseZoomLevels = []int{} // Zooming is unnecessary for noise
 const NoiseOrder = 20
 
 // Noise Pattern
-// Generates random noise using various algorithms (Crypto, Hash).
+// Generates random noise using various algorithms (Crypto, Hash, Perlin, OpenSimplex, Value).
 func ExampleNewNoise() {
 	// Create a noise pattern with a seeded algorithm (Hash) for stability
 	i := NewNoise(NoiseSeed(1))
@@ -57,7 +57,19 @@ func GenerateNoiseReferences() (map[string]func(image.Rectangle) image.Image, []
 				Frequency: 0.1,
 			}))
 		},
-	}, []string{"Hash", "Hash2", "Perlin", "Perlin_Octaves", "Perlin_HighFreq"}
+		"OpenSimplex": func(b image.Rectangle) image.Image {
+			return NewNoise(SetBounds(b), SetNoiseAlgorithm(&OpenSimplexNoise{
+				Seed:    1,
+				Octaves: 5,
+			}))
+		},
+		"Value": func(b image.Rectangle) image.Image {
+			return NewNoise(SetBounds(b), SetNoiseAlgorithm(&ValueNoise{
+				Seed:    1,
+				Octaves: 5,
+			}))
+		},
+	}, []string{"Hash", "Hash2", "Perlin", "Perlin_Octaves", "Perlin_HighFreq", "OpenSimplex", "Value"}
 }
 
 func init() {
@@ -1,7 +1,12 @@
 package pattern
 
 import (
+	"crypto/md5"
+	"fmt"
+	"image"
 	"image/color"
+	"image/draw"
+	"math"
 	"testing"
 )
 
@@ -91,3 +96,131 @@ func TestCryptoNoise_At(t *testing.T) {
 		t.Errorf("CryptoNoise seems to produce constant output: %v", seen)
 	}
 }
+
+func TestPerlinNoiseSlices(t *testing.T) {
+	p := &PerlinNoise{Seed: 7, Octaves: 3}
+	for y := 0.0; y < 40; y += 3.7 {
+		for x := 0.0; x < 40; x += 2.3 {
+			v := p.ValueAt(x, y)
+			if got := p.ValueAt3(x, y, 0); got != v {
+				t.Fatalf("ValueAt3(%v, %v, 0) = %v, want %v", x, y, got, v)
+			}
+			if got := p.ValueAt4(x, y, 0, 0); got != v {
+				t.Fatalf("ValueAt4(%v, %v, 0, 0) = %v, want %v", x, y, got, v)
+			}
+		}
+	}
+	if p.ValueAt3(10, 10, 0) == p.ValueAt3(10, 10, 25) {
+		t.Error("3D noise does not change along z")
+	}
+	if p.ValueAt4(10, 10, 0, 0) == p.ValueAt4(10, 10, 0, 25) {
+		t.Error("4D noise does not change along w")
+	}
+}
+
+func TestNoisePeriod(t *testing.T) {
+	const px, py = 100, 60
+	images := map[string]interface {
+		ValueAt(x, y float64) float64
+	}{
+		"perlin": &PerlinNoise{Seed: 3, Octaves: 4, Frequency: 0.03, Period: Period{PeriodX: px, PeriodY: py}},
+		"worley": NewWorleyNoise(SetPeriod(px, py), SetFrequency(0.07)).(*WorleyNoise),
+	}
+	for name, img := range images {
+		for y := 0.0; y < py; y += 7 {
+			for x := 0.0; x < px; x += 3 {
+				v := img.ValueAt(x, y)
+				for _, o := range [][2]float64{{px, 0}, {0, py}, {-px, -py}} {
+					if got := img.ValueAt(x+o[0], y+o[1]); math.Abs(got-v) > 1e-9 {
+						t.Fatalf("%s: value at (%v, %v) = %v, want %v as at (%v, %v)", name, x+o[0], y+o[1], got, v, x, y)
+					}
+				}
+			}
+		}
+	}
+
+	s := NewScatter(SetPeriod(px, py), SetScatterFrequency(0.1), SetDensity(0.8)).(*Scatter)
+	s.Generator = func(u, v float64, hash uint64) (color.Color, float64) {
+		if u*u+v*v > 9 {
+			return color.Transparent, 0
+		}
+		return color.RGBA{uint8(hash), uint8(hash >> 8), 255, 255}, float64(hash & 0xff)
+	}
+	for y := 0; y < py; y++ {
+		for x := 0; x < px; x++ {
+			r1, g1, b1, _ := s.At(x, y).RGBA()
+			r2, g2, b2, _ := s.At(x+px, y-py).RGBA()
+			if r1 != r2 || g1 != g2 || b1 != b2 {
+				t.Fatalf("scatter: (%d, %d) and (%d, %d) differ", x, y, x+px, y-py)
+			}
+		}
+	}
+}
+
+func TestNoiseTime(t *testing.T) {
+	still := NewNoise(SetNoiseAlgorithm(&PerlinNoise{Seed: 5, Octaves: 2})).(*Noise)
+	moving := NewNoise(SetNoiseAlgorithm(&PerlinNoise{Seed: 5, Octaves: 2}), SetLoop(2), SetTime(0)).(*Noise)
+	if !IsAnimated(moving) {
+		t.Fatal("noise is not animated")
+	}
+	value := func(n *Noise, tm float64) float64 {
+		n.SetTime(tm)
+		return n.ValueAt(17, 23)
+	}
+	if a, b := value(still, 0), value(moving, 0); a != b {
+		t.Errorf("looping noise at time 0 = %v, want the still noise %v", b, a)
+	}
+	if a, b := value(moving, 0.7), value(moving, 2.7); math.Abs(a-b) > 1e-9 {
+		t.Errorf("looping noise at 0.7s = %v but %v a loop later", a, b)
+	}
+	if a, b := value(moving, 0), value(moving, 0.5); a == b {
+		t.Error("looping noise does not change over time")
+	}
+	if a, b := value(still, 0), value(still, 1); a == b {
+		t.Error("noise does not change over time")
+	}
+}
+
+// TestNoiseGolden checks that the seeded algorithms draw exactly what they always
+// have, so recipes built on them do not change from one release, or platform, to
+// the next.
+func TestNoiseGolden(t *testing.T) {
+	tests := []struct {
+		name string
+		algo NoiseAlgorithm
+		want string
+	}{
+		{"hash", &HashNoise{Seed: 1}, "6775a2ede3eb85abfdbdb12d213c0aaa"},
+		{"perlin", &PerlinNoise{Seed: 1, Octaves: 4}, "c4f371a63277abb3eab29ee9c79a127b"},
+		{"opensimplex", &OpenSimplexNoise{Seed: 1, Octaves: 4}, "f5cc2bd41f8a6bcd5883ca36e3415db5"},
+		{"opensimplex_high_frequency", &OpenSimplexNoise{Seed: 2, Frequency: 0.1}, "14b5ed8c86a4dc159387c8d540504566"},
+		{"value", &ValueNoise{Seed: 1, Octaves: 4}, "94daadc270ec0c915c3623eabcb6cdaf"},
+		{"value_high_frequency", &ValueNoise{Seed: 2, Frequency: 0.1}, "d64f4ec6a1e677d60b394b5f16e066ee"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := image.NewGray(image.Rect(0, 0, 128, 128))
+			draw.Draw(img, img.Bounds(), NewNoise(SetNoiseAlgorithm(tt.algo), SetBounds(img.Bounds())), image.Point{}, draw.Src)
+			if got := fmt.Sprintf("%x", md5.Sum(img.Pix)); got != tt.want {
+				t.Errorf("md5 = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenSimplexNoise(t *testing.T) {
+	n := &OpenSimplexNoise{Seed: 4, Octaves: 3}
+	lo, hi := 1.0, 0.0
+	for y := 0.0; y < 200; y += 1.3 {
+		for x := 0.0; x < 200; x += 1.7 {
+			v := n.ValueAt(x, y)
+			lo, hi = math.Min(lo, v), math.Max(hi, v)
+			if v3 := n.ValueAt3(x, y, 5); v3 < -0.01 || v3 > 1.01 {
+				t.Fatalf("ValueAt3(%v, %v, 5) = %v, outside [0, 1]", x, y, v3)
+			}
+		}
+	}
+	if lo < -0.01 || hi > 1.01 || hi-lo < 0.5 {
+		t.Errorf("values span [%v, %v], want most of [0, 1]", lo, hi)
+	}
+}
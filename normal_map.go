@@ -30,7 +30,12 @@ func (nm *NormalMap) At(x, y int) color.Color {
 	//      1  2  1
 
 	// Helper to get height (0.0-1.0)
+	field, isField := nm.Source.(ScalarField)
 	getHeight := func(x, y int) float64 {
+		if isField {
+			// Use the unquantised height to avoid banding in the normals.
+			return field.ValueAt(float64(x), float64(y))
+		}
 		c := nm.Source.At(x, y)
 		r, g, b, _ := c.RGBA()
 		// Convert to grayscale/luminance
@@ -110,3 +115,26 @@ func NormalMapStrength(strength float64) func(any) {
 		}
 	}
 }
+
+func init() {
+	RegisterPattern(&PatternType{
+		Name:     "normal_map",
+		Category: CategoryFilter,
+		Inputs:   []Input{{Name: "source"}},
+		Params: []Param{
+			{Name: "strength", Type: ParamFloat, Default: 1.0, Doc: "Height scale of the source."},
+		},
+		Sample: &NormalMap{},
+		New: func(a *Args) (image.Image, error) {
+			p := NewNormalMap(a.Input("source")).(*NormalMap)
+			p.Strength = a.Float("strength")
+			return applyOps(p, a.Options), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			p := img.(*NormalMap)
+			a.SetInput("source", p.Source)
+			a.Set("strength", p.Strength)
+			return nil
+		},
+	})
+}
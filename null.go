@@ -43,3 +43,8 @@ func NewNull(ops ...func(any)) image.Image {
 func NewDemoNull(ops ...func(any)) image.Image {
 	return NewNull(ops...)
 }
+
+func init() {
+	RegisterPattern(generatorType("null", &Null{}, NewNull))
+	RegisterPattern(generatorType("demo_null", nil, NewDemoNull))
+}
@@ -0,0 +1,189 @@
+package pattern
+
+import (
+	"image/color"
+	"math"
+	"sync"
+)
+
+// Ensure OpenSimplexNoise implements the NoiseAlgorithm and ScalarField interfaces.
+var _ NoiseAlgorithm = (*OpenSimplexNoise)(nil)
+var _ ScalarField = (*OpenSimplexNoise)(nil)
+
+// OpenSimplexNoise implements OpenSimplex2 noise with Fractional Brownian Motion
+// (fBm). In 2D it sums kernels on a triangular lattice and in 3D on a body
+// centred cubic lattice, so it lacks the axis aligned artifacts of PerlinNoise.
+//
+// The lattice is hashed with StableHash and the gradients are built from
+// constants, so the noise is the same on every platform.
+type OpenSimplexNoise struct {
+	Seed        int64
+	Octaves     int
+	Persistence float64
+	Lacunarity  float64
+	Frequency   float64
+
+	once sync.Once
+}
+
+func (n *OpenSimplexNoise) init() {
+	n.once.Do(func() {
+		n.Frequency, n.Octaves, n.Lacunarity, n.Persistence = noiseDefaults(n.Frequency, n.Octaves, n.Lacunarity, n.Persistence)
+	})
+}
+
+func (n *OpenSimplexNoise) At(x, y int) color.Color {
+	return color.Gray{Y: uint8(clamp01(n.ValueAt(float64(x), float64(y))) * 255)}
+}
+
+// ValueAt returns the 2D fBm sum at (x, y) mapped to roughly [0, 1], without
+// clamping or quantisation.
+func (n *OpenSimplexNoise) ValueAt(x, y float64) float64 {
+	n.init()
+	seed := uint64(n.Seed)
+	return fbm(n.Octaves, n.Frequency, n.Lacunarity, n.Persistence, func(f float64) float64 {
+		return openSimplex2(seed, float64(x*f), float64(y*f))
+	})
+}
+
+// ValueAt3 returns the 3D fBm sum at (x, y, z) mapped to roughly [0, 1]. All
+// three coordinates are in pixels.
+func (n *OpenSimplexNoise) ValueAt3(x, y, z float64) float64 {
+	n.init()
+	seed := uint64(n.Seed)
+	return fbm(n.Octaves, n.Frequency, n.Lacunarity, n.Persistence, func(f float64) float64 {
+		return openSimplex3(seed, float64(x*f), float64(y*f), float64(z*f))
+	})
+}
+
+// The products in these kernels are converted to float64 before they are added,
+// which stops the compiler fusing them into multiply-adds on the platforms that
+// have them, so the results round the same everywhere.
+
+const (
+	simplexSkew2   = 0.366025403784439    // (√3 - 1) / 2
+	simplexUnskew2 = -0.21132486540518713 // (1/√3 - 1) / 2
+	// simplexScale2 and simplexScale3 bring the sums of the kernels to [-1, 1].
+	simplexScale2 = 99.83685446303647
+	simplexScale3 = 41.45
+	// simplexFlip3 changes the seed for the second cubic lattice of the 3D noise.
+	simplexFlip3 = 0x52d547b2e96ed629
+)
+
+// openSimplex2 returns 2D OpenSimplex2 noise at (x, y) in [-1, 1].
+func openSimplex2(seed uint64, x, y float64) float64 {
+	s := float64(simplexSkew2 * (x + y))
+	xs, ys := x+s, y+s
+	xb, yb := math.Floor(xs), math.Floor(ys)
+	xi, yi := xs-xb, ys-yb
+	t := float64((xi + yi) * simplexUnskew2)
+	dx0, dy0 := xi+t, yi+t
+
+	// The triangle holding the point has the corners (0, 0) and (1, 1) of the
+	// skewed cell, and one of the others; the fourth corner is out of reach of the
+	// kernel, so all four can be tried.
+	var value float64
+	for j := 0; j <= 1; j++ {
+		for i := 0; i <= 1; i++ {
+			u := float64(float64(i+j) * simplexUnskew2)
+			dx, dy := dx0-float64(i)-u, dy0-float64(j)-u
+			a := 0.5 - float64(dx*dx) - float64(dy*dy)
+			if a <= 0 {
+				continue
+			}
+			h := StableHash(int(xb)+i, int(yb)+j, seed)
+			g := simplexGradients2[h%uint64(len(simplexGradients2))]
+			a *= a
+			value += float64(a * a * (float64(g[0]*dx) + float64(g[1]*dy)))
+		}
+	}
+	return value * simplexScale2
+}
+
+// openSimplex3 returns 3D OpenSimplex2 noise at (x, y, z) in [-1, 1]. The
+// coordinates are reflected through the plane x + y + z = 0 so that the
+// lattice's main diagonal, where its artifacts are, points along z.
+func openSimplex3(seed uint64, x, y, z float64) float64 {
+	r := float64((2.0 / 3.0) * (x + y + z))
+	x, y, z = r-x, r-y, r-z
+
+	// The body centred cubic lattice is two cubic lattices, the second offset by
+	// half a cell. Only the corners of the cell holding the point, in each, are
+	// close enough to add to it.
+	var value float64
+	for lattice := 0; lattice <= 1; lattice++ {
+		offset := float64(lattice) * 0.5
+		xb, yb, zb := math.Floor(x-offset), math.Floor(y-offset), math.Floor(z-offset)
+		latticeSeed := seed ^ uint64(lattice)*simplexFlip3
+		for k := 0; k <= 1; k++ {
+			for j := 0; j <= 1; j++ {
+				for i := 0; i <= 1; i++ {
+					dx := x - (xb + float64(i) + offset)
+					dy := y - (yb + float64(j) + offset)
+					dz := z - (zb + float64(k) + offset)
+					a := 0.6 - float64(dx*dx) - float64(dy*dy) - float64(dz*dz)
+					if a <= 0 {
+						continue
+					}
+					h := StableHash(int(xb)+i, int(yb)+j, StableHash(int(zb)+k, 0, latticeSeed))
+					g := simplexGradients3[h%uint64(len(simplexGradients3))]
+					a *= a
+					value += float64(a * a * (float64(g[0]*dx) + float64(g[1]*dy) + float64(g[2]*dz)))
+				}
+			}
+		}
+	}
+	return value * simplexScale3
+}
+
+// simplexGradients2 holds 24 unit vectors 15° apart, starting 7.5° from the x
+// axis so that none lie along an axis.
+var simplexGradients2 = func() [][2]float64 {
+	// The sines of 7.5°, 22.5°, ..., 82.5°.
+	sines := []float64{
+		0.13052619222005157, 0.38268343236508978, 0.60876142900872066,
+		0.79335334029123517, 0.92387953251128674, 0.99144486137381038,
+	}
+	var g [][2]float64
+	for i := range sines {
+		x, y := sines[len(sines)-1-i], sines[i]
+		// Each quarter turn maps (x, y) to (-y, x).
+		for q := 0; q < 4; q++ {
+			g = append(g, [2]float64{x, y})
+			x, y = -y, x
+		}
+	}
+	return g
+}()
+
+// simplexGradients3 holds the 48 vectors of OpenSimplex2: the permutations of
+// (±a, ±a, ±1) and of (±b, ±c, 0), all of the same length, scaled to unit length.
+var simplexGradients3 = func() [][3]float64 {
+	const a, b, c = 2.22474487139, 3.0862664687972017, 1.1721513422464978
+	length := math.Sqrt(2*a*a + 1)
+	var g [][3]float64
+	for axis := 0; axis < 3; axis++ {
+		for signs := 0; signs < 8; signs++ {
+			sign := func(bit int) float64 {
+				if signs&(1<<bit) != 0 {
+					return -1
+				}
+				return 1
+			}
+			var v, w [3]float64
+			for i := 0; i < 3; i++ {
+				v[i] = a * sign(i)
+			}
+			v[axis] = sign(axis)
+			// The zero of the second family is on axis, with b and c after it in
+			// turn, or swapped.
+			w[(axis+1)%3], w[(axis+2)%3] = b*sign(0), c*sign(1)
+			if signs&4 != 0 {
+				w[(axis+1)%3], w[(axis+2)%3] = c*sign(0), b*sign(1)
+			}
+			g = append(g, [3]float64{v[0] / length, v[1] / length, v[2] / length})
+			g = append(g, [3]float64{w[0] / length, w[1] / length, w[2] / length})
+		}
+	}
+	return g
+}()
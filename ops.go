@@ -1,6 +1,9 @@
 package pattern
 
-import "image"
+import (
+	"image"
+	"reflect"
+)
 
 type hasBounds interface {
 	SetBounds(image.Rectangle)
@@ -13,3 +16,84 @@ func SetBounds(bounds image.Rectangle) func(ai any) {
 		}
 	}
 }
+
+// SetChainBounds sets the bounds of img and of every image it is built from, so a
+// whole pipeline renders at one size. The inputs of registered types are found
+// through their Encode function, and those of every type through their exported
+// image.Image and []image.Image fields, embedded ones included. Images without a
+// SetBounds method keep their bounds but are still searched.
+func SetChainBounds(img image.Image, bounds image.Rectangle) {
+	walkChain(img, func(img image.Image) {
+		if b, ok := img.(hasBounds); ok {
+			b.SetBounds(bounds)
+		}
+	})
+}
+
+// walkChain calls fn on img and on every image it is built from, once each.
+func walkChain(img image.Image, fn func(image.Image)) {
+	seen := map[image.Image]bool{}
+	var walk func(img image.Image)
+	walk = func(img image.Image) {
+		if img == nil {
+			return
+		}
+		if reflect.TypeOf(img).Comparable() {
+			if seen[img] {
+				return
+			}
+			seen[img] = true
+		}
+		fn(img)
+		for _, in := range imageInputs(img) {
+			walk(in)
+		}
+	}
+	walk(img)
+}
+
+// imageInputs returns the images img is built from: the inputs Encode reports for
+// registered types, then any images in exported fields, such as those options
+// like SetFillImageSource and SetMortarImage hold.
+func imageInputs(img image.Image) []image.Image {
+	var inputs []image.Image
+	if t, ok := PatternTypeOf(img); ok && t.Encode != nil {
+		var a Args
+		if err := t.Encode(img, &a); err == nil {
+			for _, slot := range t.Inputs {
+				inputs = append(inputs, a.Inputs[slot.Name]...)
+			}
+		}
+	}
+	v := reflect.ValueOf(img)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	return fieldImages(v, inputs)
+}
+
+// fieldImages appends the images in the exported image.Image and []image.Image
+// fields of v, and of the structs it embeds, to inputs.
+func fieldImages(v reflect.Value, inputs []image.Image) []image.Image {
+	if v.Kind() != reflect.Struct {
+		return inputs
+	}
+	imageType := reflect.TypeOf((*image.Image)(nil)).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		f, sf := v.Field(i), v.Type().Field(i)
+		switch {
+		case sf.Anonymous && f.Kind() == reflect.Struct:
+			inputs = fieldImages(f, inputs)
+		case !sf.IsExported():
+		case f.Type() == imageType && !f.IsNil():
+			inputs = append(inputs, f.Interface().(image.Image))
+		case f.Kind() == reflect.Slice && f.Type().Elem() == imageType:
+			for j := 0; j < f.Len(); j++ {
+				if e := f.Index(j); !e.IsNil() {
+					inputs = append(inputs, e.Interface().(image.Image))
+				}
+			}
+		}
+	}
+	return inputs
+}
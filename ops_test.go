@@ -0,0 +1,41 @@
+package pattern
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+// chainWrapper is an unregistered pattern whose inputs are exported fields.
+type chainWrapper struct {
+	image.Image
+	Layers []image.Image
+}
+
+func TestSetChainBounds(t *testing.T) {
+	checker := NewChecker(color.Black, color.White)
+	gradient := NewLinearGradient()
+	blend := NewBlend(checker, NewRotate(gradient, 90), BlendMultiply)
+	null := NewNull()
+	want := image.Rect(0, 0, 40, 30)
+	SetChainBounds(&chainWrapper{Image: blend, Layers: []image.Image{null, blend}}, want)
+	for name, img := range map[string]image.Image{"checker": checker, "gradient": gradient, "blend": blend, "null": null} {
+		if got := img.Bounds(); got != want {
+			t.Errorf("Expected %s bounds %v, got %v", name, want, got)
+		}
+	}
+}
+
+func TestSetChainBoundsOptionImages(t *testing.T) {
+	fill := NewChecker(color.Black, color.White)
+	mortar := NewLinearGradient()
+	circle := NewCircle(SetFillImageSource(fill))
+	brick := NewBrick(SetMortarImage(mortar))
+	want := image.Rect(0, 0, 40, 30)
+	SetChainBounds(NewBlend(circle, brick, BlendMultiply), want)
+	for name, img := range map[string]image.Image{"fill": fill, "mortar": mortar} {
+		if got := img.Bounds(); got != want {
+			t.Errorf("Expected %s bounds %v, got %v", name, want, got)
+		}
+	}
+}
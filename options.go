@@ -229,6 +229,75 @@ func SetPhase(v float64) func(any) {
 	}
 }
 
+// Time configures the moment, in seconds, shown by an animated pattern.
+type Time struct {
+	Time float64
+}
+
+func (s *Time) SetTime(v float64) {
+	s.Time = v
+}
+
+type hasTime interface {
+	SetTime(float64)
+}
+
+// SetTime creates an option to set the time.
+func SetTime(v float64) func(any) {
+	return func(i any) {
+		if h, ok := i.(hasTime); ok {
+			h.SetTime(v)
+		}
+	}
+}
+
+// Loop configures the length, in seconds, after which an animated pattern repeats
+// exactly. Zero does not repeat.
+type Loop struct {
+	Loop float64
+}
+
+func (s *Loop) SetLoop(v float64) {
+	s.Loop = v
+}
+
+type hasLoop interface {
+	SetLoop(float64)
+}
+
+// SetLoop creates an option to set the loop length.
+func SetLoop(v float64) func(any) {
+	return func(i any) {
+		if h, ok := i.(hasLoop); ok {
+			h.SetLoop(v)
+		}
+	}
+}
+
+// Period configures the width and height, in pixels, at which a pattern tiles
+// exactly. Zero does not tile in that direction.
+type Period struct {
+	PeriodX, PeriodY int
+}
+
+func (p *Period) SetPeriod(x, y int) {
+	p.PeriodX = x
+	p.PeriodY = y
+}
+
+type hasPeriod interface {
+	SetPeriod(int, int)
+}
+
+// SetPeriod creates an option to set the period.
+func SetPeriod(x, y int) func(any) {
+	return func(i any) {
+		if h, ok := i.(hasPeriod); ok {
+			h.SetPeriod(x, y)
+		}
+	}
+}
+
 // Radius configures the radius of circles/dots in a pattern.
 type Radius struct {
 	Radius int
@@ -689,3 +758,69 @@ func SetPalette(v ...color.Color) func(any) {
 		}
 	}
 }
+
+// TileSize configures the edge length of the square tiles used when rendering.
+type TileSize struct {
+	TileSize int
+}
+
+func (s *TileSize) SetTileSize(v int) {
+	s.TileSize = v
+}
+
+type hasTileSize interface {
+	SetTileSize(int)
+}
+
+// SetTileSize creates an option to set the tile size.
+func SetTileSize(v int) func(any) {
+	return func(i any) {
+		if h, ok := i.(hasTileSize); ok {
+			h.SetTileSize(v)
+		}
+	}
+}
+
+// Workers configures the number of concurrent workers.
+type Workers struct {
+	Workers int
+}
+
+func (s *Workers) SetWorkers(v int) {
+	s.Workers = v
+}
+
+type hasWorkers interface {
+	SetWorkers(int)
+}
+
+// SetWorkers creates an option to set the number of workers.
+func SetWorkers(v int) func(any) {
+	return func(i any) {
+		if h, ok := i.(hasWorkers); ok {
+			h.SetWorkers(v)
+		}
+	}
+}
+
+// AntiAlias configures analytic edge anti-aliasing for geometric patterns.
+type AntiAlias struct {
+	AntiAlias bool
+}
+
+func (s *AntiAlias) SetAntiAlias(v bool) {
+	s.AntiAlias = v
+}
+
+type hasAntiAlias interface {
+	SetAntiAlias(bool)
+}
+
+// SetAntiAlias creates an option to enable analytic edge anti-aliasing.
+func SetAntiAlias(v bool) func(any) {
+	return func(i any) {
+		if h, ok := i.(hasAntiAlias); ok {
+			h.SetAntiAlias(v)
+		}
+	}
+}
@@ -0,0 +1,455 @@
+package pattern
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// OptionInfo describes one of the functional options a pattern accepts.
+type OptionInfo struct {
+	// Name is the option's name; the option is created by the function Set<Name>.
+	Name string
+	// Args are the types of the arguments taken by the option's setter.
+	Args []reflect.Type
+	// Value is the option's current value, or nil if it cannot be read. Options set
+	// through a single value hold that value; others hold the struct that stores them,
+	// such as Center.
+	Value any
+}
+
+// Describe lists the options that apply to img, sorted by name, with their current values.
+// An option applies when img implements its has* setter interface, so options that only
+// target a specific concrete type, such as WarpScale, are not listed.
+func Describe(img any) []OptionInfo {
+	t := reflect.TypeOf(img)
+	if t == nil {
+		return nil
+	}
+	var infos []OptionInfo
+	for _, iface := range optionInterfaces {
+		if !t.Implements(iface) {
+			continue
+		}
+		m := iface.Method(0)
+		info := OptionInfo{Name: strings.TrimPrefix(m.Name, "Set")}
+		for i := 0; i < m.Type.NumIn(); i++ {
+			info.Args = append(info.Args, m.Type.In(i))
+		}
+		info.Value = optionValue(img, info)
+		infos = append(infos, info)
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// optionValue reads the current value of an option from img. It looks for an embedded
+// option struct or a field named after the option, then for a getter method such as Bounds.
+func optionValue(img any, info OptionInfo) any {
+	v := reflect.ValueOf(img)
+	if v.Kind() == reflect.Ptr && !v.IsNil() && v.Elem().Kind() == reflect.Struct {
+		if sf, ok := v.Elem().Type().FieldByName(info.Name); ok && sf.IsExported() {
+			f := v.Elem().FieldByIndex(sf.Index)
+			if sf.Anonymous && f.Kind() == reflect.Struct {
+				if f.NumField() == 1 && f.Type().Field(0).IsExported() {
+					return f.Field(0).Interface()
+				}
+				return f.Interface()
+			}
+			if len(info.Args) == 1 && f.Type() == info.Args[0] {
+				return f.Interface()
+			}
+		}
+	}
+	if len(info.Args) == 1 {
+		if m := v.MethodByName(info.Name); m.IsValid() && m.Type().NumIn() == 0 && m.Type().NumOut() == 1 && m.Type().Out(0) == info.Args[0] {
+			return m.Call(nil)[0].Interface()
+		}
+	}
+	return nil
+}
+
+// OptionError reports options that did not apply to a pattern under ApplyStrict.
+type OptionError struct {
+	// Target is the pattern the options were applied to.
+	Target any
+	// Index is the position of the option in the list passed to ApplyStrict.
+	Index int
+	// Setter names the setter the option calls, or is empty if it could not be identified.
+	Setter string
+}
+
+func (e *OptionError) Error() string {
+	if e.Setter == "" {
+		return fmt.Sprintf("option %d had no effect on %T", e.Index, e.Target)
+	}
+	return fmt.Sprintf("option %d (%s) does not apply to %T", e.Index, e.Setter, e.Target)
+}
+
+// ApplyStrict applies ops to target and returns an error for each option that did not apply.
+// Options built on a has* setter interface, such as SetRadius, fail when target does not
+// implement it. Options that check for a concrete type, such as WarpScale, fail when they
+// leave target unchanged, which includes setting a value it already has. The state compared
+// follows pointers, so changes made behind a pointer field count; funcs are compared by
+// their code, so replacing a func with another closure of the same function literal does not.
+// Options that do apply are applied even if others fail.
+func ApplyStrict(target any, ops ...func(any)) error {
+	var errs []error
+	for i, op := range ops {
+		if setter := optionSetter(op); setter != "" {
+			if !hasSetter(target, setter) {
+				errs = append(errs, &OptionError{Target: target, Index: i, Setter: setter})
+				continue
+			}
+			op(target)
+			continue
+		}
+		before := optionState(target)
+		op(target)
+		if before == optionState(target) {
+			errs = append(errs, &OptionError{Target: target, Index: i})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Strict wraps ops so that they are applied with ApplyStrict, panicking with the error if
+// any of them does not apply. It is intended for catching mistakes in pattern
+// definitions, for example NewPolka(Strict(SetRadius(4), SetSpaceSize(2))).
+func Strict(ops ...func(any)) func(any) {
+	return func(i any) {
+		if err := ApplyStrict(i, ops...); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// optionSetter returns the name of the setter method op calls, or "" if op does not use
+// one of the setter interfaces. An op that panics on the probe, such as a nested Strict
+// of options that check for a concrete type, is treated as not using one.
+func optionSetter(op func(any)) (setter string) {
+	defer func() {
+		if recover() != nil {
+			setter = ""
+		}
+	}()
+	probe := &optionProbe{}
+	op(probe)
+	return probe.setter
+}
+
+// setterAliases lists setters that options fall back to when the first is missing.
+var setterAliases = map[string]string{
+	"SetSeed":       "SetSeedUint64",
+	"SetSeedUint64": "SetSeed",
+}
+
+func hasSetter(target any, name string) bool {
+	t := reflect.TypeOf(target)
+	if t == nil {
+		return false
+	}
+	if _, ok := t.MethodByName(name); ok {
+		return true
+	}
+	if alias, ok := setterAliases[name]; ok {
+		_, ok := t.MethodByName(alias)
+		return ok
+	}
+	return false
+}
+
+// optionState returns a fingerprint of the state reachable from target, so that
+// ApplyStrict can tell whether an option changed it.
+func optionState(target any) uint64 {
+	h := fnv.New64a()
+	writeOptionState(h, reflect.ValueOf(target), map[optionStateVisit]bool{})
+	return h.Sum64()
+}
+
+// optionStateVisit identifies a pointer already written, so cycles end.
+type optionStateVisit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// writeOptionState writes v, and all it points to, to h. Funcs, channels and unsafe
+// pointers are written as the pointer, which for funcs is their code.
+func writeOptionState(h hash.Hash64, v reflect.Value, seen map[optionStateVisit]bool) {
+	var buf [8]byte
+	writeUint := func(u uint64) {
+		binary.LittleEndian.PutUint64(buf[:], u)
+		h.Write(buf[:])
+	}
+	if !v.IsValid() {
+		writeUint(0)
+		return
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			writeUint(1)
+		} else {
+			writeUint(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeUint(math.Float64bits(v.Float()))
+	case reflect.Complex64, reflect.Complex128:
+		writeUint(math.Float64bits(real(v.Complex())))
+		writeUint(math.Float64bits(imag(v.Complex())))
+	case reflect.String:
+		writeUint(uint64(v.Len()))
+		h.Write([]byte(v.String()))
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		writeUint(uint64(v.Pointer()))
+	case reflect.Interface:
+		if v.IsNil() {
+			writeUint(0)
+			return
+		}
+		h.Write([]byte(v.Elem().Type().String()))
+		writeOptionState(h, v.Elem(), seen)
+	case reflect.Ptr:
+		if v.IsNil() {
+			writeUint(0)
+			return
+		}
+		visit := optionStateVisit{v.Pointer(), v.Type()}
+		writeUint(uint64(visit.ptr))
+		if seen[visit] {
+			return
+		}
+		seen[visit] = true
+		writeOptionState(h, v.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			writeOptionState(h, v.Field(i), seen)
+		}
+	case reflect.Slice, reflect.Array:
+		writeUint(uint64(v.Len()))
+		if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+			// Pixel buffers are written whole.
+			h.Write(v.Bytes())
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			writeOptionState(h, v.Index(i), seen)
+		}
+	case reflect.Map:
+		// Maps have no order, so entries are written in the order of their keys'
+		// fingerprints.
+		type entry struct {
+			key   uint64
+			value reflect.Value
+		}
+		var entries []entry
+		iter := v.MapRange()
+		for iter.Next() {
+			kh := fnv.New64a()
+			writeOptionState(kh, iter.Key(), map[optionStateVisit]bool{})
+			entries = append(entries, entry{kh.Sum64(), iter.Value()})
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+		writeUint(uint64(len(entries)))
+		for _, e := range entries {
+			writeUint(e.key)
+			writeOptionState(h, e.value, seen)
+		}
+	}
+}
+
+// optionInterfaces lists the setter interfaces used by the functional options.
+var optionInterfaces = []reflect.Type{
+	reflect.TypeOf((*hasAngle)(nil)).Elem(),
+	reflect.TypeOf((*hasAngles)(nil)).Elem(),
+	reflect.TypeOf((*hasAntiAlias)(nil)).Elem(),
+	reflect.TypeOf((*hasBackgroundColor)(nil)).Elem(),
+	reflect.TypeOf((*hasBandColor)(nil)).Elem(),
+	reflect.TypeOf((*hasBandThickness)(nil)).Elem(),
+	reflect.TypeOf((*hasBlockSize)(nil)).Elem(),
+	reflect.TypeOf((*hasBooleanMode)(nil)).Elem(),
+	reflect.TypeOf((*hasBounds)(nil)).Elem(),
+	reflect.TypeOf((*hasBrickImages)(nil)).Elem(),
+	reflect.TypeOf((*hasBrickOffset)(nil)).Elem(),
+	reflect.TypeOf((*hasBrickSize)(nil)).Elem(),
+	reflect.TypeOf((*hasCenter)(nil)).Elem(),
+	reflect.TypeOf((*hasColorOffset)(nil)).Elem(),
+	reflect.TypeOf((*hasColorVariance)(nil)).Elem(),
+	reflect.TypeOf((*hasCrossShadowDepth)(nil)).Elem(),
+	reflect.TypeOf((*hasDarkThreadColor)(nil)).Elem(),
+	reflect.TypeOf((*hasDensity)(nil)).Elem(),
+	reflect.TypeOf((*hasEdgeAwareness)(nil)).Elem(),
+	reflect.TypeOf((*hasEndColor)(nil)).Elem(),
+	reflect.TypeOf((*hasExpiry)(nil)).Elem(),
+	reflect.TypeOf((*hasFalloffCurve)(nil)).Elem(),
+	reflect.TypeOf((*hasFalseColor)(nil)).Elem(),
+	reflect.TypeOf((*hasFillColor)(nil)).Elem(),
+	reflect.TypeOf((*hasFillImageSource)(nil)).Elem(),
+	reflect.TypeOf((*hasFineGridCellSize)(nil)).Elem(),
+	reflect.TypeOf((*hasFineGridGlowRadius)(nil)).Elem(),
+	reflect.TypeOf((*hasFineGridHue)(nil)).Elem(),
+	reflect.TypeOf((*hasFloatCenter)(nil)).Elem(),
+	reflect.TypeOf((*hasFrequency)(nil)).Elem(),
+	reflect.TypeOf((*hasFrequencyX)(nil)).Elem(),
+	reflect.TypeOf((*hasFrequencyY)(nil)).Elem(),
+	reflect.TypeOf((*hasGamma)(nil)).Elem(),
+	reflect.TypeOf((*hasGlowColor)(nil)).Elem(),
+	reflect.TypeOf((*hasGlowSize)(nil)).Elem(),
+	reflect.TypeOf((*hasGlyphSize)(nil)).Elem(),
+	reflect.TypeOf((*hasGrainIntensity)(nil)).Elem(),
+	reflect.TypeOf((*hasHueJitter)(nil)).Elem(),
+	reflect.TypeOf((*hasLatitudeLines)(nil)).Elem(),
+	reflect.TypeOf((*hasLightThreadColor)(nil)).Elem(),
+	reflect.TypeOf((*hasLineColor)(nil)).Elem(),
+	reflect.TypeOf((*hasLineImageSource)(nil)).Elem(),
+	reflect.TypeOf((*hasLineSize)(nil)).Elem(),
+	reflect.TypeOf((*hasLineThickness)(nil)).Elem(),
+	reflect.TypeOf((*hasLongitudeLines)(nil)).Elem(),
+	reflect.TypeOf((*hasLoop)(nil)).Elem(),
+	reflect.TypeOf((*hasMaxRadius)(nil)).Elem(),
+	reflect.TypeOf((*hasMinRadius)(nil)).Elem(),
+	reflect.TypeOf((*hasMortarImage)(nil)).Elem(),
+	reflect.TypeOf((*hasMortarSize)(nil)).Elem(),
+	reflect.TypeOf((*hasNoiseAlgorithm)(nil)).Elem(),
+	reflect.TypeOf((*hasNoiseIntensity)(nil)).Elem(),
+	reflect.TypeOf((*hasOffsetStrength)(nil)).Elem(),
+	reflect.TypeOf((*hasPaintColor)(nil)).Elem(),
+	reflect.TypeOf((*hasPaintWear)(nil)).Elem(),
+	reflect.TypeOf((*hasPalette)(nil)).Elem(),
+	reflect.TypeOf((*hasPeriod)(nil)).Elem(),
+	reflect.TypeOf((*hasPhase)(nil)).Elem(),
+	reflect.TypeOf((*hasPlankBaseWidth)(nil)).Elem(),
+	reflect.TypeOf((*hasPlankWidthVariance)(nil)).Elem(),
+	reflect.TypeOf((*hasPredicate)(nil)).Elem(),
+	reflect.TypeOf((*hasRadius)(nil)).Elem(),
+	reflect.TypeOf((*hasRenderDepth)(nil)).Elem(),
+	reflect.TypeOf((*hasRenderProgress)(nil)).Elem(),
+	reflect.TypeOf((*hasRuneColor)(nil)).Elem(),
+	reflect.TypeOf((*hasScaleRadius)(nil)).Elem(),
+	reflect.TypeOf((*hasScaleXSpacing)(nil)).Elem(),
+	reflect.TypeOf((*hasScaleYSpacing)(nil)).Elem(),
+	reflect.TypeOf((*hasScanlineFrequency)(nil)).Elem(),
+	reflect.TypeOf((*hasScanlineIntensity)(nil)).Elem(),
+	reflect.TypeOf((*hasSeed)(nil)).Elem(),
+	reflect.TypeOf((*hasSeedUint64)(nil)).Elem(),
+	reflect.TypeOf((*hasSerpentine)(nil)).Elem(),
+	reflect.TypeOf((*hasSpaceColor)(nil)).Elem(),
+	reflect.TypeOf((*hasSpaceImageSource)(nil)).Elem(),
+	reflect.TypeOf((*hasSpaceSize)(nil)).Elem(),
+	reflect.TypeOf((*hasSpacing)(nil)).Elem(),
+	reflect.TypeOf((*hasStartColor)(nil)).Elem(),
+	reflect.TypeOf((*hasThreadWidth)(nil)).Elem(),
+	reflect.TypeOf((*hasThreshold)(nil)).Elem(),
+	reflect.TypeOf((*hasTileSize)(nil)).Elem(),
+	reflect.TypeOf((*hasTilt)(nil)).Elem(),
+	reflect.TypeOf((*hasTime)(nil)).Elem(),
+	reflect.TypeOf((*hasTrueColor)(nil)).Elem(),
+	reflect.TypeOf((*hasVignetteRadius)(nil)).Elem(),
+	reflect.TypeOf((*hasWorkers)(nil)).Elem(),
+}
+
+// optionProbe implements every setter interface in optionInterfaces and records which
+// one an option calls, so ApplyStrict can tell which interface the option needs.
+type optionProbe struct {
+	setter string
+}
+
+func (p *optionProbe) SetAngle(float64)                     { p.setter = "SetAngle" }
+func (p *optionProbe) SetAngles([]float64)                  { p.setter = "SetAngles" }
+func (p *optionProbe) SetAntiAlias(bool)                    { p.setter = "SetAntiAlias" }
+func (p *optionProbe) SetBackgroundColor(color.Color)       { p.setter = "SetBackgroundColor" }
+func (p *optionProbe) SetBandColor(color.Color)             { p.setter = "SetBandColor" }
+func (p *optionProbe) SetBandThickness(int)                 { p.setter = "SetBandThickness" }
+func (p *optionProbe) SetBlockSize(int)                     { p.setter = "SetBlockSize" }
+func (p *optionProbe) SetBooleanMode(BooleanMode)           { p.setter = "SetBooleanMode" }
+func (p *optionProbe) SetBounds(image.Rectangle)            { p.setter = "SetBounds" }
+func (p *optionProbe) SetBrickImages([]image.Image)         { p.setter = "SetBrickImages" }
+func (p *optionProbe) SetBrickOffset(float64)               { p.setter = "SetBrickOffset" }
+func (p *optionProbe) SetBrickSize(int, int)                { p.setter = "SetBrickSize" }
+func (p *optionProbe) SetCenter(int, int)                   { p.setter = "SetCenter" }
+func (p *optionProbe) SetColorOffset(int)                   { p.setter = "SetColorOffset" }
+func (p *optionProbe) SetColorVariance(float64)             { p.setter = "SetColorVariance" }
+func (p *optionProbe) SetCrossShadowDepth(float64)          { p.setter = "SetCrossShadowDepth" }
+func (p *optionProbe) SetDarkThreadColor(color.Color)       { p.setter = "SetDarkThreadColor" }
+func (p *optionProbe) SetDensity(float64)                   { p.setter = "SetDensity" }
+func (p *optionProbe) SetEdgeAwareness(float64)             { p.setter = "SetEdgeAwareness" }
+func (p *optionProbe) SetEndColor(color.Color)              { p.setter = "SetEndColor" }
+func (p *optionProbe) SetExpiry(time.Duration)              { p.setter = "SetExpiry" }
+func (p *optionProbe) SetFalloffCurve(float64)              { p.setter = "SetFalloffCurve" }
+func (p *optionProbe) SetFalseColor(color.Color)            { p.setter = "SetFalseColor" }
+func (p *optionProbe) SetFillColor(color.Color)             { p.setter = "SetFillColor" }
+func (p *optionProbe) SetFillImageSource(image.Image)       { p.setter = "SetFillImageSource" }
+func (p *optionProbe) SetFineGridCellSize(int)              { p.setter = "SetFineGridCellSize" }
+func (p *optionProbe) SetFineGridGlowRadius(float64)        { p.setter = "SetFineGridGlowRadius" }
+func (p *optionProbe) SetFineGridHue(float64)               { p.setter = "SetFineGridHue" }
+func (p *optionProbe) SetFloatCenter(float64, float64)      { p.setter = "SetFloatCenter" }
+func (p *optionProbe) SetFrequency(float64)                 { p.setter = "SetFrequency" }
+func (p *optionProbe) SetFrequencyX(float64)                { p.setter = "SetFrequencyX" }
+func (p *optionProbe) SetFrequencyY(float64)                { p.setter = "SetFrequencyY" }
+func (p *optionProbe) SetGamma(float64)                     { p.setter = "SetGamma" }
+func (p *optionProbe) SetGlowColor(color.Color)             { p.setter = "SetGlowColor" }
+func (p *optionProbe) SetGlowSize(int)                      { p.setter = "SetGlowSize" }
+func (p *optionProbe) SetGlyphSize(int)                     { p.setter = "SetGlyphSize" }
+func (p *optionProbe) SetGrainIntensity(float64)            { p.setter = "SetGrainIntensity" }
+func (p *optionProbe) SetHueJitter(float64)                 { p.setter = "SetHueJitter" }
+func (p *optionProbe) SetLatitudeLines(int)                 { p.setter = "SetLatitudeLines" }
+func (p *optionProbe) SetLightThreadColor(color.Color)      { p.setter = "SetLightThreadColor" }
+func (p *optionProbe) SetLineColor(color.Color)             { p.setter = "SetLineColor" }
+func (p *optionProbe) SetLineImageSource(image.Image)       { p.setter = "SetLineImageSource" }
+func (p *optionProbe) SetLineSize(int)                      { p.setter = "SetLineSize" }
+func (p *optionProbe) SetLineThickness(int)                 { p.setter = "SetLineThickness" }
+func (p *optionProbe) SetLongitudeLines(int)                { p.setter = "SetLongitudeLines" }
+func (p *optionProbe) SetLoop(float64)                      { p.setter = "SetLoop" }
+func (p *optionProbe) SetMaxRadius(float64)                 { p.setter = "SetMaxRadius" }
+func (p *optionProbe) SetMinRadius(float64)                 { p.setter = "SetMinRadius" }
+func (p *optionProbe) SetMortarImage(image.Image)           { p.setter = "SetMortarImage" }
+func (p *optionProbe) SetMortarSize(int)                    { p.setter = "SetMortarSize" }
+func (p *optionProbe) SetNoiseAlgorithm(NoiseAlgorithm)     { p.setter = "SetNoiseAlgorithm" }
+func (p *optionProbe) SetNoiseIntensity(float64)            { p.setter = "SetNoiseIntensity" }
+func (p *optionProbe) SetOffsetStrength(float64)            { p.setter = "SetOffsetStrength" }
+func (p *optionProbe) SetPaintColor(color.RGBA)             { p.setter = "SetPaintColor" }
+func (p *optionProbe) SetPaintWear(float64)                 { p.setter = "SetPaintWear" }
+func (p *optionProbe) SetPalette([]color.Color)             { p.setter = "SetPalette" }
+func (p *optionProbe) SetPeriod(int, int)                   { p.setter = "SetPeriod" }
+func (p *optionProbe) SetPhase(float64)                     { p.setter = "SetPhase" }
+func (p *optionProbe) SetPlankBaseWidth(int)                { p.setter = "SetPlankBaseWidth" }
+func (p *optionProbe) SetPlankWidthVariance(float64)        { p.setter = "SetPlankWidthVariance" }
+func (p *optionProbe) SetPredicate(ColorPredicate)          { p.setter = "SetPredicate" }
+func (p *optionProbe) SetRadius(int)                        { p.setter = "SetRadius" }
+func (p *optionProbe) SetRenderDepth(RenderDepth)           { p.setter = "SetRenderDepth" }
+func (p *optionProbe) SetRenderProgress(RenderProgressFunc) { p.setter = "SetRenderProgress" }
+func (p *optionProbe) SetRuneColor(color.Color)             { p.setter = "SetRuneColor" }
+func (p *optionProbe) SetScaleRadius(int)                   { p.setter = "SetScaleRadius" }
+func (p *optionProbe) SetScaleXSpacing(int)                 { p.setter = "SetScaleXSpacing" }
+func (p *optionProbe) SetScaleYSpacing(int)                 { p.setter = "SetScaleYSpacing" }
+func (p *optionProbe) SetScanlineFrequency(float64)         { p.setter = "SetScanlineFrequency" }
+func (p *optionProbe) SetScanlineIntensity(float64)         { p.setter = "SetScanlineIntensity" }
+func (p *optionProbe) SetSeed(int64)                        { p.setter = "SetSeed" }
+func (p *optionProbe) SetSeedUint64(uint64)                 { p.setter = "SetSeedUint64" }
+func (p *optionProbe) SetSerpentine(bool)                   { p.setter = "SetSerpentine" }
+func (p *optionProbe) SetSpaceColor(color.Color)            { p.setter = "SetSpaceColor" }
+func (p *optionProbe) SetSpaceImageSource(image.Image)      { p.setter = "SetSpaceImageSource" }
+func (p *optionProbe) SetSpaceSize(int)                     { p.setter = "SetSpaceSize" }
+func (p *optionProbe) SetSpacing(int)                       { p.setter = "SetSpacing" }
+func (p *optionProbe) SetStartColor(color.Color)            { p.setter = "SetStartColor" }
+func (p *optionProbe) SetThreadWidth(int)                   { p.setter = "SetThreadWidth" }
+func (p *optionProbe) SetThreshold(float64)                 { p.setter = "SetThreshold" }
+func (p *optionProbe) SetTileSize(int)                      { p.setter = "SetTileSize" }
+func (p *optionProbe) SetTilt(float64)                      { p.setter = "SetTilt" }
+func (p *optionProbe) SetTime(float64)                      { p.setter = "SetTime" }
+func (p *optionProbe) SetTrueColor(color.Color)             { p.setter = "SetTrueColor" }
+func (p *optionProbe) SetVignetteRadius(float64)            { p.setter = "SetVignetteRadius" }
+func (p *optionProbe) SetWorkers(int)                       { p.setter = "SetWorkers" }
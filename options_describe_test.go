@@ -0,0 +1,122 @@
+package pattern
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestOptionProbeImplementsAllInterfaces(t *testing.T) {
+	probe := reflect.TypeOf(&optionProbe{})
+	for _, iface := range optionInterfaces {
+		if !probe.Implements(iface) {
+			t.Errorf("optionProbe does not implement %v", iface)
+		}
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	p := NewPolka(SetRadius(7), SetFillColor(color.White), SetBounds(image.Rect(0, 0, 20, 10)))
+	infos := map[string]OptionInfo{}
+	for _, info := range Describe(p) {
+		infos[info.Name] = info
+	}
+
+	if got := infos["Radius"]; got.Value != 7 || len(got.Args) != 1 || got.Args[0].Kind() != reflect.Int {
+		t.Errorf("Expected Radius 7 of type int, got %+v", got)
+	}
+	if got := infos["FillColor"].Value; got != color.White {
+		t.Errorf("Expected FillColor white, got %v", got)
+	}
+	if got := infos["Bounds"].Value; got != image.Rect(0, 0, 20, 10) {
+		t.Errorf("Expected Bounds to be read through Bounds(), got %v", got)
+	}
+	if _, ok := infos["SpaceSize"]; ok {
+		t.Error("Polka does not take SpaceSize but Describe listed it")
+	}
+
+	b := Describe(NewBrick(SetMortarSize(3)))
+	for _, info := range b {
+		if info.Name == "MortarSize" && info.Value != 3 {
+			t.Errorf("Expected MortarSize 3 read from the Brick field, got %v", info.Value)
+		}
+	}
+}
+
+func TestApplyStrict(t *testing.T) {
+	p := NewPolka().(*Polka)
+	err := ApplyStrict(p, SetRadius(4), SetSpaceSize(2), SetSeed(1))
+	if err == nil {
+		t.Fatal("Expected an error for options Polka does not take")
+	}
+	var oe *OptionError
+	if !errors.As(err, &oe) || oe.Index != 1 || oe.Setter != "SetSpaceSize" {
+		t.Errorf("Expected the first failure to be SetSpaceSize at index 1, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "SetSeed") {
+		t.Errorf("Expected SetSeed to be reported too, got %v", err)
+	}
+	if p.Radius.Radius != 4 {
+		t.Errorf("Expected the applicable option to be applied, got radius %d", p.Radius.Radius)
+	}
+
+	// Options may be checked through the setter's fallback.
+	if err := ApplyStrict(NewNoise(), WithSeed(3)); err != nil {
+		t.Errorf("Expected WithSeed to apply to Noise, got %v", err)
+	}
+
+	// Options that check for a concrete type are detected by their effect.
+	w := NewWarp(nil)
+	if err := ApplyStrict(w, WarpScale(3)); err != nil {
+		t.Errorf("Expected WarpScale to apply to Warp, got %v", err)
+	}
+	if err := ApplyStrict(p, WarpScale(3)); err == nil {
+		t.Error("Expected WarpScale to fail on Polka")
+	}
+
+	// A nested Strict is applied rather than tripping over the probe.
+	w = NewWarp(nil)
+	if err := ApplyStrict(w, Strict(WarpScale(3))); err != nil {
+		t.Errorf("Expected a nested Strict to apply to Warp, got %v", err)
+	}
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected a nested Strict to panic on Polka")
+			}
+		}()
+		_ = ApplyStrict(p, Strict(WarpScale(3)))
+	}()
+
+	// Func fields do not hide options that have no effect.
+	h := NewHeatmap(func(x, y float64) float64 { return x })
+	if err := ApplyStrict(h, WarpScale(3)); err == nil {
+		t.Error("Expected WarpScale to fail on Heatmap")
+	}
+	if err := ApplyStrict(h, SetZRange(0, 2)); err != nil {
+		t.Errorf("Expected SetZRange to apply to Heatmap, got %v", err)
+	}
+
+	// Changes behind a pointer field count.
+	w = NewWarp(NewPolka())
+	growSource := func(i any) {
+		if w, ok := i.(*Warp); ok {
+			w.Source.(*Polka).Radius.Radius = 9
+		}
+	}
+	if err := ApplyStrict(w, growSource); err != nil {
+		t.Errorf("Expected a change to the source to apply, got %v", err)
+	}
+}
+
+func TestStrictPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected Strict to panic for an option that does not apply")
+		}
+	}()
+	NewPolka(Strict(SetSpaceSize(2)))
+}
@@ -7,6 +7,7 @@ import (
 
 var _ image.Image = (*Padding)(nil)
 
+// Padding surrounds an image with margins filled from a background pattern.
 type Padding struct {
 	img      image.Image
 	bounds   image.Rectangle
@@ -200,7 +201,7 @@ func NewPadding(img image.Image, opts ...PaddingOption) image.Image {
 	}
 }
 
-// Helper for "Center"
+// NewCenter pads img to width by height, centred on bg.
 func NewCenter(img image.Image, width, height int, bg image.Image) image.Image {
 	b := img.Bounds()
 	mx := (width - b.Dx()) / 2
@@ -292,3 +293,72 @@ func NewAligned(img image.Image, width, height int, xAlign, yAlign float64, bg i
 		PaddingBoundary(image.Rect(0, 0, width, height)),
 	)
 }
+
+func init() {
+	RegisterPattern(&PatternType{
+		Name:     "padding",
+		Category: CategoryFilter,
+		Inputs:   []Input{{Name: "source"}, {Name: "background", Optional: true}},
+		Params: []Param{
+			{Name: "top", Type: ParamInt, Default: 0, Doc: "Margin above the source in pixels."},
+			{Name: "right", Type: ParamInt, Default: 0, Doc: "Margin right of the source in pixels."},
+			{Name: "bottom", Type: ParamInt, Default: 0, Doc: "Margin below the source in pixels."},
+			{Name: "left", Type: ParamInt, Default: 0, Doc: "Margin left of the source in pixels."},
+			{Name: "boundary", Type: ParamRect, Default: image.Rectangle{}, Doc: "Bounds of the result; empty fits the source and its margins."},
+		},
+		Sample: &Padding{},
+		New: func(a *Args) (image.Image, error) {
+			p := NewPadding(a.Input("source"),
+				PaddingTop(a.Int("top")), PaddingRight(a.Int("right")),
+				PaddingBottom(a.Int("bottom")), PaddingLeft(a.Int("left")),
+				PaddingBackground(a.Input("background")),
+				PaddingBoundary(a.Rect("boundary")),
+			)
+			return applyOps(p, a.Options), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			p := img.(*Padding)
+			a.SetInput("source", p.img)
+			a.SetInput("background", p.bgPattern)
+			a.Set("top", p.top)
+			a.Set("right", p.right)
+			a.Set("bottom", p.bottom)
+			a.Set("left", p.left)
+			a.Set("boundary", p.bounds)
+			return nil
+		},
+	})
+	// Centred and aligned images are Paddings, so they are saved as those.
+	RegisterPattern(&PatternType{
+		Name:     "center",
+		Category: CategoryFilter,
+		Inputs:   []Input{{Name: "source"}, {Name: "background", Optional: true}},
+		Params: []Param{
+			{Name: "width", Type: ParamInt, Default: 255, Doc: "Width of the result."},
+			{Name: "height", Type: ParamInt, Default: 255, Doc: "Height of the result."},
+		},
+		New: func(a *Args) (image.Image, error) {
+			return applyOps(NewCenter(a.Input("source"), a.Int("width"), a.Int("height"), a.Input("background")), a.Options), nil
+		},
+	})
+	RegisterPattern(&PatternType{
+		Name:     "aligned",
+		Category: CategoryFilter,
+		Inputs:   []Input{{Name: "source"}, {Name: "background", Optional: true}},
+		Params: []Param{
+			{Name: "width", Type: ParamInt, Default: 255, Doc: "Width of the result."},
+			{Name: "height", Type: ParamInt, Default: 255, Doc: "Height of the result."},
+			{Name: "x_align", Type: ParamFloat, Default: 0.5, Min: 0, Max: 1, Doc: "Horizontal position of the source, from left to right."},
+			{Name: "y_align", Type: ParamFloat, Default: 0.5, Min: 0, Max: 1, Doc: "Vertical position of the source, from top to bottom."},
+			{Name: "padding", Type: ParamFloats, Doc: "Padding in pixels as in CSS: all sides; vertical, horizontal; or top, right, bottom, left."},
+		},
+		New: func(a *Args) (image.Image, error) {
+			var padding []int
+			for _, f := range a.Floats("padding") {
+				padding = append(padding, int(f))
+			}
+			p := NewAligned(a.Input("source"), a.Int("width"), a.Int("height"), a.Float("x_align"), a.Float("y_align"), a.Input("background"), padding...)
+			return applyOps(p, a.Options), nil
+		},
+	})
+}
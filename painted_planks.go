@@ -236,3 +236,6 @@ func (p *PaintedPlanks) SetGrainIntensity(v float64)     { p.GrainIntensity = v
 func (p *PaintedPlanks) SetPaintWear(v float64)          { p.PaintWear = v }
 func (p *PaintedPlanks) SetPaintColor(c color.RGBA)      { p.PaintColor = c }
 
+func init() {
+	RegisterPattern(generatorType("painted_planks", &PaintedPlanks{}, NewPaintedPlanks))
+}
@@ -0,0 +1,352 @@
+package pattern
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ParamType is the type of a registered pattern parameter.
+type ParamType string
+
+const (
+	ParamInt        ParamType = "int"         // int
+	ParamFloat      ParamType = "float"       // float64
+	ParamBool       ParamType = "bool"        // bool
+	ParamString     ParamType = "string"      // string
+	ParamEnum       ParamType = "enum"        // string, one of Param.Values
+	ParamColor      ParamType = "color"       // color.Color
+	ParamColors     ParamType = "colors"      // []color.Color
+	ParamFloats     ParamType = "floats"      // []float64
+	ParamPoints     ParamType = "points"      // []image.Point
+	ParamRect       ParamType = "rect"        // image.Rectangle
+	ParamColorStops ParamType = "color_stops" // []ColorStop
+)
+
+// Param describes a parameter of a registered pattern type.
+//
+// Values are held as the Go type listed against each ParamType. Convert accepts those,
+// the equivalent decoded JSON values, and strings in the form used by the DSL:
+// colours in any form ParseColor accepts, lists separated by commas, points as "x:y",
+// rectangles as "minX,minY,maxX,maxY" and colour stops as "position:colour".
+type Param struct {
+	Name string
+	Type ParamType
+	Doc  string
+	// Default is used when no value is given.
+	Default any
+	// Min and Max bound numeric parameters when Max > Min.
+	Min, Max float64
+	// Values lists the accepted names of an enum parameter.
+	Values []string
+}
+
+// Convert checks v against the parameter's type and range and returns it as the
+// parameter's Go type.
+func (p Param) Convert(v any) (any, error) {
+	out, err := p.convert(v)
+	if err != nil {
+		return nil, fmt.Errorf("parameter %q: %w", p.Name, err)
+	}
+	if p.Max > p.Min {
+		var n float64
+		switch x := out.(type) {
+		case int:
+			n = float64(x)
+		case float64:
+			n = x
+		default:
+			return out, nil
+		}
+		if n < p.Min || n > p.Max {
+			return nil, fmt.Errorf("parameter %q: %v is outside the range %v to %v", p.Name, n, p.Min, p.Max)
+		}
+	}
+	return out, nil
+}
+
+func (p Param) convert(v any) (any, error) {
+	switch p.Type {
+	case ParamInt:
+		return convertInt(v)
+	case ParamFloat:
+		return convertFloat(v)
+	case ParamBool:
+		switch b := v.(type) {
+		case bool:
+			return b, nil
+		case string:
+			r, err := strconv.ParseBool(b)
+			if err != nil {
+				return nil, fmt.Errorf("expected true or false, got %q", b)
+			}
+			return r, nil
+		}
+		return nil, fmt.Errorf("expected true or false, got %v", v)
+	case ParamString:
+		if s, ok := v.(string); ok {
+			return s, nil
+		}
+		return nil, fmt.Errorf("expected a string, got %v", v)
+	case ParamEnum:
+		s, _ := v.(string)
+		for _, name := range p.Values {
+			if name == s {
+				return s, nil
+			}
+		}
+		return nil, fmt.Errorf("expected one of %s, got %v", strings.Join(p.Values, ", "), v)
+	case ParamColor:
+		return convertColor(v)
+	case ParamColors:
+		return convertColors(v)
+	case ParamFloats:
+		return convertFloats(v)
+	case ParamPoints:
+		if l, ok := v.([]image.Point); ok {
+			return l, nil
+		}
+		items, err := listItems(v)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]image.Point, len(items))
+		for i, e := range items {
+			xy, err := convertInts(e, ":")
+			if err != nil || len(xy) != 2 {
+				return nil, fmt.Errorf("expected points as x:y or [x, y], got %v", e)
+			}
+			out[i] = image.Pt(xy[0], xy[1])
+		}
+		return out, nil
+	case ParamRect:
+		if r, ok := v.(image.Rectangle); ok {
+			return r, nil
+		}
+		l, err := convertInts(v, ",")
+		if err != nil || len(l) != 4 {
+			return nil, fmt.Errorf("expected minX,minY,maxX,maxY, got %v", v)
+		}
+		return image.Rect(l[0], l[1], l[2], l[3]), nil
+	case ParamColorStops:
+		if l, ok := v.([]ColorStop); ok {
+			return l, nil
+		}
+		items, err := listItems(v)
+		if err != nil {
+			return nil, err
+		}
+		out := make([]ColorStop, len(items))
+		for i, e := range items {
+			var pos, col any
+			switch s := e.(type) {
+			case map[string]any:
+				pos, col = s["position"], s["color"]
+			case string:
+				before, after, ok := strings.Cut(s, ":")
+				if !ok {
+					return nil, fmt.Errorf("expected a colour stop as position:colour, got %q", s)
+				}
+				pos, col = before, after
+			default:
+				return nil, fmt.Errorf("expected a colour stop, got %v", e)
+			}
+			if out[i].Position, err = convertFloat(pos); err != nil {
+				return nil, err
+			}
+			if out[i].Color, err = convertColor(col); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("unknown parameter type %q", p.Type)
+}
+
+// Format returns v, a value of the parameter's Go type, as a JSON or YAML friendly value
+// that Convert accepts.
+func (p Param) Format(v any) any {
+	switch x := v.(type) {
+	case color.Color:
+		return formatColor(x)
+	case []color.Color:
+		return formatColors(x)
+	case []image.Point:
+		out := make([][]int, len(x))
+		for i, pt := range x {
+			out[i] = []int{pt.X, pt.Y}
+		}
+		return out
+	case image.Rectangle:
+		return []int{x.Min.X, x.Min.Y, x.Max.X, x.Max.Y}
+	case []ColorStop:
+		out := make([]map[string]any, len(x))
+		for i, s := range x {
+			out[i] = map[string]any{"position": s.Position, "color": formatColor(s.Color)}
+		}
+		return out
+	}
+	return v
+}
+
+func convertInt(v any) (int, error) {
+	switch n := v.(type) {
+	case int:
+		return n, nil
+	case int64:
+		return int(n), nil
+	}
+	f, err := convertFloat(v)
+	if err != nil {
+		return 0, err
+	}
+	if f != math.Trunc(f) {
+		return 0, fmt.Errorf("expected an integer, got %v", v)
+	}
+	return int(f), nil
+}
+
+func convertFloat(v any) (float64, error) {
+	switch n := v.(type) {
+	case float64:
+		return n, nil
+	case int:
+		return float64(n), nil
+	case int64:
+		return float64(n), nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		if err != nil {
+			return 0, fmt.Errorf("expected a number, got %q", n)
+		}
+		return f, nil
+	}
+	return 0, fmt.Errorf("expected a number, got %v", v)
+}
+
+func convertFloats(v any) ([]float64, error) {
+	if l, ok := v.([]float64); ok {
+		return l, nil
+	}
+	items, err := listItems(v)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]float64, len(items))
+	for i, e := range items {
+		if out[i], err = convertFloat(e); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func convertInts(v any, sep string) ([]int, error) {
+	var items []any
+	if s, ok := v.(string); ok {
+		for _, part := range strings.Split(s, sep) {
+			items = append(items, part)
+		}
+	} else {
+		var err error
+		if items, err = listItems(v); err != nil {
+			return nil, err
+		}
+	}
+	out := make([]int, len(items))
+	for i, e := range items {
+		n, err := convertInt(e)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = n
+	}
+	return out, nil
+}
+
+func convertColor(v any) (color.Color, error) {
+	switch c := v.(type) {
+	case color.Color:
+		return c, nil
+	case string:
+		return ParseColor(c)
+	}
+	return nil, fmt.Errorf("expected a colour, got %v", v)
+}
+
+func convertColors(v any) ([]color.Color, error) {
+	switch l := v.(type) {
+	case []color.Color:
+		return l, nil
+	case color.Palette:
+		return []color.Color(l), nil
+	case string:
+		if p, ok := namedPalettes[strings.ToLower(strings.TrimSpace(l))]; ok {
+			return append([]color.Color(nil), p...), nil
+		}
+	}
+	items, err := listItems(v)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]color.Color, len(items))
+	for i, e := range items {
+		if out[i], err = convertColor(e); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// listItems returns the elements of a decoded list, or of a comma separated string.
+// Commas inside parentheses do not split, so colours such as rgb(1, 2, 3) stay whole.
+func listItems(v any) ([]any, error) {
+	switch l := v.(type) {
+	case []any:
+		return l, nil
+	case string:
+		var items []any
+		depth, start := 0, 0
+		for i, r := range l {
+			switch r {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			case ',':
+				if depth == 0 {
+					items = append(items, strings.TrimSpace(l[start:i]))
+					start = i + 1
+				}
+			}
+		}
+		if s := strings.TrimSpace(l[start:]); s != "" || len(items) > 0 {
+			items = append(items, s)
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("expected a list, got %v", v)
+}
+
+// formatColor writes c as "#rrggbb", or "#rrggbbaa" when it is not opaque.
+func formatColor(c color.Color) string {
+	if c == nil {
+		return "transparent"
+	}
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	if n.A == 0xff {
+		return fmt.Sprintf("#%02x%02x%02x", n.R, n.G, n.B)
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", n.R, n.G, n.B, n.A)
+}
+
+func formatColors(cs []color.Color) []string {
+	out := make([]string, len(cs))
+	for i, c := range cs {
+		out[i] = formatColor(c)
+	}
+	return out
+}
@@ -243,3 +243,7 @@ func mixRGBA(a, b color.RGBA, t float64) color.RGBA {
 		A: clampChannel(float64(a.A)*inv + float64(b.A)*t),
 	}
 }
+
+func init() {
+	RegisterPattern(generatorType("pcb_traces", &PCBTraces{}, NewPCBTraces))
+}
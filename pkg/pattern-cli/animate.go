@@ -0,0 +1,145 @@
+package pattern_cli
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"os"
+	"reflect"
+
+	"github.com/arran4/go-pattern"
+	"github.com/arran4/go-pattern/animation"
+)
+
+// Animate is a subcommand `pattern-cli animate`
+//
+// It renders frames frames of a DSL pipeline, or the graph file at graph, fps to
+// the second, and writes them to out ("-" writes standard output) as an animated
+// gif or apng. format defaults to apng for files named .png or .apng, and gif
+// otherwise. GIF frames are reduced to palette, a palette name or a list of
+// colours, with the named dither. width, height and bounds are as for render.
+func Animate(pipeline, graph string, width, height int, bounds string, frames int, fps float64, format, palette, dither, out string) error {
+	if (pipeline == "") == (graph == "") {
+		return errors.New("animate takes either a pipeline or a graph file")
+	}
+	if out == "" {
+		return errors.New("animate requires an output file, or - for standard output")
+	}
+	if frames < 1 || fps <= 0 {
+		return fmt.Errorf("animate needs at least one frame and a positive frame rate, got %d at %v", frames, fps)
+	}
+	if format == "" {
+		switch FormatOf(out) {
+		case "png", "apng":
+			format = "apng"
+		default:
+			format = "gif"
+		}
+	}
+	if format != "gif" && format != "apng" {
+		return fmt.Errorf("unsupported animation format %q, expected gif or apng", format)
+	}
+	var opts animation.GIFOptions
+	if palette != "" {
+		v, err := pattern.ParseValue(reflect.TypeOf(color.Palette(nil)), palette)
+		if err != nil {
+			return fmt.Errorf("palette: %w", err)
+		}
+		opts.Palette = v.(color.Palette)
+	}
+	if dither != "" {
+		d, err := ditherFunc(dither)
+		if err != nil {
+			return err
+		}
+		opts.Dither = d
+	}
+
+	source, err := frameSource(pipeline, graph)
+	if err != nil {
+		return err
+	}
+	images := make([]image.Image, 0, frames)
+	for i := 0; i < frames; i++ {
+		// The chain is built again for each frame, as some patterns keep what
+		// they first draw.
+		img, err := source()
+		if err != nil {
+			return err
+		}
+		if i == 0 && !pattern.IsAnimated(img) {
+			return errors.New("nothing in the pipeline is animated")
+		}
+		r, err := renderBounds(img.Bounds(), width, height, bounds)
+		if err != nil {
+			return err
+		}
+		if r != img.Bounds() {
+			pattern.SetChainBounds(img, r)
+		}
+		pattern.SetChainTime(img, float64(i)/fps)
+		frame, err := pattern.Render(context.Background(), img, pattern.SetBounds(r))
+		if err != nil {
+			return err
+		}
+		images = append(images, frame)
+	}
+
+	encode := func(w io.Writer) error {
+		if format == "apng" {
+			return animation.EncodeAPNG(w, images, fps)
+		}
+		return animation.EncodeGIF(w, images, fps, &opts)
+	}
+	if out == "-" {
+		return encode(os.Stdout)
+	}
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	if err := encode(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// frameSource returns a function building the pipeline, or loading the graph
+// file, afresh. A graph read from standard input is read once and kept.
+func frameSource(pipeline, graph string) (func() (image.Image, error), error) {
+	if graph != "-" {
+		return func() (image.Image, error) { return build(pipeline, graph) }, nil
+	}
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return nil, err
+	}
+	return func() (image.Image, error) { return pattern.LoadGraph(bytes.NewReader(data)) }, nil
+}
+
+// ditherFunc returns a GIF dither using the registered dither pattern called name.
+func ditherFunc(name string) (func(image.Image, color.Palette) image.Image, error) {
+	t, ok := pattern.LookupPattern(name)
+	if !ok || t.Category != pattern.CategoryDither {
+		return nil, fmt.Errorf("unknown dither %q; see pattern-cli list -category dither", name)
+	}
+	if t.Param("palette") == nil {
+		return nil, fmt.Errorf("%s does not take a palette", name)
+	}
+	return func(img image.Image, p color.Palette) image.Image {
+		d, err := t.Build(pattern.Args{
+			Inputs: map[string][]image.Image{t.Inputs[0].Name: {img}},
+			Values: map[string]any{"palette": []color.Color(p)},
+		})
+		if err != nil {
+			// The pattern type was checked above, so this does not happen.
+			panic(err)
+		}
+		return d
+	}, nil
+}
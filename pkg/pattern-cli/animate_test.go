@@ -0,0 +1,60 @@
+package pattern_cli
+
+import (
+	"image"
+	"image/gif"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAnimate(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out.gif")
+	if err := Animate("plasma | vhs", "", 16, 8, "", 3, 10, "", "websafe", "bayer4x4_dither", out); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	g, err := gif.DecodeAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(g.Image) != 3 || g.Delay[0] != 10 || g.Image[0].Bounds() != image.Rect(0, 0, 16, 8) {
+		t.Errorf("Expected 3 16x8 frames of 1/10s, got %d frames of %v, delay %d", len(g.Image), g.Image[0].Bounds(), g.Delay[0])
+	}
+
+	out = filepath.Join(dir, "out.png")
+	if err := Animate("concentric_water", "", 8, 8, "", 2, 2, "", "", "", out); err != nil {
+		t.Fatal(err)
+	}
+	f2, err := os.Open(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f2.Close()
+	if _, err := png.Decode(f2); err != nil {
+		t.Errorf("Expected an APNG: %v", err)
+	}
+
+	for _, c := range []struct {
+		pipeline, format, dither, want string
+		frames                         int
+	}{
+		{"checker", "", "", "nothing in the pipeline is animated", 2},
+		{"plasma", "webp", "", "expected gif or apng", 2},
+		{"plasma", "", "blur", "unknown dither", 2},
+		{"plasma", "", "bayer_dither", "does not take a palette", 2},
+		{"plasma", "", "", "at least one frame", 0},
+	} {
+		err := Animate(c.pipeline, "", 8, 8, "", c.frames, 10, c.format, "", c.dither, filepath.Join(dir, "x.gif"))
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("%+v: expected an error containing %q, got %v", c, c.want, err)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package pattern_cli
+
+import (
+	"image"
+
+	"github.com/arran4/go-pattern"
+	"github.com/arran4/go-pattern/dsl"
+)
+
+// setOptions sets each named argument as an option on img, with pattern.SetOption.
+func setOptions(img image.Image, named dsl.Args) (image.Image, error) {
+	for _, arg := range named {
+		var v any = arg.Value
+		if arg.IsImage() {
+			v = arg.Image
+		}
+		if err := pattern.SetOption(img, arg.Name, v); err != nil {
+			return nil, err
+		}
+	}
+	return img, nil
+}
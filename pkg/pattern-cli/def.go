@@ -1,79 +1,107 @@
 package pattern_cli
 
 import (
-	"bufio"
+	"context"
 	"fmt"
 	"github.com/arran4/go-pattern/dsl"
 	"image"
-	"image/color"
-	"image/png"
 	"os"
 	"strconv"
-	"strings"
 
 	"github.com/arran4/go-pattern"
-	"golang.org/x/image/colornames"
+	"github.com/arran4/go-pattern/termimg"
 )
 
-// Repl is a subcommand `pattern-cli repl`
-func Repl() {
-	scanner := bufio.NewScanner(os.Stdin)
-	fmt.Print("> ")
-	funcMap := make(dsl.FuncMap)
-	registerCommands(funcMap)
-	for scanner.Scan() {
-		input := scanner.Text()
-		if input == "exit" || input == "quit" {
-			break
-		}
-		if err := process(input, funcMap); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		}
-		fmt.Print("> ")
-	}
-	if err := scanner.Err(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error reading stdin: %v\n", err)
-		os.Exit(1)
-	}
-}
-
 // Run is a subcommand `pattern-cli run`
 func Run(pipeline string) {
 	funcMap := make(dsl.FuncMap)
 	registerCommands(funcMap)
-	if err := process(pipeline, funcMap); err != nil {
+	if err := process(pipeline, dsl.NewInterpreter(funcMap)); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
 }
 
-func process(input string, fm dsl.FuncMap) error {
-	p, err := dsl.Parse(input)
+func process(input string, interp *dsl.Interpreter) error {
+	s, err := dsl.ParseScript(input)
 	if err != nil {
 		return err
 	}
-	_, err = p.Execute(fm, nil)
+	_, err = interp.Run(s, nil)
 	return err
 }
 
+// builtinDocs documents the commands registerCommands adds by hand.
+var builtinDocs = map[string]commandDoc{
+	"checkers": {
+		Category:    "generator",
+		Description: "Alternates between two colours in a checkerboard fashion.",
+		Args:        []string{"color1 color.Color", "color2 color.Color"},
+		Example:     "checkers black white",
+	},
+	"zoom": {
+		Category:    "filter",
+		Description: "Enlarges the input by a whole number factor.",
+		Args:        []string{"factor int"},
+		Example:     "checker | zoom 4",
+	},
+	"mirror": {
+		Category:    "filter",
+		Description: "Flips the input horizontally (h), vertically (v) or both (hv). With no argument it flips horizontally.",
+		Args:        []string{"axes string"},
+		Example:     "linear_gradient | mirror hv",
+	},
+	"edgedetect": {
+		Category:    "filter",
+		Description: "Applies Sobel edge detection to the input.",
+		Example:     "checker | edgedetect",
+	},
+	"circle": {
+		Category:    "generator",
+		Description: "Draws a circle in the line colour on the space colour.",
+		Args:        []string{"line color.Color", "space color.Color"},
+		Example:     "circle black white",
+	},
+	"save": {
+		Category:    "output",
+		Description: "Saves the input to a file, in the format named by its extension, and passes the input on.",
+		Args:        []string{"filename string"},
+		Example:     "checker | save checker.png",
+	},
+	"preview": {
+		Category:    "output",
+		Description: "Draws the input in the terminal as ansi half blocks, sixel or kitty graphics, scaled down to width (columns for ansi, pixels otherwise), and passes the input on.",
+		Args:        []string{"format string", "width int"},
+		Example:     "checker | preview ansi 40",
+	},
+}
+
 func registerCommands(fm dsl.FuncMap) {
-	RegisterGeneratedCommands(fm)
-	fm["checkers"] = func(args []string, input image.Image) (image.Image, error) {
+	RegisterPatternCommands(fm)
+	fm["checkers"] = func(call dsl.Args, input image.Image) (image.Image, error) {
+		args, err := call.Positional()
+		if err != nil {
+			return nil, err
+		}
 		if len(args) < 2 {
 			return nil, fmt.Errorf("checkers requires 2 color arguments")
 		}
-		c1, err := parseColor(args[0])
+		c1, err := pattern.ParseColor(args[0])
 		if err != nil {
 			return nil, err
 		}
-		c2, err := parseColor(args[1])
+		c2, err := pattern.ParseColor(args[1])
 		if err != nil {
 			return nil, err
 		}
 		return pattern.NewChecker(c1, c2), nil
 	}
 
-	fm["zoom"] = func(args []string, input image.Image) (image.Image, error) {
+	fm["zoom"] = func(call dsl.Args, input image.Image) (image.Image, error) {
+		args, err := call.Positional()
+		if err != nil {
+			return nil, err
+		}
 		if input == nil {
 			return nil, fmt.Errorf("zoom requires an input image")
 		}
@@ -87,28 +115,11 @@ func registerCommands(fm dsl.FuncMap) {
 		return pattern.NewSimpleZoom(input, factor), nil
 	}
 
-	fm["transposed"] = func(args []string, input image.Image) (image.Image, error) {
-		if input == nil {
-			return nil, fmt.Errorf("transposed requires an input image")
-		}
-		x, y := 0, 0
-		var err error
-		if len(args) >= 1 {
-			x, err = strconv.Atoi(args[0])
-			if err != nil {
-				return nil, fmt.Errorf("invalid x offset: %v", err)
-			}
-		}
-		if len(args) >= 2 {
-			y, err = strconv.Atoi(args[1])
-			if err != nil {
-				return nil, fmt.Errorf("invalid y offset: %v", err)
-			}
+	fm["mirror"] = func(call dsl.Args, input image.Image) (image.Image, error) {
+		args, err := call.Positional()
+		if err != nil {
+			return nil, err
 		}
-		return pattern.NewTransposed(input, x, y), nil
-	}
-
-	fm["mirror"] = func(args []string, input image.Image) (image.Image, error) {
 		if input == nil {
 			return nil, fmt.Errorf("mirror requires an input image")
 		}
@@ -135,59 +146,39 @@ func registerCommands(fm dsl.FuncMap) {
 		return pattern.NewMirror(input, horizontal, vertical), nil
 	}
 
-	fm["rotate"] = func(args []string, input image.Image) (image.Image, error) {
-		if input == nil {
-			return nil, fmt.Errorf("rotate requires an input image")
-		}
-		if len(args) < 1 {
-			return nil, fmt.Errorf("rotate requires degrees (90, 180, 270)")
-		}
-		deg, err := strconv.Atoi(args[0])
-		if err != nil {
-			return nil, fmt.Errorf("invalid degrees: %v", err)
+	fm["edgedetect"] = func(call dsl.Args, input image.Image) (image.Image, error) {
+		if len(call) > 0 {
+			return nil, fmt.Errorf("edgedetect takes no arguments")
 		}
-		return pattern.NewRotate(input, deg), nil
-  }
-	fm["edgedetect"] = func(args []string, input image.Image) (image.Image, error) {
 		if input == nil {
 			return nil, fmt.Errorf("edgedetect requires an input image")
 		}
 		return pattern.NewEdgeDetect(input), nil
   }
-	fm["quantize"] = func(args []string, input image.Image) (image.Image, error) {
-		if input == nil {
-			return nil, fmt.Errorf("quantize requires an input image")
-		}
-		if len(args) < 1 {
-			return nil, fmt.Errorf("quantize requires a levels argument")
-		}
-		levels, err := strconv.Atoi(args[0])
+	fm["circle"] = func(call dsl.Args, input image.Image) (image.Image, error) {
+		args, err := call.Positional()
 		if err != nil {
-			return nil, fmt.Errorf("invalid levels: %v", err)
+			return nil, err
 		}
-		return pattern.NewQuantize(input, levels), nil
-	}
-
-	fm["null"] = func(args []string, input image.Image) (image.Image, error) {
-		return pattern.NewNull(), nil
-	}
-
-	fm["circle"] = func(args []string, input image.Image) (image.Image, error) {
 		if len(args) < 2 {
 			return nil, fmt.Errorf("circle requires 2 color arguments (line, space)")
 		}
-		c1, err := parseColor(args[0])
+		c1, err := pattern.ParseColor(args[0])
 		if err != nil {
 			return nil, err
 		}
-		c2, err := parseColor(args[1])
+		c2, err := pattern.ParseColor(args[1])
 		if err != nil {
 			return nil, err
 		}
 		return pattern.NewCircle(pattern.SetLineColor(c1), pattern.SetSpaceColor(c2)), nil
 	}
 
-	fm["save"] = func(args []string, input image.Image) (image.Image, error) {
+	fm["save"] = func(call dsl.Args, input image.Image) (image.Image, error) {
+		args, err := call.Positional()
+		if err != nil {
+			return nil, err
+		}
 		if input == nil {
 			return nil, fmt.Errorf("save requires an input image")
 		}
@@ -195,27 +186,66 @@ func registerCommands(fm dsl.FuncMap) {
 			return nil, fmt.Errorf("save requires a filename argument")
 		}
 		filename := args[0]
+		format, err := ParseFormat(FormatOf(filename))
+		if err != nil {
+			return nil, fmt.Errorf("unsupported file format: %s", filename)
+		}
+		rendered, err := pattern.Render(context.Background(), input)
+		if err != nil {
+			return nil, err
+		}
 		f, err := os.Create(filename)
 		if err != nil {
 			return nil, err
 		}
 		defer f.Close()
 
-		if strings.HasSuffix(filename, ".png") {
-			if err := png.Encode(f, input); err != nil {
-				return nil, err
-			}
-		} else {
-			return nil, fmt.Errorf("unsupported file format: %s", filename)
+		if err := Encode(f, rendered, format, 0); err != nil {
+			return nil, err
 		}
 		fmt.Printf("Saved to %s\n", filename)
 		return input, nil
 	}
-}
 
-func parseColor(s string) (color.Color, error) {
-	if c, ok := colornames.Map[s]; ok {
-		return c, nil
+	fm["preview"] = func(call dsl.Args, input image.Image) (image.Image, error) {
+		if input == nil {
+			return nil, fmt.Errorf("preview requires an input image")
+		}
+		format, width := termimg.ANSI, 0
+		positional, named := call.SplitNamed()
+		if len(positional) > 2 {
+			return nil, fmt.Errorf("preview takes a format and a width")
+		}
+		for i, arg := range append(positional, named...) {
+			name := arg.Name
+			if name == "" {
+				name = []string{"format", "width"}[i]
+			}
+			if arg.Image != nil {
+				return nil, fmt.Errorf("preview %s: unexpected image argument", name)
+			}
+			var err error
+			switch name {
+			case "format":
+				format, err = termimg.ParseFormat(arg.Value)
+			case "width":
+				if width, err = strconv.Atoi(arg.Value); err == nil && width < 1 {
+					err = fmt.Errorf("width must be positive, got %d", width)
+				}
+			default:
+				err = fmt.Errorf("unknown argument")
+			}
+			if err != nil {
+				return nil, fmt.Errorf("preview %s: %w", name, err)
+			}
+		}
+		rendered, err := pattern.Render(context.Background(), input)
+		if err != nil {
+			return nil, err
+		}
+		if err := termimg.Encode(os.Stdout, rendered, format, width); err != nil {
+			return nil, err
+		}
+		return input, nil
 	}
-	return nil, fmt.Errorf("unknown color: %s", s)
 }
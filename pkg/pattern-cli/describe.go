@@ -0,0 +1,248 @@
+package pattern_cli
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+
+	"github.com/arran4/go-pattern"
+	"github.com/arran4/go-pattern/dsl"
+)
+
+// commandDoc documents a DSL command for list and describe. Commands from the
+// pattern registry take their category, inputs and parameters from their
+// PatternType instead.
+type commandDoc struct {
+	Category    string
+	Description string
+	// Args lists the positional arguments, as "name type".
+	Args []string
+	// Example is a pipeline using the command.
+	Example string
+	// GoUsage is Go code building the pattern, taken from its example.
+	GoUsage string
+}
+
+// List is a subcommand `pattern-cli list`
+func List(category string) error {
+	return writeList(os.Stdout, category)
+}
+
+// Describe is a subcommand `pattern-cli describe`
+func Describe(name string) error {
+	return writeDescribe(os.Stdout, name)
+}
+
+// commandDocs documents every command registerCommands adds.
+func commandDocs() map[string]commandDoc {
+	fm := make(dsl.FuncMap)
+	registerCommands(fm)
+	docs := make(map[string]commandDoc, len(fm))
+	for name := range fm {
+		doc := generatedDocs[name]
+		if b, ok := builtinDocs[name]; ok {
+			// The hand written commands replace any generated command of the same name.
+			b.Description = cmp.Or(b.Description, doc.Description)
+			b.GoUsage = cmp.Or(b.GoUsage, doc.GoUsage)
+			doc = b
+		} else if t, ok := pattern.LookupPattern(name); ok {
+			doc.Category = string(t.Category)
+			doc.Example = patternExample(t)
+		}
+		docs[name] = doc
+	}
+	return docs
+}
+
+func writeList(w io.Writer, category string) error {
+	docs := commandDocs()
+	var categories []string
+	for _, doc := range docs {
+		if !slices.Contains(categories, doc.Category) {
+			categories = append(categories, doc.Category)
+		}
+	}
+	slices.Sort(categories)
+	if category != "" && !slices.Contains(categories, category) {
+		return fmt.Errorf("unknown category %q: expected one of %s", category, strings.Join(categories, ", "))
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, name := range slices.Sorted(maps.Keys(docs)) {
+		doc := docs[name]
+		if category != "" && doc.Category != category {
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", name, doc.Category, summary(doc.Description))
+	}
+	return tw.Flush()
+}
+
+func writeDescribe(w io.Writer, name string) error {
+	doc, ok := commandDocs()[name]
+	if !ok {
+		return fmt.Errorf("unknown command: %s", name)
+	}
+	fmt.Fprintf(w, "%s (%s)\n", name, doc.Category)
+	if doc.Description != "" {
+		fmt.Fprintf(w, "\n%s\n", doc.Description)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, builtin := builtinDocs[name]
+	if t, ok := pattern.LookupPattern(name); ok && !builtin {
+		if len(t.Inputs) > 0 {
+			fmt.Fprintln(tw, "\nInputs:")
+			for _, in := range t.Inputs {
+				var notes []string
+				if in.Optional {
+					notes = append(notes, "optional")
+				}
+				if in.Variadic {
+					notes = append(notes, "any number of images")
+				}
+				fmt.Fprintf(tw, "    %s\t%s\n", in.Name, strings.Join(notes, ", "))
+			}
+		}
+		if len(t.Params) > 0 {
+			fmt.Fprintln(tw, "\nParameters:")
+			for _, p := range t.Params {
+				fmt.Fprintf(tw, "    %s\t%s\t%s\t%s\n", p.Name, p.Type, formatParam(p, p.Default), paramDoc(p))
+			}
+		}
+		// Options that share a parameter's name are set as the parameter.
+		var options []string
+		for _, o := range pattern.Describe(t.Sample) {
+			if t.Param(optionName(o.Name)) != nil {
+				continue
+			}
+			var types []string
+			for _, a := range o.Args {
+				types = append(types, a.String())
+			}
+			options = append(options, fmt.Sprintf("    %s\t%s\n", optionName(o.Name), strings.Join(types, ", ")))
+		}
+		if len(options) > 0 {
+			fmt.Fprintln(tw, "\nOptions:")
+			for _, o := range options {
+				fmt.Fprint(tw, o)
+			}
+		}
+	} else if len(doc.Args) > 0 {
+		fmt.Fprintln(tw, "\nArguments:")
+		for _, arg := range doc.Args {
+			n, typ, _ := strings.Cut(arg, " ")
+			fmt.Fprintf(tw, "    %s\t%s\n", n, typ)
+		}
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	if doc.Example != "" {
+		fmt.Fprintf(w, "\nExample:\n\n    %s\n", doc.Example)
+	}
+	if doc.GoUsage != "" {
+		fmt.Fprintf(w, "\nGo usage:\n\n%s\n", doc.GoUsage)
+	}
+	return nil
+}
+
+// patternExample writes a pipeline using t. The first input is piped in, any
+// other required input is a sub-pipeline, and the first parameters are set to
+// their defaults.
+func patternExample(t *pattern.PatternType) string {
+	var pl dsl.Pipeline
+	cmd := dsl.Command{Name: t.Name}
+	source := &dsl.Pipeline{{Name: "linear_gradient"}}
+	for i, in := range t.Inputs {
+		switch {
+		case in.Optional:
+		case i == 0:
+			pl = append(pl, dsl.Command{Name: "checker"})
+			if in.Variadic {
+				cmd.Args = append(cmd.Args, dsl.Arg{Sub: source})
+			}
+		default:
+			cmd.Args = append(cmd.Args, dsl.Arg{Name: in.Name, Sub: source})
+		}
+	}
+	set := 0
+	for _, p := range t.Params {
+		if v := formatParam(p, p.Default); v != "" && set < 2 {
+			cmd.Args = append(cmd.Args, dsl.Arg{Name: p.Name, Value: v})
+			set++
+		}
+	}
+	return append(pl, cmd).String()
+}
+
+// formatParam writes v, a value of p's Go type, as a command argument.
+func formatParam(p pattern.Param, v any) string {
+	if v == nil {
+		return ""
+	}
+	var items []string
+	switch x := p.Format(v).(type) {
+	case []string:
+		items = x
+	case []int:
+		for _, n := range x {
+			items = append(items, strconv.Itoa(n))
+		}
+	case []float64:
+		for _, f := range x {
+			items = append(items, strconv.FormatFloat(f, 'g', -1, 64))
+		}
+	case [][]int:
+		for _, pt := range x {
+			items = append(items, fmt.Sprintf("%d:%d", pt[0], pt[1]))
+		}
+	case []map[string]any:
+		for _, s := range x {
+			items = append(items, fmt.Sprintf("%v:%v", s["position"], s["color"]))
+		}
+	default:
+		return fmt.Sprint(x)
+	}
+	return strings.Join(items, ",")
+}
+
+// paramDoc is p's doc with its range or accepted values.
+func paramDoc(p pattern.Param) string {
+	s := p.Doc
+	switch {
+	case len(p.Values) > 0:
+		s += " (one of " + strings.Join(p.Values, ", ") + ")"
+	case p.Max > p.Min:
+		s += fmt.Sprintf(" (%v to %v)", p.Min, p.Max)
+	}
+	return strings.TrimSpace(s)
+}
+
+// optionName writes the name of an option, such as LineColor, the way it is
+// given as a named argument: line_color.
+func optionName(name string) string {
+	var sb strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 && (!unicode.IsUpper(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			sb.WriteRune('_')
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
+
+// summary is the first sentence of a description, on one line.
+func summary(description string) string {
+	s := strings.Join(strings.Fields(description), " ")
+	if i := strings.Index(s, ". "); i >= 0 {
+		return s[:i+1]
+	}
+	return s
+}
@@ -0,0 +1,80 @@
+package pattern_cli
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/arran4/go-pattern/dsl"
+)
+
+func TestCommandExamples(t *testing.T) {
+	fm := make(dsl.FuncMap)
+	registerCommands(fm)
+	for name, doc := range commandDocs() {
+		if doc.Example == "" || name == "save" {
+			continue
+		}
+		t.Run(name, func(t *testing.T) {
+			if !strings.Contains(doc.Example, name) {
+				t.Errorf("Example %q does not use %s", doc.Example, name)
+			}
+			s, err := dsl.ParseScript(doc.Example)
+			if err != nil {
+				t.Fatalf("Example %q does not parse: %v", doc.Example, err)
+			}
+			img, err := s.Execute(fm, nil)
+			if err != nil {
+				t.Fatalf("Example %q failed: %v", doc.Example, err)
+			}
+			img.At(img.Bounds().Min.X, img.Bounds().Min.Y)
+		})
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	for name, doc := range commandDocs() {
+		if doc.Category == "" {
+			t.Errorf("%s has no category", name)
+		}
+		if doc.Description == "" || strings.Contains(doc.Description, "convenience function") || strings.HasPrefix(doc.Description, "Helper for") {
+			t.Errorf("%s has no useful description: %q", name, doc.Description)
+		}
+		for _, output := range []string{"os.Create", "png.Encode", "OutputFilename"} {
+			if strings.Contains(doc.GoUsage, output) {
+				t.Errorf("Go usage of %s writes its output with %s", name, output)
+			}
+		}
+		var sb strings.Builder
+		if err := writeDescribe(&sb, name); err != nil {
+			t.Errorf("Describe(%s) failed: %v", name, err)
+		}
+	}
+	var sb strings.Builder
+	if err := writeDescribe(&sb, "brick"); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"brick (generator)", "mortar_size  int", "Example:\n\n    brick width=40 height=20\n", "NewBrick("} {
+		if !strings.Contains(sb.String(), want) {
+			t.Errorf("Expected the description of brick to contain %q, got:\n%s", want, sb.String())
+		}
+	}
+	if err := writeDescribe(io.Discard, "no_such_command"); err == nil {
+		t.Error("Expected an error describing an unknown command")
+	}
+}
+
+func TestList(t *testing.T) {
+	var sb strings.Builder
+	if err := writeList(&sb, "dither"); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(sb.String()), "\n") {
+		if fields := strings.Fields(line); len(fields) < 2 || fields[1] != "dither" {
+			t.Errorf("Expected only dithers, got %q", line)
+		}
+	}
+	if err := writeList(io.Discard, "shapes"); err == nil || !strings.Contains(err.Error(), "expected one of") {
+		t.Errorf("Expected an unknown category error, got %v", err)
+	}
+}
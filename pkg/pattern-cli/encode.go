@@ -0,0 +1,76 @@
+package pattern_cli
+
+import (
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/arran4/go-pattern/termimg"
+	"golang.org/x/image/bmp"
+	"golang.org/x/image/tiff"
+)
+
+// Formats lists the image formats Encode writes. The last three draw the image
+// in a terminal; see termimg.
+var Formats = []string{"png", "jpeg", "gif", "bmp", "tiff", "ansi", "sixel", "kitty"}
+
+// ParseFormat returns the name in Formats of format, accepting jpg and tif too.
+func ParseFormat(format string) (string, error) {
+	switch f := strings.ToLower(format); f {
+	case "jpg":
+		return "jpeg", nil
+	case "tif":
+		return "tiff", nil
+	case "png", "jpeg", "gif", "bmp", "tiff", "ansi", "sixel", "kitty":
+		return f, nil
+	}
+	return "", fmt.Errorf("unsupported format %q, expected one of %s", format, strings.Join(Formats, ", "))
+}
+
+// IsTerminalFormat reports whether format, as returned by ParseFormat, draws the
+// image in a terminal rather than writing an image file.
+func IsTerminalFormat(format string) bool {
+	_, err := termimg.ParseFormat(format)
+	return err == nil
+}
+
+// Encode writes img to w in format, as accepted by ParseFormat. quality is the JPEG
+// quality from 1 to 100, or 0 for the default; other formats ignore it. Terminal
+// formats are scaled down to termimg.DefaultWidth.
+func Encode(w io.Writer, img image.Image, format string, quality int) error {
+	format, err := ParseFormat(format)
+	if err != nil {
+		return err
+	}
+	switch format {
+	case "png":
+		return png.Encode(w, img)
+	case "jpeg":
+		if quality < 0 || quality > 100 {
+			return fmt.Errorf("quality %d is outside 1 to 100", quality)
+		}
+		if quality == 0 {
+			quality = jpeg.DefaultQuality
+		}
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+	case "gif":
+		return gif.Encode(w, img, nil)
+	case "bmp":
+		return bmp.Encode(w, img)
+	case "ansi", "sixel", "kitty":
+		return termimg.Encode(w, img, termimg.Format(format), 0)
+	default: // tiff
+		return tiff.Encode(w, img, &tiff.Options{Compression: tiff.Deflate})
+	}
+}
+
+// FormatOf returns the format named by the extension of filename, or "" if it has
+// none.
+func FormatOf(filename string) string {
+	return strings.ToLower(strings.TrimPrefix(filepath.Ext(filename), "."))
+}
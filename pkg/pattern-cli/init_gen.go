@@ -1,779 +1,733 @@
 // Code generated by cmd/bootstrap/main.go; DO NOT EDIT.
 package pattern_cli
 
-import (
-	"fmt"
-	"image"
-	"strconv"
-	"github.com/arran4/go-pattern/dsl"
-	"github.com/arran4/go-pattern"
-)
+// generatedDocs documents the commands of the pattern registry.
+var generatedDocs = map[string]commandDoc{
+	"aligned": {
+		Description: `Returns an image padded to the specified width and height,
+with the inner image aligned according to xAlign and yAlign (0.0 to 1.0).
+0.0 means Top/Left, 0.5 means Center, 1.0 means Bottom/Right.
+Optional padding arguments can be provided (following CSS standards):
+1 arg: All sides
+2 args: Vertical, Horizontal
+4 args: Top, Right, Bottom, Left`,
+	},
+	"ambient_occlusion": {
+		Description: `Calculates AO from a height map using a sampling kernel.`,
+		GoUsage: `	// This function is for documentation reference
+	_ = GenerateAmbientOcclusion(image.Rect(0, 0, 200, 200))`,
+	},
+	"and": {
+		Description: `Represents a boolean AND operation.`,
+	},
+	"bayer2x2_dither": {
+		Description: `Example of applying a 2x2 Bayer ordered dither.`,
+		GoUsage: `	// Black and White Palette
+	palette := []color.Color{color.Black, color.White}
+	i := NewBayer2x2Dither(NewGopher(), palette)`,
+	},
+	"bayer4x4_dither": {
+		Description: `Applies ordered dithering using a threshold matrix.`,
+	},
+	"bayer8x8_dither": {
+		Description: `Applies ordered dithering using a threshold matrix.`,
+	},
+	"bayer_dither": {
+		Description: `Applies ordered dithering using a Bayer matrix.`,
+		GoUsage: `	grad := NewLinearGradient(
+		SetStartColor(color.Black),
+		SetEndColor(color.White),
+	)
+	p := NewBayerDither(grad, 4)`,
+	},
+	"bitwise_and": {
+		Description: `Represents a boolean AND operation.`,
+		GoUsage: `	h := NewHorizontalLine(SetLineSize(50), SetSpaceSize(50), SetLineColor(color.RGBA{255, 0, 0, 255}))
+	v := NewVerticalLine(SetLineSize(50), SetSpaceSize(50), SetLineColor(color.RGBA{0, 255, 0, 255}))
+	p := NewBitwiseAnd([]image.Image{h, v})`,
+	},
+	"bitwise_not": {
+		Description: `Represents a boolean NOT operation.`,
+	},
+	"bitwise_or": {
+		Description: `Represents a boolean OR operation.`,
+	},
+	"bitwise_xor": {
+		Description: `Represents a boolean XOR operation.`,
+	},
+	"blend": {
+		Description: `Combines two images using a specified blend mode.`,
+	},
+	"blue_noise": {
+		Description: `Draws a blue noise mask: a grey texture with no low frequencies and no
+spectral peaks, whose pixels below any grey level are spread evenly without
+clumps. It is the void-and-cluster mask of BlueNoiseMask, Size pixels square and
+64 by default, tiled from the origin, so it makes a good threshold map for
+dithering.`,
+		GoUsage: `	p := NewBlueNoise()`,
+	},
+	"blue_noise_dither": {
+		Description: `Creates a blue noise dither pattern.
+Uses a 64x64 void-and-cluster blue noise mask from BlueNoiseMask.`,
+	},
+	"brick": {
+		Description: `Creates a basic brick pattern.`,
+		GoUsage: `	NewBrick(
+		SetBrickSize(50, 20),
+		SetMortarSize(4),
+	)`,
+	},
+	"buffer": {
+		Description: `A pattern that buffers a source image.`,
+		GoUsage: `	// 1. Create a source pattern
+	source := NewSolid(color.RGBA{255, 0, 0, 255})
+	// 2. Create a buffer
+	b := NewBuffer(source, SetExpiry(10*time.Second))
+	// 3. Refresh the buffer explicitly to populate the cache
+	b.Refresh()`,
+	},
+	"center": {
+		Description: `Pads img to width by height, centred on bg.`,
+	},
+	"checker": {
+		Description: `Alternates between two colors in a checkerboard fashion.`,
+		GoUsage: `	i := NewChecker(color.Black, color.White)`,
+	},
+	"chipped_brick": {
+		Description: `Creates a weathered brick wall.`,
+		GoUsage: `	GenerateChippedBrick(image.Rect(0, 0, 300, 300))`,
+	},
+	"chunky_bands": {
+		Description: `Composes chunky pixel bands at a configurable angle.`,
+	},
+	"circle": {
+		Description: `Draws a circle fitting within its bounds.
+It supports a border (LineSize, LineColor, LineImageSource) and a fill (FillColor, FillImageSource).`,
+		GoUsage: `	// Create a simple circle
+	c := NewCircle(SetLineColor(color.Black), SetSpaceColor(color.White))`,
+	},
+	"color_map": {
+		Description: `Maps the luminance of a source pattern to a color gradient (ramp).
+This is useful for creating textures like grass, dirt, clouds, or heatmaps.`,
+		GoUsage: `	// 1. Create a Noise source (Perlin Noise with FBM)
+	noise := NewNoise(
+		NoiseSeed(42), // Fixed seed for reproducible documentation
+		SetNoiseAlgorithm(&PerlinNoise{
+			Seed:        42,
+			Octaves:     4,
+			Persistence: 0.5,
+			Lacunarity:  2.0,
+			Frequency:   0.1,
+		}),
+	)
 
-func RegisterGeneratedCommands(fm dsl.FuncMap) {
-	fm["aligned"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 6 {
-			return nil, fmt.Errorf("aligned requires 6 arguments")
-		}
-		return nil, fmt.Errorf("command aligned has unsupported argument types")
-	}
-	fm["ambient_occlusion"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("ambient_occlusion requires 0 arguments")
-		}
-		if input == nil {
-			return nil, fmt.Errorf("ambient_occlusion requires an input image")
-		}
-		return pattern.NewAmbientOcclusion(input), nil
-	}
-	fm["and"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 1 {
-			return nil, fmt.Errorf("and requires 1 arguments")
-		}
-		return nil, fmt.Errorf("command and has unsupported argument types")
-	}
-	fm["bayer2x2_dither"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 1 {
-			return nil, fmt.Errorf("bayer2x2_dither requires 1 arguments")
-		}
-		return nil, fmt.Errorf("command bayer2x2_dither has unsupported argument types")
-	}
-	fm["bayer4x4_dither"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 1 {
-			return nil, fmt.Errorf("bayer4x4_dither requires 1 arguments")
-		}
-		return nil, fmt.Errorf("command bayer4x4_dither has unsupported argument types")
-	}
-	fm["bayer8x8_dither"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 1 {
-			return nil, fmt.Errorf("bayer8x8_dither requires 1 arguments")
-		}
-		return nil, fmt.Errorf("command bayer8x8_dither has unsupported argument types")
-	}
-	fm["bayer_dither"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 1 {
-			return nil, fmt.Errorf("bayer_dither requires 1 arguments")
-		}
-		if input == nil {
-			return nil, fmt.Errorf("bayer_dither requires an input image")
-		}
-		arg0, err := strconv.Atoi(args[0])
-		if err != nil {
-			return nil, fmt.Errorf("argument 0 must be int: %v", err)
-		}
-		return pattern.NewBayerDither(input, arg0), nil
-	}
-	fm["bitwise_and"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 1 {
-			return nil, fmt.Errorf("bitwise_and requires 1 arguments")
-		}
-		return nil, fmt.Errorf("command bitwise_and has unsupported argument types")
-	}
-	fm["bitwise_not"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("bitwise_not requires 0 arguments")
-		}
-		if input == nil {
-			return nil, fmt.Errorf("bitwise_not requires an input image")
-		}
-		return pattern.NewBitwiseNot(input), nil
-	}
-	fm["bitwise_or"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 1 {
-			return nil, fmt.Errorf("bitwise_or requires 1 arguments")
-		}
-		return nil, fmt.Errorf("command bitwise_or has unsupported argument types")
-	}
-	fm["bitwise_xor"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 1 {
-			return nil, fmt.Errorf("bitwise_xor requires 1 arguments")
-		}
-		return nil, fmt.Errorf("command bitwise_xor has unsupported argument types")
-	}
-	fm["blend"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 1 {
-			return nil, fmt.Errorf("blend requires 1 arguments")
-		}
-		return nil, fmt.Errorf("command blend has unsupported argument types")
-	}
-	fm["blue_noise"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("blue_noise requires 0 arguments")
-		}
-		return pattern.NewBlueNoise(), nil
-	}
-	fm["blue_noise_dither"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 1 {
-			return nil, fmt.Errorf("blue_noise_dither requires 1 arguments")
-		}
-		return nil, fmt.Errorf("command blue_noise_dither has unsupported argument types")
-	}
-	fm["brick"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("brick requires 0 arguments")
-		}
-		return pattern.NewBrick(), nil
-	}
-	fm["buffer"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("buffer requires 0 arguments")
-		}
-		if input == nil {
-			return nil, fmt.Errorf("buffer requires an input image")
-		}
-		return pattern.NewBuffer(input), nil
-	}
-	fm["center"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 3 {
-			return nil, fmt.Errorf("center requires 3 arguments")
-		}
-		return nil, fmt.Errorf("command center has unsupported argument types")
-	}
-	fm["checker"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 2 {
-			return nil, fmt.Errorf("checker requires 2 arguments")
-		}
-		arg0, err := parseColor(args[0])
-		if err != nil {
-			return nil, fmt.Errorf("argument 0 must be color: %v", err)
-		}
-		arg1, err := parseColor(args[1])
-		if err != nil {
-			return nil, fmt.Errorf("argument 1 must be color: %v", err)
-		}
-		return pattern.NewChecker(arg0, arg1), nil
-	}
-	fm["chipped_brick"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("chipped_brick requires 0 arguments")
-		}
-		return pattern.NewChippedBrick(), nil
-	}
-	fm["chunky_bands"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("chunky_bands requires 0 arguments")
-		}
-		return pattern.NewChunkyBands(), nil
-	}
-	fm["circle"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("circle requires 0 arguments")
-		}
-		return pattern.NewCircle(), nil
-	}
-	fm["color_map"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 1 {
-			return nil, fmt.Errorf("color_map requires 1 arguments")
-		}
-		return nil, fmt.Errorf("command color_map has unsupported argument types")
-	}
-	fm["concentric_rings"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 1 {
-			return nil, fmt.Errorf("concentric_rings requires 1 arguments")
-		}
-		return nil, fmt.Errorf("command concentric_rings has unsupported argument types")
-	}
-	fm["concentric_water"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("concentric_water requires 0 arguments")
-		}
-		return pattern.NewConcentricWater(), nil
-	}
-	fm["conic_gradient"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("conic_gradient requires 0 arguments")
-		}
-		return pattern.NewConicGradient(), nil
-	}
-	fm["crop"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 1 {
-			return nil, fmt.Errorf("crop requires 1 arguments")
-		}
-		return nil, fmt.Errorf("command crop has unsupported argument types")
-	}
-	fm["cross_hatch"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("cross_hatch requires 0 arguments")
-		}
-		return pattern.NewCrossHatch(), nil
-	}
-	fm["curvature"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("curvature requires 0 arguments")
-		}
-		if input == nil {
-			return nil, fmt.Errorf("curvature requires an input image")
-		}
-		return pattern.NewCurvature(input), nil
-	}
-	fm["demo_and"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("demo_and requires 0 arguments")
-		}
-		return pattern.NewDemoAnd(), nil
-	}
-	fm["demo_checker"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("demo_checker requires 0 arguments")
-		}
-		return pattern.NewDemoChecker(), nil
-	}
-	fm["demo_chunky_bands"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("demo_chunky_bands requires 0 arguments")
-		}
-		return pattern.NewDemoChunkyBands(), nil
-	}
-	fm["demo_circle"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("demo_circle requires 0 arguments")
-		}
-		return pattern.NewDemoCircle(), nil
-	}
-	fm["demo_cross_hatch"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("demo_cross_hatch requires 0 arguments")
-		}
-		return pattern.NewDemoCrossHatch(), nil
-	}
-	fm["demo_edge_detect"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("demo_edge_detect requires 0 arguments")
-		}
-		return pattern.NewDemoEdgeDetect(), nil
-	}
-	fm["demo_fibonacci"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("demo_fibonacci requires 0 arguments")
-		}
-		return pattern.NewDemoFibonacci(), nil
-	}
-	fm["demo_fine_grid"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("demo_fine_grid requires 0 arguments")
-		}
-		return pattern.NewDemoFineGrid(), nil
-	}
-	fm["demo_glyph_ring"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("demo_glyph_ring requires 0 arguments")
-		}
-		return pattern.NewDemoGlyphRing(), nil
-	}
-	fm["demo_horizontal_line"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("demo_horizontal_line requires 0 arguments")
-		}
-		return pattern.NewDemoHorizontalLine(), nil
-	}
-	fm["demo_not"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("demo_not requires 0 arguments")
-		}
-		return pattern.NewDemoNot(), nil
-	}
-	fm["demo_null"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("demo_null requires 0 arguments")
-		}
-		return pattern.NewDemoNull(), nil
-	}
-	fm["demo_or"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("demo_or requires 0 arguments")
-		}
-		return pattern.NewDemoOr(), nil
-	}
-	fm["demo_polka"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("demo_polka requires 0 arguments")
-		}
-		return pattern.NewDemoPolka(), nil
-	}
-	fm["demo_rect"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("demo_rect requires 0 arguments")
-		}
-		return pattern.NewDemoRect(), nil
-	}
-	fm["demo_simple_zoom"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("demo_simple_zoom requires 0 arguments")
-		}
-		if input == nil {
-			return nil, fmt.Errorf("demo_simple_zoom requires an input image")
-		}
-		return pattern.NewDemoSimpleZoom(input), nil
-	}
-	fm["demo_subpixel_lines"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("demo_subpixel_lines requires 0 arguments")
-		}
-		return pattern.NewDemoSubpixelLines(), nil
-	}
-	fm["demo_thread_bands"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("demo_thread_bands requires 0 arguments")
-		}
-		return pattern.NewDemoThreadBands(), nil
-	}
-	fm["demo_transposed"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("demo_transposed requires 0 arguments")
-		}
-		return pattern.NewDemoTransposed(), nil
-	}
-	fm["demo_vertical_line"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("demo_vertical_line requires 0 arguments")
-		}
-		return pattern.NewDemoVerticalLine(), nil
-	}
-	fm["demo_voronoi"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("demo_voronoi requires 0 arguments")
-		}
-		return pattern.NewDemoVoronoi(), nil
-	}
-	fm["demo_xor"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("demo_xor requires 0 arguments")
-		}
-		return pattern.NewDemoXor(), nil
-	}
-	fm["edge_detect"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("edge_detect requires 0 arguments")
-		}
-		if input == nil {
-			return nil, fmt.Errorf("edge_detect requires an input image")
-		}
-		return pattern.NewEdgeDetect(input), nil
-	}
-	fm["error_diffusion"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 2 {
-			return nil, fmt.Errorf("error_diffusion requires 2 arguments")
-		}
-		return nil, fmt.Errorf("command error_diffusion has unsupported argument types")
-	}
-	fm["fibonacci"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("fibonacci requires 0 arguments")
-		}
-		return pattern.NewFibonacci(), nil
-	}
-	fm["fine_grid"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("fine_grid requires 0 arguments")
-		}
-		return pattern.NewFineGrid(), nil
-	}
-	fm["fog"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("fog requires 0 arguments")
-		}
-		return pattern.NewFog(), nil
-	}
-	fm["globe"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("globe requires 0 arguments")
-		}
-		return pattern.NewGlobe(), nil
-	}
-	fm["glyph_ring"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("glyph_ring requires 0 arguments")
-		}
-		return pattern.NewGlyphRing(), nil
-	}
-	fm["go_logo"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("go_logo requires 0 arguments")
-		}
-		return pattern.NewGoLogo(), nil
-	}
-	fm["gopher"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("gopher requires 0 arguments")
-		}
-		return pattern.NewGopher(), nil
-	}
-	fm["grass_close"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("grass_close requires 0 arguments")
-		}
-		return pattern.NewGrassClose(), nil
-	}
-	fm["grid"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 1 {
-			return nil, fmt.Errorf("grid requires 1 arguments")
-		}
-		return nil, fmt.Errorf("command grid has unsupported argument types")
-	}
-	fm["halftone_dither"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 2 {
-			return nil, fmt.Errorf("halftone_dither requires 2 arguments")
-		}
-		return nil, fmt.Errorf("command halftone_dither has unsupported argument types")
-	}
-	fm["heatmap"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 1 {
-			return nil, fmt.Errorf("heatmap requires 1 arguments")
-		}
-		return nil, fmt.Errorf("command heatmap has unsupported argument types")
-	}
-	fm["hex_grid"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("hex_grid requires 0 arguments")
-		}
-		return pattern.NewHexGrid(), nil
-	}
-	fm["horizontal_line"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("horizontal_line requires 0 arguments")
-		}
-		return pattern.NewHorizontalLine(), nil
-	}
-	fm["knoll_dither"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 2 {
-			return nil, fmt.Errorf("knoll_dither requires 2 arguments")
-		}
-		return nil, fmt.Errorf("command knoll_dither has unsupported argument types")
-	}
-	fm["linear_gradient"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("linear_gradient requires 0 arguments")
-		}
-		return pattern.NewLinearGradient(), nil
-	}
-	fm["maths"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 1 {
-			return nil, fmt.Errorf("maths requires 1 arguments")
-		}
-		return nil, fmt.Errorf("command maths has unsupported argument types")
-	}
-	fm["mirror"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 2 {
-			return nil, fmt.Errorf("mirror requires 2 arguments")
-		}
-		if input == nil {
-			return nil, fmt.Errorf("mirror requires an input image")
-		}
-		arg0, err := strconv.ParseBool(args[0])
-		if err != nil {
-			return nil, fmt.Errorf("argument 0 must be bool: %v", err)
-		}
-		arg1, err := strconv.ParseBool(args[1])
-		if err != nil {
-			return nil, fmt.Errorf("argument 1 must be bool: %v", err)
-		}
-		return pattern.NewMirror(input, arg0, arg1), nil
-	}
-	fm["modulo_stripe"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 1 {
-			return nil, fmt.Errorf("modulo_stripe requires 1 arguments")
-		}
-		return nil, fmt.Errorf("command modulo_stripe has unsupported argument types")
-	}
-	fm["multi_scale_ordered_dither"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 1 {
-			return nil, fmt.Errorf("multi_scale_ordered_dither requires 1 arguments")
-		}
-		return nil, fmt.Errorf("command multi_scale_ordered_dither has unsupported argument types")
-	}
-	fm["noise"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("noise requires 0 arguments")
-		}
-		return pattern.NewNoise(), nil
-	}
-	fm["normal_map"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("normal_map requires 0 arguments")
-		}
-		if input == nil {
-			return nil, fmt.Errorf("normal_map requires an input image")
-		}
-		return pattern.NewNormalMap(input), nil
-	}
-	fm["not"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("not requires 0 arguments")
-		}
-		if input == nil {
-			return nil, fmt.Errorf("not requires an input image")
-		}
-		return pattern.NewNot(input), nil
-	}
-	fm["null"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("null requires 0 arguments")
-		}
-		return pattern.NewNull(), nil
-	}
-	fm["or"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 1 {
-			return nil, fmt.Errorf("or requires 1 arguments")
-		}
-		return nil, fmt.Errorf("command or has unsupported argument types")
-	}
-	fm["ordered_dither"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 4 {
-			return nil, fmt.Errorf("ordered_dither requires 4 arguments")
-		}
-		return nil, fmt.Errorf("command ordered_dither has unsupported argument types")
-	}
-	fm["p_c_b_traces"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("p_c_b_traces requires 0 arguments")
-		}
-		return pattern.NewPCBTraces(), nil
-	}
-	fm["padding"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 1 {
-			return nil, fmt.Errorf("padding requires 1 arguments")
-		}
-		return nil, fmt.Errorf("command padding has unsupported argument types")
-	}
-	fm["painted_planks"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("painted_planks requires 0 arguments")
-		}
-		return pattern.NewPaintedPlanks(), nil
-	}
-	fm["plasma"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("plasma requires 0 arguments")
-		}
-		return pattern.NewPlasma(), nil
-	}
-	fm["polka"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("polka requires 0 arguments")
-		}
-		return pattern.NewPolka(), nil
-	}
-	fm["quantize"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 1 {
-			return nil, fmt.Errorf("quantize requires 1 arguments")
-		}
-		if input == nil {
-			return nil, fmt.Errorf("quantize requires an input image")
-		}
-		arg0, err := strconv.Atoi(args[0])
-		if err != nil {
-			return nil, fmt.Errorf("argument 0 must be int: %v", err)
-		}
-		return pattern.NewQuantize(input, arg0), nil
-	}
-	fm["radial_gradient"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("radial_gradient requires 0 arguments")
-		}
-		return pattern.NewRadialGradient(), nil
-	}
-	fm["random_dither"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 2 {
-			return nil, fmt.Errorf("random_dither requires 2 arguments")
-		}
-		return nil, fmt.Errorf("command random_dither has unsupported argument types")
-	}
-	fm["rect"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("rect requires 0 arguments")
-		}
-		return pattern.NewRect(), nil
-	}
-	fm["rotate"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 1 {
-			return nil, fmt.Errorf("rotate requires 1 arguments")
-		}
-		if input == nil {
-			return nil, fmt.Errorf("rotate requires an input image")
-		}
-		arg0, err := strconv.Atoi(args[0])
-		if err != nil {
-			return nil, fmt.Errorf("argument 0 must be int: %v", err)
-		}
-		return pattern.NewRotate(input, arg0), nil
-	}
-	fm["scale"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 1 {
-			return nil, fmt.Errorf("scale requires 1 arguments")
-		}
-		return nil, fmt.Errorf("command scale has unsupported argument types")
-	}
-	fm["scales"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("scales requires 0 arguments")
-		}
-		return pattern.NewScales(), nil
-	}
-	fm["scatter"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("scatter requires 0 arguments")
-		}
-		return pattern.NewScatter(), nil
-	}
-	fm["screen_tone"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("screen_tone requires 0 arguments")
-		}
-		return pattern.NewScreenTone(), nil
-	}
-	fm["shojo"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("shojo requires 0 arguments")
-		}
-		return pattern.NewShojo(), nil
-	}
-	fm["sierpinski_carpet"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("sierpinski_carpet requires 0 arguments")
-		}
-		return pattern.NewSierpinskiCarpet(), nil
-	}
-	fm["sierpinski_triangle"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("sierpinski_triangle requires 0 arguments")
-		}
-		return pattern.NewSierpinskiTriangle(), nil
-	}
-	fm["simple_zoom"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 1 {
-			return nil, fmt.Errorf("simple_zoom requires 1 arguments")
-		}
-		if input == nil {
-			return nil, fmt.Errorf("simple_zoom requires an input image")
-		}
-		arg0, err := strconv.Atoi(args[0])
-		if err != nil {
-			return nil, fmt.Errorf("argument 0 must be int: %v", err)
-		}
-		return pattern.NewSimpleZoom(input, arg0), nil
-	}
-	fm["speed_lines"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("speed_lines requires 0 arguments")
-		}
-		return pattern.NewSpeedLines(), nil
-	}
-	fm["subpixel_lines"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("subpixel_lines requires 0 arguments")
-		}
-		return pattern.NewSubpixelLines(), nil
-	}
-	fm["text"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 2 {
-			return nil, fmt.Errorf("text requires 2 arguments")
-		}
-		return nil, fmt.Errorf("command text has unsupported argument types")
-	}
-	fm["thread_bands"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("thread_bands requires 0 arguments")
-		}
-		return pattern.NewThreadBands(), nil
-	}
-	fm["tile"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 1 {
-			return nil, fmt.Errorf("tile requires 1 arguments")
-		}
-		return nil, fmt.Errorf("command tile has unsupported argument types")
-	}
-	fm["transposed"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 2 {
-			return nil, fmt.Errorf("transposed requires 2 arguments")
-		}
-		if input == nil {
-			return nil, fmt.Errorf("transposed requires an input image")
-		}
-		arg0, err := strconv.Atoi(args[0])
-		if err != nil {
-			return nil, fmt.Errorf("argument 0 must be int: %v", err)
-		}
-		arg1, err := strconv.Atoi(args[1])
-		if err != nil {
-			return nil, fmt.Errorf("argument 1 must be int: %v", err)
-		}
-		return pattern.NewTransposed(input, arg0, arg1), nil
-	}
-	fm["v_h_s"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("v_h_s requires 0 arguments")
-		}
-		if input == nil {
-			return nil, fmt.Errorf("v_h_s requires an input image")
-		}
-		return pattern.NewVHS(input), nil
-	}
-	fm["vertical_line"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("vertical_line requires 0 arguments")
-		}
-		return pattern.NewVerticalLine(), nil
-	}
-	fm["voronoi"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 2 {
-			return nil, fmt.Errorf("voronoi requires 2 arguments")
-		}
-		return nil, fmt.Errorf("command voronoi has unsupported argument types")
-	}
-	fm["voronoi_tiles"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 5 {
-			return nil, fmt.Errorf("voronoi_tiles requires 5 arguments")
-		}
-		return nil, fmt.Errorf("command voronoi_tiles has unsupported argument types")
-	}
-	fm["warp"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("warp requires 0 arguments")
-		}
-		if input == nil {
-			return nil, fmt.Errorf("warp requires an input image")
-		}
-		return pattern.NewWarp(input), nil
-	}
-	fm["wind_ridges"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("wind_ridges requires 0 arguments")
-		}
-		return pattern.NewWindRidges(), nil
-	}
-	fm["worley_noise"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("worley_noise requires 0 arguments")
-		}
-		return pattern.NewWorleyNoise(), nil
-	}
-	fm["worley_tiles"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("worley_tiles requires 0 arguments")
-		}
-		return pattern.NewWorleyTiles(), nil
-	}
-	fm["xor"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 1 {
-			return nil, fmt.Errorf("xor requires 1 arguments")
-		}
-		return nil, fmt.Errorf("command xor has unsupported argument types")
-	}
-	fm["xor_pattern"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 0 {
-			return nil, fmt.Errorf("xor_pattern requires 0 arguments")
-		}
-		return pattern.NewXorPattern(), nil
-	}
-	fm["yliluoma1_dither"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 2 {
-			return nil, fmt.Errorf("yliluoma1_dither requires 2 arguments")
-		}
-		return nil, fmt.Errorf("command yliluoma1_dither has unsupported argument types")
-	}
-	fm["yliluoma2_dither"] = func(args []string, input image.Image) (image.Image, error) {
-		if len(args) < 2 {
-			return nil, fmt.Errorf("yliluoma2_dither requires 2 arguments")
-		}
-		return nil, fmt.Errorf("command yliluoma2_dither has unsupported argument types")
+	// 2. Map the noise to a "Grass" color ramp
+	grass := NewColorMap(noise,
+		ColorStop{Position: 0.0, Color: color.RGBA{0, 50, 0, 255}},     // Deep shadow green
+		ColorStop{Position: 0.4, Color: color.RGBA{10, 100, 10, 255}},  // Mid green
+		ColorStop{Position: 0.7, Color: color.RGBA{50, 150, 30, 255}},  // Light green
+		ColorStop{Position: 1.0, Color: color.RGBA{100, 140, 60, 255}}, // Dried tip
+	)`,
+	},
+	"concentric_rings": {
+		Description: `Generates concentric rings using sqrt(x^2 + y^2) % n.`,
+		GoUsage: `	p := NewConcentricRings([]color.Color{
+		color.Black,
+		color.White,
+		color.RGBA{255, 0, 0, 255},
+	})`,
+	},
+	"concentric_water": {
+		Description: `Demonstrates concentric distance-field ripples with
+sine-driven heights that tint and bend the normals of the surface.`,
+		GoUsage: `	NewConcentricWater(
+		ConcentricWaterRingSpacing(14.0),
+		ConcentricWaterAmplitude(1.1),
+		ConcentricWaterAmplitudeFalloff(0.018),
+		ConcentricWaterBaseTint(color.RGBA{24, 104, 168, 255}),
+		ConcentricWaterNormalStrength(4.0),
+	)`,
+	},
+	"conic_gradient": {
+		Description: `Represents a conic (angular) color gradient.`,
+		GoUsage: `	// Conic Gradient
+	NewConicGradient(
+		SetStartColor(color.RGBA{255, 0, 255, 255}),
+		SetEndColor(color.RGBA{0, 255, 255, 255}),
+	)`,
+	},
+	"crop": {
+		Description: `Shows its source through a rectangular window, transparent outside it.`,
+	},
+	"cross_hatch": {
+		Description: `Draws layered diagonal hatch lines.`,
+	},
+	"curl_noise": {
+		Description: `Generates a swirling, divergence free vector field from the curl of noise, drawn
+one component at a time.`,
+		GoUsage: `	// The X component; SetCurlComponent(CurlY) draws the other
+	i := NewCurlNoise(SetSeed(3), SetFrequency(0.02))`,
+	},
+	"curvature": {
+		Description: `Calculates curvature (convex/concave) from a height map.`,
+		GoUsage: `	// This function is for documentation reference
+	_ = GenerateCurvature(image.Rect(0, 0, 200, 200))`,
+	},
+	"demo_and": {
+		Description: `Represents a boolean AND operation.`,
+	},
+	"demo_checker": {
+		Description: `Creates a new Checker with the given colors and square size.`,
+	},
+	"demo_chunky_bands": {
+		Description: `Composes chunky pixel bands at a configurable angle.`,
+	},
+	"demo_circle": {
+		Description: `Draws a circle fitting within its bounds.
+It supports a border (LineSize, LineColor, LineImageSource) and a fill (FillColor, FillImageSource).`,
+	},
+	"demo_cross_hatch": {
+		Description: `Draws layered diagonal hatch lines.`,
+	},
+	"demo_edge_detect": {
+		Description: `Creates a new EdgeDetect pattern from an existing image.`,
+	},
+	"demo_fibonacci": {
+		Description: `Draws a Fibonacci (Golden) spiral.
+It uses the logarithmic spiral equation r = a * e^(b * theta) with b = 2*ln(Phi)/pi.
+It supports LineSize, LineColor, and SpaceColor.`,
+	},
+	"demo_fine_grid": {
+		Description: `Builds a grid with glow and chromatic aberration.`,
+	},
+	"demo_glyph_ring": {
+		Description: `Constructs a GlyphRing with optional configuration.`,
+	},
+	"demo_horizontal_line": {
+		Description: `Draws horizontal lines.
+Animated with SetTime, the lines move down one period a second.`,
+	},
+	"demo_not": {
+		Description: `Represents a boolean NOT operation.`,
+	},
+	"demo_null": {
+		Description: `Returns a transparent color for all pixels.`,
+	},
+	"demo_or": {
+		Description: `Represents a boolean OR operation.`,
+	},
+	"demo_polka": {
+		Description: `Displays a grid of circles (polka dots).`,
+	},
+	"demo_rect": {
+		Description: `Creates a new Rect pattern with the given options.`,
+	},
+	"demo_simple_zoom": {
+		Description: `Creates a new SimpleZoom with the given image and zoom factor.`,
+	},
+	"demo_subpixel_lines": {
+		Description: `Renders alternating dark/light horizontal bands with subtle RGB
+channel offsets and a vignette falloff.`,
+	},
+	"demo_thread_bands": {
+		Description: `Creates a thread weave pattern with sensible defaults.`,
+	},
+	"demo_transposed": {
+		Description: `Creates a new Transposed from an existing image.`,
+	},
+	"demo_vertical_line": {
+		Description: `Draws vertical lines.
+Animated with SetTime, the lines move right one period a second.`,
+	},
+	"demo_voronoi": {
+		Description: `Generates Voronoi cells based on a set of points and colors.
+
+Each cell is filled with its colour, used in turn, or with its image from Sources,
+also used in turn, which take precedence. Distances are measured with Metric, and
+Weights, when set, make a power diagram: each site claims the points where d² − w²
+is least, w being its weight in pixels, so heavier sites claim larger cells. Sites
+without a weight weigh nothing.
+
+Where BorderWidth is positive the cells are outlined in BorderColor. The distance
+outputs are drawn in units of the mean spacing of the sites over the bounds, so
+they are roughly in [0, 1]; borders are exact for the Euclidean metric and
+estimated, as half F2 − F1, for the others.
+
+The sites are indexed in a grid the first time the pattern is drawn, so Points and
+Weights should not change after that.`,
+	},
+	"demo_xor": {
+		Description: `Represents a boolean XOR operation.`,
+	},
+	"edge_detect": {
+		Description: `Applies Sobel edge detection to an input image.`,
+		GoUsage: `	i := NewDemoEdgeDetect()`,
+	},
+	"error_diffusion": {
+		Description: `Applies error diffusion dithering to an image.`,
+		GoUsage: `	// Standard example
+	i := NewDemoErrorDiffusion()`,
+	},
+	"fibonacci": {
+		Description: `Draws a Fibonacci (Golden) spiral.
+It uses the logarithmic spiral equation r = a * e^(b * theta) with b = 2*ln(Phi)/pi.
+It supports LineSize, LineColor, and SpaceColor.`,
+		GoUsage: `	// Create a simple Fibonacci spiral
+	c := NewFibonacci(SetLineColor(color.Black), SetSpaceColor(color.White))`,
+	},
+	"fine_grid": {
+		Description: `Renders a neon grid with glow and saves it to fine_grid.png.`,
+		GoUsage: `	img := NewFineGrid(
+		SetBounds(image.Rect(0, 0, 640, 640)),
+		SetFineGridCellSize(12),
+		SetFineGridGlowRadius(3.5),
+		SetFineGridHue(205),
+		SetFineGridAberration(1),
+		SetFineGridGlowStrength(0.9),
+		SetFineGridLineStrength(1.4),
+		SetFineGridBackgroundFade(0.0),
+	)`,
+	},
+	"fog": {
+		Description: `Renders soft Perlin/fBm fog with a radial falloff so the center stays clearer.`,
+		GoUsage: `	NewFog(
+		SetDensity(0.85),
+		SetFalloffCurve(1.8),
+		SetFillColor(color.RGBA{185, 205, 230, 255}),
+	)`,
+	},
+	"globe": {
+		Description: `Renders a 3D sphere projected onto 2D.
+It supports configurable latitude and longitude grid lines,
+3D rotation via Angle (Y-axis) and Tilt (X-axis/Z-axis),
+and texture mapping via FillImageSource with UV coordinates.`,
+		GoUsage: `	ExampleNewGlobe_Projected()`,
+	},
+	"glyph_ring": {
+		Description: `Constructs a GlyphRing with optional configuration.`,
+		GoUsage: `	i := NewGlyphRing()`,
+	},
+	"go_logo": {
+		Description: `Returns an image of the Go Logo (or a Gopher related image).`,
+	},
+	"gopher": {
+		Description: `A static image of the Go Gopher.`,
+		GoUsage: `	i := NewGopher()`,
+	},
+	"grass_close": {
+		Description: `Demonstrates a procedural grass texture using the GrassClose pattern composed with Noise.`,
+		GoUsage: `	// 1. Background: Dirt
+	dirt := NewColorMap(
+		NewNoise(SetFrequency(0.05), NoiseSeed(1)),
+		ColorStop{0.0, color.RGBA{40, 30, 20, 255}},
+		ColorStop{1.0, color.RGBA{80, 60, 40, 255}},
+	)
+
+	// 2. Wind map (Perlin noise)
+	wind := NewNoise(
+		SetFrequency(0.01),
+		NoiseSeed(2),
+		SetNoiseAlgorithm(&PerlinNoise{Seed: 2, Octaves: 2, Persistence: 0.5}),
+	)
+
+	// 3. Density map (Worley noise for clumping)
+	density := NewWorleyNoise(
+		SetFrequency(0.02),
+		SetSeed(3),
+	)
+
+	// 4. Grass Layer
+	grass := NewGrassClose(
+		SetBladeHeight(35),
+		SetBladeWidth(5),
+		SetFillColor(color.RGBA{20, 160, 30, 255}),
+		SetWindSource(wind),
+		SetDensitySource(density),
+		// Background source
+		func(p any) {
+			if g, ok := p.(*GrassClose); ok {
+				g.Source = dirt
+			}
+		},
+	)`,
+	},
+	"grid": {
+		Description: `Lays images out in rows and columns of cells.`,
+		GoUsage: `	// Example 1: Simple 2x2 grid with Gophers
+	// Shrink the Gopher so it fits better
+	gopher := NewScale(NewGopher(), ScaleToRatio(0.25))
+
+	args := []any{
+		Row(Cell(gopher), Cell(gopher)),
+		Row(Cell(gopher), Cell(gopher)),
+	}
+	for _, op := range ops {
+		args = append(args, op)
+	}
+	NewGrid(args...)`,
+	},
+	"halftone_dither": {
+		Description: `Creates a halftone dither effect using a clustered dot matrix.
+size determines the grid size of the dots (e.g. 8x8).`,
+	},
+	"hex_grid": {
+		Description: `HexGrid example: alternating palette across axial coordinates with a subtle bevel.`,
+		GoUsage: `	img := GenerateHexGrid(image.Rect(0, 0, 255, 255))`,
+	},
+	"horizontal_line": {
+		Description: `Draws horizontal lines.
+Animated with SetTime, the lines move down one period a second.`,
+		GoUsage: `	i := NewHorizontalLine(
+		SetLineSize(5),
+		SetSpaceSize(5),
+		SetLineColor(color.RGBA{255, 0, 0, 255}),
+		SetSpaceColor(color.White),
+	)`,
+	},
+	"knoll_dither": {
+		Description: `Implements Thomas Knoll's pattern dithering (Photoshop).`,
+		GoUsage: `	img := NewGopher()
+	NewKnollDither(img, Windows16, 8)`,
+	},
+	"linear_gradient": {
+		Description: `Represents a linear color gradient.`,
+		GoUsage: `	// Linear Gradient (Horizontal)
+	NewLinearGradient(
+		SetStartColor(color.RGBA{255, 0, 0, 255}),
+		SetEndColor(color.RGBA{0, 0, 255, 255}),
+	)`,
+	},
+	"low_poly": {
+		Description: `Draws an image as faceted triangles between scattered points, for low poly
+backgrounds.`,
+		GoUsage: `	noise := NewNoise(SetNoiseAlgorithm(&PerlinNoise{Seed: 11, Frequency: 0.012, Octaves: 3, Persistence: 0.5}))
+	sky := NewColorMap(noise,
+		ColorStop{Position: 0.2, Color: color.RGBA{40, 30, 90, 255}},
+		ColorStop{Position: 0.45, Color: color.RGBA{170, 60, 120, 255}},
+		ColorStop{Position: 0.65, Color: color.RGBA{250, 140, 80, 255}},
+		ColorStop{Position: 0.85, Color: color.RGBA{255, 220, 140, 255}},
+	)
+	// Points spread evenly, about 24 pixels apart, make even facets
+	points := PoissonDiskPoints(sky.Bounds(), 24, 7, nil)
+	i := NewLowPoly(sky, points)`,
+	},
+	"mirror": {
+		Description: `Mirrors the input pattern horizontally or vertically.`,
+		GoUsage: `	i := NewMirror(NewDemoMirrorInput(image.Rect(0, 0, 40, 40)), true, false)`,
+	},
+	"modulo_stripe": {
+		Description: `Generates a pattern based on (x + y) % n.`,
+		GoUsage: `	p := NewModuloStripe([]color.Color{
+		color.RGBA{255, 0, 0, 255},
+		color.RGBA{0, 255, 0, 255},
+		color.RGBA{0, 0, 255, 255},
+	})`,
+	},
+	"multi_scale_ordered_dither": {
+		Description: `Blends between two matrices based on local variance.`,
+	},
+	"noise": {
+		Description: `Generates random noise using various algorithms (Crypto, Hash, Perlin, OpenSimplex, Value).`,
+		GoUsage: `	// Create a noise pattern with a seeded algorithm (Hash) for stability
+	i := NewNoise(NoiseSeed(1))`,
+	},
+	"normal_map": {
+		Description: `Creates a new NormalMap from a source image.
+Default Strength is 1.0.`,
+		GoUsage: `	// Create a height map using Perlin noise
+	noise := NewNoise(
+		NoiseSeed(123),
+		SetNoiseAlgorithm(&PerlinNoise{
+			Seed:        123,
+			Octaves:     4,
+			Persistence: 0.5,
+			Lacunarity:  2.0,
+			Frequency:   0.05,
+		}),
+	)
+	NewNormalMap(noise, NormalMapStrength(5.0))`,
+	},
+	"not": {
+		Description: `Represents a boolean NOT operation.`,
+	},
+	"null": {
+		Description: `Returns a transparent color for all pixels.`,
+		GoUsage: `	i := NewNull()`,
+	},
+	"or": {
+		Description: `Represents a boolean OR operation.`,
+	},
+	"ordered_dither": {
+		Description: `Applies ordered dithering using a threshold matrix.`,
+		GoUsage: `	i := NewDemoOrderedDither()`,
+	},
+	"padding": {
+		Description: `Surrounds an image with margins filled from a background pattern.`,
+		GoUsage: `	gopher := NewScale(NewGopher(), ScaleToRatio(0.5))
+	NewPadding(gopher, PaddingMargin(20))`,
+	},
+	"painted_planks": {
+		Description: `Demonstrates segmented planks with grain noise per board
+and a chipped paint overlay.`,
+		GoUsage: `	NewPaintedPlanks(
+		SetPlankBaseWidth(72),
+		SetPlankWidthVariance(0.32),
+		SetGrainIntensity(0.75),
+		SetPaintWear(0.42),
+		SetPaintColor(color.RGBA{177, 202, 214, 255}),
+	)`,
+	},
+	"pcb_traces": {
+		Description: `Returns a sample PCB trace layout with default options.`,
+		GoUsage: `	GeneratePCBTraces(image.Rect(0, 0, 192, 192))`,
+	},
+	"plasma": {
+		Description: `Generates a plasma noise texture using Diamond-Square algorithm.
+It supports RGB (independent channels) or Grayscale.
+Animated with SetTime, it colour cycles: each value runs up to 1 and back down
+again once a second.`,
+		GoUsage: `	p := NewPlasma()`,
+	},
+	"polka": {
+		Description: `A pattern of dots (circles) arranged in a grid.`,
+		GoUsage: `	i := NewPolka(
+		SetRadius(10),
+		SetSpacing(40),
+		SetFillColor(color.Black),
+		SetSpaceColor(color.White),
+	)`,
+	},
+	"quantize": {
+		Description: `Example of quantizing the colors of an image (Posterization).
+This example reduces the Gopher image to 4 levels per channel.`,
+		GoUsage: `	i := NewQuantize(NewGopher(), 4)`,
+	},
+	"radial_gradient": {
+		Description: `Represents a radial color gradient.`,
+		GoUsage: `	// Radial Gradient
+	NewRadialGradient(
+		SetStartColor(color.RGBA{255, 0, 0, 255}),
+		SetEndColor(color.RGBA{0, 0, 255, 255}),
+	)`,
+	},
+	"random_dither": {
+		Description: `Applies random noise dithering.`,
+	},
+	"rect": {
+		Description: `A pattern that draws a filled rectangle.`,
+		GoUsage: `	// A simple black rectangle (default)
+	i := NewRect()`,
+	},
+	"rotate": {
+		Description: `Rotates the input pattern by 90, 180, or 270 degrees.`,
+		GoUsage: `	i := NewRotate(NewDemoRotateInput(image.Rect(0, 0, 40, 60)), 90)`,
+	},
+	"scalar_image": {
+		Description: `Creates an image from a ScalarField.`,
+	},
+	"scale": {
+		Description: `Creates a new scaled image.
+Note: This eagerly computes the scaled image because advanced interpolation requires neighborhood access.`,
+	},
+	"scales": {
+		Description: `Demonstrates using the Scales pattern to create Amazonian fish scales.`,
+		GoUsage: `	// Use the explicit Scales pattern for proper overlapping geometry.
+	// Radius 40, SpacingX 40 (touching horizontally), SpacingY 20 (half-overlap vertically).
+	pattern := NewScales(
+		SetScaleRadius(40),
+		SetScaleXSpacing(40),
+		SetScaleYSpacing(25),
+	)
+
+	// The Scales pattern returns a heightmap (0 edge, 1 center).
+	// We want to map this to look like a tough fish scale.
+	// Center: Shiny/Metallic
+	// Gradient towards edge.
+	// Edge: Dark border.
+
+	scales := NewColorMap(pattern,
+		ColorStop{Position: 0.0, Color: color.RGBA{10, 10, 10, 255}},    // Deep edge (overlap shadow)
+		ColorStop{Position: 0.2, Color: color.RGBA{40, 40, 30, 255}},    // Rim
+		ColorStop{Position: 0.5, Color: color.RGBA{100, 100, 80, 255}},  // Body
+		ColorStop{Position: 0.8, Color: color.RGBA{160, 150, 120, 255}}, // Highlight start
+		ColorStop{Position: 1.0, Color: color.RGBA{200, 190, 160, 255}}, // Peak Highlight
+	)`,
+	},
+	"scatter": {
+		Description: `Places generated items in a grid with random offsets.
+It supports overlapping items by sorting them by a Z-index derived from the hash.`,
+	},
+	"screen_tone": {
+		Description: `A halftone dot matrix pattern with adjustable density (Spacing) and angle.`,
+		GoUsage: `	i := NewScreenTone(
+		SetRadius(3),
+		SetSpacing(10),
+		SetAngle(45),
+		SetFillColor(color.Black),
+		SetSpaceColor(color.White),
+	)`,
+	},
+	"shojo": {
+		Description: `Generates scattered starbursts with glow halos ("Shōjo Sparkles").`,
+		GoUsage: `	i := NewShojo()`,
+	},
+	"sierpinski_carpet": {
+		Description: `Generates a Sierpinski Carpet fractal.`,
+	},
+	"sierpinski_triangle": {
+		Description: `Generates a Sierpinski Triangle fractal (right-angled variant using Pascal's Triangle modulo 2).`,
+	},
+	"simple_zoom": {
+		Description: `Scales an input pattern by a factor.`,
+		GoUsage: `	i := NewSimpleZoom(NewChecker(color.Black, color.White), 2)`,
+	},
+	"speed_lines": {
+		Description: `Basic radial speed lines.`,
+		GoUsage: `	i := NewSpeedLines(
+		SetDensity(150),
+		SetMinRadius(30),
+		SetMaxRadius(80),
+	)`,
+	},
+	"streamlines": {
+		Description: `Draws the flow of a vector field by smearing an image along its streamlines.
+Over white noise, as here, this is line integral convolution.`,
+		GoUsage: `	noise := NewNoise(NoiseSeed(1))
+	flow := NewCurlNoise(SetSeed(3), SetFrequency(0.02))
+	i := NewStreamlines(noise, flow, SetStreamlineSteps(12))`,
+	},
+	"subpixel_lines": {
+		Description: `Subpixel lines with per-channel offset and vignette.`,
+		GoUsage: `	i := NewSubpixelLines(
+		SetLineThickness(2),
+		SetOffsetStrength(0.65),
+		SetVignetteRadius(0.82),
+	)`,
+	},
+	"supersample": {
+		Description: `Creates a Supersample pattern taking n×n samples per pixel.
+The bounds default to those of the source.`,
+	},
+	"text": {
+		Description: `Renders s in the Go font, sized to fit the text.`,
+	},
+	"thread_bands": {
+		Description: `Creates a thread weave pattern with sensible defaults.`,
+	},
+	"tile": {
+		Description: `A filter set by rect (Bounds of the tiled image).`,
+		GoUsage: `	gopher := NewScale(NewGopher(), ScaleToRatio(0.25))
+	NewTile(gopher, image.Rect(0, 0, 200, 200))`,
+	},
+	"transposed": {
+		Description: `Transposes the coordinates of an input pattern.`,
+		GoUsage: `	i := NewTransposed(NewDemoNull(), 10, 10)`,
+	},
+	"vertical_line": {
+		Description: `Draws vertical lines.
+Animated with SetTime, the lines move right one period a second.`,
+		GoUsage: `	i := NewVerticalLine(
+		SetLineSize(5),
+		SetSpaceSize(5),
+		SetLineColor(color.RGBA{0, 0, 255, 255}),
+		SetSpaceColor(color.White),
+	)`,
+	},
+	"vhs": {
+		Description: `Demonstrates the VHS scanline, color shift, and noise effect.`,
+	},
+	"voronoi": {
+		Description: `Generates Voronoi cells.`,
+		GoUsage: `	// Define some points and colors
+	points := []image.Point{
+		{50, 50}, {200, 50}, {125, 125}, {50, 200}, {200, 200},
+	}
+	colors := []color.Color{
+		color.RGBA{255, 100, 100, 255},
+		color.RGBA{100, 255, 100, 255},
+		color.RGBA{100, 100, 255, 255},
+		color.RGBA{255, 255, 100, 255},
+		color.RGBA{100, 255, 255, 255},
 	}
+
+	i := NewVoronoi(points, colors)`,
+	},
+	"voronoi_tiles": {
+		Description: `Uses Voronoi cells to define tiles, raises the centers, darkens the gaps, and sprinkles dust noise.`,
+		GoUsage: `	img := NewVoronoiTiles(image.Rect(0, 0, 255, 255), defaultVoronoiTileCellSize, defaultVoronoiTileGapWidth, defaultVoronoiTileHeightImpact, 2024)`,
+	},
+	"warp": {
+		Description: `Distorts the coordinates of the Source image using the Distortion image.
+It maps the color intensity of the Distortion image to a coordinate offset.`,
+		GoUsage: `	// Standard demo: Grid warped by noise
+	// We want a visual that clearly shows the warping effect.
+	// A checkerboard is good.
+
+	checker := NewChecker(
+		color.RGBA{200, 200, 200, 255},
+		color.RGBA{50, 50, 50, 255},
+	)
+
+	// Distortion noise
+	noise := NewNoise(NoiseSeed(99), SetNoiseAlgorithm(&PerlinNoise{
+		Frequency: 0.03,
+		Octaves: 2,
+	}))
+
+	// Apply Warp
+	warped := NewWarp(checker,
+		WarpDistortion(noise),
+		WarpScale(10.0),
+	)`,
+	},
+	"wind_ridges": {
+		Description: `Writes a wind-swept noise PNG showcasing parameterized streaks.`,
+		GoUsage: `	img := GenerateWindRidges(image.Rect(0, 0, 200, 200))`,
+	},
+	"worley_noise": {
+		Description: `Generates Worley (cellular) noise.`,
+		GoUsage: `	// Standard F1 Euclidean Worley Noise
+	i := NewWorleyNoise(
+		SetFrequency(0.05),
+		SetSeed(1),
+	)`,
+	},
+	"worley_tiles": {
+		Description: `Tiles Worley/Voronoi stones with rounded edges and mortar.
+Parameters:
+- stone size (pixels): SetTileStoneSize
+- gap width (0-1): SetTileGapWidth
+- color palette spread (0-1): SetTilePaletteSpread`,
+		GoUsage: `	baseTile := NewWorleyTiles(
+		SetBounds(image.Rect(0, 0, 160, 160)),
+		SetTileStoneSize(52),
+		SetTileGapWidth(0.1),
+		SetTilePaletteSpread(0.18),
+		SetTilePalette(
+			color.RGBA{128, 116, 106, 255},
+			color.RGBA{146, 132, 118, 255},
+			color.RGBA{112, 102, 96, 255},
+		),
+		WithSeed(2024),
+	)
+	NewTile(baseTile, image.Rect(0, 0, 320, 320))`,
+	},
+	"xor": {
+		Description: `Represents a boolean XOR operation.`,
+	},
+	"xor_pattern": {
+		Description: `Generates an XOR texture pattern.`,
+	},
+	"yliluoma1_dither": {
+		Description: `Implements Yliluoma's ordered dithering algorithm 1.
+It mixes two colors from the palette to approximate the input color.`,
+		GoUsage: `	img := NewGopher()
+	NewYliluoma1Dither(img, Windows16, 8)`,
+	},
+	"yliluoma2_dither": {
+		Description: `Implements Yliluoma's ordered dithering algorithm 2.
+It builds a candidate list of colors that average to the input color.`,
+		GoUsage: `	img := NewGopher()
+	NewYliluoma2Dither(img, Windows16, 8)`,
+	},
 }
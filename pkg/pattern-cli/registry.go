@@ -0,0 +1,90 @@
+package pattern_cli
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/arran4/go-pattern"
+	"github.com/arran4/go-pattern/dsl"
+)
+
+// RegisterPatternCommands adds a command for every type in the pattern registry.
+//
+// Named arguments set parameters, input slots and then options by name (see
+// pattern.SetOption). Positional values fill
+// the remaining parameters in order, and the pipeline input followed by positional
+// images ($name references and sub-pipelines) fill the remaining input slots in
+// order, a variadic slot taking all that are left. A type with no input slots
+// ignores the pipeline input.
+func RegisterPatternCommands(fm dsl.FuncMap) {
+	for _, t := range pattern.Patterns() {
+		fm[t.Name] = patternCommand(t)
+	}
+}
+
+func patternCommand(t *pattern.PatternType) dsl.CommandFunc {
+	return func(args dsl.Args, input image.Image) (image.Image, error) {
+		var a pattern.Args
+		var values []string
+		var options dsl.Args
+		var images []image.Image
+		if input != nil && len(t.Inputs) > 0 {
+			// A type that takes no images starts a new pipeline.
+			images = append(images, input)
+		}
+		for _, arg := range args {
+			switch {
+			case arg.Name == "" && arg.IsImage():
+				images = append(images, arg.Image)
+			case arg.Name == "":
+				values = append(values, arg.Value)
+			case t.Input(arg.Name) != nil:
+				if !arg.IsImage() {
+					return nil, fmt.Errorf("%s: input %q takes an image, not %q", t.Name, arg.Name, arg.Value)
+				}
+				a.SetInput(arg.Name, arg.Image)
+			case t.Param(arg.Name) == nil:
+				options = append(options, arg)
+			case arg.IsImage():
+				return nil, fmt.Errorf("%s: parameter %q takes a value, not an image", t.Name, arg.Name)
+			default:
+				a.Set(arg.Name, arg.Value)
+			}
+		}
+
+		params := t.Params
+		for _, v := range values {
+			for len(params) > 0 && a.Values[params[0].Name] != nil {
+				params = params[1:]
+			}
+			if len(params) == 0 {
+				return nil, fmt.Errorf("%s takes at most %d arguments", t.Name, len(t.Params))
+			}
+			a.Set(params[0].Name, v)
+			params = params[1:]
+		}
+
+		inputs := t.Inputs
+		for len(images) > 0 {
+			for len(inputs) > 0 && len(a.Inputs[inputs[0].Name]) > 0 {
+				inputs = inputs[1:]
+			}
+			if len(inputs) == 0 {
+				return nil, fmt.Errorf("%s takes at most %d input images", t.Name, len(t.Inputs))
+			}
+			if inputs[0].Variadic {
+				a.SetInput(inputs[0].Name, images...)
+				images = nil
+			} else {
+				a.SetInput(inputs[0].Name, images[0])
+				images = images[1:]
+			}
+			inputs = inputs[1:]
+		}
+		img, err := t.Build(a)
+		if err != nil {
+			return nil, err
+		}
+		return setOptions(img, options)
+	}
+}
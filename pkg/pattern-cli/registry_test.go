@@ -0,0 +1,79 @@
+package pattern_cli
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/arran4/go-pattern/dsl"
+)
+
+// runScript runs a script with the CLI's commands.
+func runScript(t *testing.T, script string) (image.Image, error) {
+	t.Helper()
+	fm := make(dsl.FuncMap)
+	registerCommands(fm)
+	s, err := dsl.ParseScript(script)
+	if err != nil {
+		t.Fatalf("ParseScript(%q) failed: %v", script, err)
+	}
+	return s.Execute(fm, nil)
+}
+
+func TestPatternCommandNamedPalette(t *testing.T) {
+	img, err := runScript(t, "checker | bayer2x2_dither bw")
+	if err != nil {
+		t.Fatalf("bayer2x2_dither bw failed: %v", err)
+	}
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Min.Y+16; y++ {
+		for x := b.Min.X; x < b.Min.X+16; x++ {
+			r, g, bl, _ := img.At(x, y).RGBA()
+			if c := (color.RGBA64{uint16(r), uint16(g), uint16(bl), 0xffff}); c != (color.RGBA64{0, 0, 0, 0xffff}) && c != (color.RGBA64{0xffff, 0xffff, 0xffff, 0xffff}) {
+				t.Fatalf("pixel (%d, %d) is %v, want black or white", x, y, c)
+			}
+		}
+	}
+}
+
+func TestPatternCommandIgnoresInputWithoutSlots(t *testing.T) {
+	img, err := runScript(t, "checker | polka")
+	if err != nil {
+		t.Fatalf("checker | polka failed: %v", err)
+	}
+	want, err := runScript(t, "polka")
+	if err != nil {
+		t.Fatalf("polka failed: %v", err)
+	}
+	if img.Bounds() != want.Bounds() {
+		t.Fatalf("bounds %v, want %v", img.Bounds(), want.Bounds())
+	}
+	b := want.Bounds()
+	for y := b.Min.Y; y < b.Min.Y+16; y++ {
+		for x := b.Min.X; x < b.Min.X+16; x++ {
+			if img.At(x, y) != want.At(x, y) {
+				t.Fatalf("pixel (%d, %d) is %v, want %v", x, y, img.At(x, y), want.At(x, y))
+			}
+		}
+	}
+}
+
+func TestPatternCommandLayout(t *testing.T) {
+	for script, want := range map[string]image.Rectangle{
+		"gopher | scale width=50 | center width=80 height=70":                  image.Rect(0, 0, 80, 70),
+		"checker bounds=0,0,10,10 | padding top=1 right=2 bottom=3 left=4":     image.Rect(0, 0, 16, 14),
+		"checker bounds=0,0,10,10 | grid (checker bounds=0,0,10,10) columns=1": image.Rect(0, 0, 10, 20),
+	} {
+		img, err := runScript(t, script)
+		if err != nil {
+			t.Errorf("%s failed: %v", script, err)
+			continue
+		}
+		if img.Bounds() != want {
+			t.Errorf("%s: bounds %v, want %v", script, img.Bounds(), want)
+		}
+	}
+	if _, err := runScript(t, "checker | ordered_dither matrix=0,0.5 dim=2"); err == nil {
+		t.Error("Expected an error for a matrix that does not fit its dim")
+	}
+}
@@ -0,0 +1,130 @@
+package pattern_cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"reflect"
+
+	"github.com/arran4/go-pattern"
+	"github.com/arran4/go-pattern/dsl"
+)
+
+// Render is a subcommand `pattern-cli render`
+//
+// It renders a DSL pipeline, or the graph file at graph ("-" reads standard
+// input), to out ("-" writes standard output). width, height and bounds, given as
+// "minX,minY,maxX,maxY", set the rendered region and are applied to every image in
+// the chain; otherwise the bounds of the result are used. format defaults to the
+// extension of out, or png, and quality is the JPEG quality. The terminal formats
+// ansi, sixel and kitty write standard output when out is not given.
+func Render(pipeline, graph string, width, height int, bounds, format string, quality int, out string) error {
+	if (pipeline == "") == (graph == "") {
+		return errors.New("render takes either a pipeline or a graph file")
+	}
+	if format == "" {
+		if format = FormatOf(out); format == "" {
+			format = "png"
+		}
+	}
+	format, err := ParseFormat(format)
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		if !IsTerminalFormat(format) {
+			return errors.New("render requires an output file, or - for standard output")
+		}
+		out = "-"
+	}
+
+	img, err := build(pipeline, graph)
+	if err != nil {
+		return err
+	}
+	r, err := renderBounds(img.Bounds(), width, height, bounds)
+	if err != nil {
+		return err
+	}
+	if r != img.Bounds() {
+		pattern.SetChainBounds(img, r)
+	}
+	rendered, err := pattern.Render(context.Background(), img, pattern.SetBounds(r))
+	if err != nil {
+		return err
+	}
+
+	if out == "-" {
+		return Encode(os.Stdout, rendered, format, quality)
+	}
+	f, err := os.Create(out)
+	if err != nil {
+		return err
+	}
+	if err := Encode(f, rendered, format, quality); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// build runs a pipeline or loads a graph file.
+func build(pipeline, graph string) (image.Image, error) {
+	if graph != "" {
+		var r io.Reader = os.Stdin
+		if graph != "-" {
+			f, err := os.Open(graph)
+			if err != nil {
+				return nil, err
+			}
+			defer f.Close()
+			r = f
+		}
+		return pattern.LoadGraph(r)
+	}
+	s, err := dsl.ParseScript(pipeline)
+	if err != nil {
+		return nil, err
+	}
+	fm := make(dsl.FuncMap)
+	registerCommands(fm)
+	img, err := dsl.NewInterpreter(fm).Run(s, nil)
+	if err != nil {
+		return nil, err
+	}
+	if img == nil {
+		return nil, errors.New("the pipeline produced no image")
+	}
+	return img, nil
+}
+
+// renderBounds works out the region to render from the flags, starting from the
+// bounds of the image.
+func renderBounds(r image.Rectangle, width, height int, bounds string) (image.Rectangle, error) {
+	if bounds != "" {
+		if width != 0 || height != 0 {
+			return r, errors.New("give either bounds or a width and height, not both")
+		}
+		v, err := pattern.ParseValue(reflect.TypeOf(r), bounds)
+		if err != nil {
+			return r, fmt.Errorf("bounds: %w", err)
+		}
+		r = v.(image.Rectangle)
+	}
+	if width < 0 || height < 0 {
+		return r, fmt.Errorf("invalid size %dx%d", width, height)
+	}
+	if width > 0 {
+		r.Max.X = r.Min.X + width
+	}
+	if height > 0 {
+		r.Max.Y = r.Min.Y + height
+	}
+	if r.Empty() {
+		return r, fmt.Errorf("nothing to render in %v", r)
+	}
+	return r, nil
+}
@@ -0,0 +1,351 @@
+package pattern_cli
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/arran4/go-pattern"
+	"github.com/arran4/go-pattern/dsl"
+	"github.com/arran4/go-pattern/termimg"
+)
+
+// maxHistory is the number of lines kept in the history file.
+const maxHistory = 1000
+
+// Repl is a subcommand `pattern-cli repl`
+//
+// Each result is kept as $_, and a line starting with | takes it as input.
+// Lines starting with : are session commands; see :help.
+func Repl() {
+	s := newSession(os.Stdout)
+	if home, err := os.UserHomeDir(); err == nil {
+		s.historyFile = filepath.Join(home, ".pattern_cli_history")
+		s.loadHistory()
+	}
+	scanner := bufio.NewScanner(os.Stdin)
+	fmt.Print("> ")
+	for scanner.Scan() {
+		input := strings.TrimSpace(scanner.Text())
+		if input == "exit" || input == "quit" || input == ":quit" {
+			break
+		}
+		if err := s.exec(input); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+		fmt.Print("> ")
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading stdin: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// session is the state the REPL keeps from one line to the next.
+type session struct {
+	out    io.Writer
+	interp *dsl.Interpreter
+	// bounds, when not empty, is set on every result.
+	bounds image.Rectangle
+	// seed is set on every result that takes one, unless the command was given
+	// a seed.
+	seed    int64
+	hasSeed bool
+
+	history     []string
+	historyFile string
+}
+
+func newSession(out io.Writer) *session {
+	s := &session{out: out}
+	fm := make(dsl.FuncMap)
+	registerCommands(fm)
+	for name, fn := range fm {
+		fm[name] = s.withSeed(fn)
+	}
+	s.interp = dsl.NewInterpreter(fm)
+	return s
+}
+
+func (s *session) withSeed(fn dsl.CommandFunc) dsl.CommandFunc {
+	return func(args dsl.Args, input image.Image) (image.Image, error) {
+		img, err := fn(args, input)
+		if err != nil || !s.hasSeed {
+			return img, err
+		}
+		if _, ok := args.Lookup("seed"); !ok {
+			pattern.SetSeed(s.seed)(img)
+		}
+		return img, nil
+	}
+}
+
+// last is the previous result, or nil.
+func (s *session) last() image.Image {
+	return s.interp.Vars["_"]
+}
+
+// exec runs a line: a session command, a history reference or a script.
+func (s *session) exec(line string) error {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, "!") {
+		var err error
+		if line, err = s.recall(line); err != nil {
+			return err
+		}
+		fmt.Fprintln(s.out, line)
+	}
+	if line == "" {
+		return nil
+	}
+	s.addHistory(line)
+	if strings.HasPrefix(line, ":") {
+		return s.command(line)
+	}
+
+	script, err := dsl.ParseScript(line)
+	if err != nil {
+		return err
+	}
+	var input image.Image
+	if strings.HasPrefix(line, "|") {
+		if input = s.last(); input == nil {
+			return errors.New("there is no previous result to pipe in")
+		}
+	}
+	img, err := s.interp.Run(script, input)
+	if err != nil || img == nil {
+		return err
+	}
+	if !s.bounds.Empty() {
+		pattern.SetChainBounds(img, s.bounds)
+	}
+	s.interp.Vars["_"] = img
+	fmt.Fprintf(s.out, "_ = %s\n", describeImage(img))
+	return nil
+}
+
+// describeImage names img's pattern type and gives its bounds.
+func describeImage(img image.Image) string {
+	name := reflect.TypeOf(img).String()
+	if t, ok := pattern.PatternTypeOf(img); ok {
+		name = t.Name
+	}
+	return fmt.Sprintf("%s %v", name, img.Bounds())
+}
+
+const replHelp = `Pipelines and let statements run as in scripts. Each result is kept as $_,
+and a line starting with | takes it as input.
+
+    :help [command]          list these commands, or describe a pipeline command
+    :name <name>             keep the last result as $name
+    :vars                    list the variables
+    :show [$name] [ansi|sixel|kitty] [width]
+                             draw the last result, or a variable, in the terminal
+    :save <file> [$name]     save the last result, or a variable, to a file
+    :bounds [w h | minX,minY,maxX,maxY | off]
+                             set the bounds of every result
+    :seed [n | off]          set the seed of every result that takes one
+    :history                 list the history; !n runs line n again and !! the last
+    :quit                    leave the REPL
+`
+
+// command runs a session command.
+func (s *session) command(line string) error {
+	fields := strings.Fields(line)
+	name, args := fields[0], fields[1:]
+	switch name {
+	case ":help":
+		if len(args) == 0 {
+			_, err := io.WriteString(s.out, replHelp)
+			return err
+		}
+		return writeDescribe(s.out, args[0])
+	case ":name":
+		if len(args) != 1 {
+			return errors.New(":name takes a variable name")
+		}
+		img := s.last()
+		if img == nil {
+			return errors.New("there is no result to name")
+		}
+		s.interp.Vars[strings.TrimPrefix(args[0], "$")] = img
+	case ":vars":
+		for _, name := range slices.Sorted(maps.Keys(s.interp.Vars)) {
+			fmt.Fprintf(s.out, "$%s = %s\n", name, describeImage(s.interp.Vars[name]))
+		}
+	case ":show":
+		img, args, err := s.image(args)
+		if err != nil {
+			return err
+		}
+		format, width := termimg.ANSI, 0
+		for _, arg := range args {
+			if f, err := termimg.ParseFormat(arg); err == nil {
+				format = f
+			} else if width, err = strconv.Atoi(arg); err != nil || width < 1 {
+				return fmt.Errorf("invalid format or width %q", arg)
+			}
+		}
+		rendered, err := pattern.Render(context.Background(), img)
+		if err != nil {
+			return err
+		}
+		return termimg.Encode(s.out, rendered, format, width)
+	case ":save":
+		if len(args) == 0 {
+			return errors.New(":save takes a file name")
+		}
+		img, _, err := s.image(args[1:])
+		if err != nil {
+			return err
+		}
+		format, err := ParseFormat(FormatOf(args[0]))
+		if err != nil {
+			return err
+		}
+		rendered, err := pattern.Render(context.Background(), img)
+		if err != nil {
+			return err
+		}
+		f, err := os.Create(args[0])
+		if err != nil {
+			return err
+		}
+		if err := Encode(f, rendered, format, 0); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		fmt.Fprintf(s.out, "Saved to %s\n", args[0])
+	case ":bounds":
+		switch {
+		case len(args) == 0:
+		case len(args) == 1 && args[0] == "off":
+			s.bounds = image.Rectangle{}
+		default:
+			r, err := parseBounds(args)
+			if err != nil {
+				return err
+			}
+			s.bounds = r
+		}
+		if s.bounds.Empty() {
+			fmt.Fprintln(s.out, "bounds: off")
+		} else {
+			fmt.Fprintf(s.out, "bounds: %v\n", s.bounds)
+		}
+	case ":seed":
+		switch {
+		case len(args) == 0:
+		case len(args) == 1 && args[0] == "off":
+			s.hasSeed = false
+		default:
+			seed, err := strconv.ParseInt(args[0], 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid seed %q", args[0])
+			}
+			s.seed, s.hasSeed = seed, true
+		}
+		if s.hasSeed {
+			fmt.Fprintf(s.out, "seed: %d\n", s.seed)
+		} else {
+			fmt.Fprintln(s.out, "seed: off")
+		}
+	case ":history":
+		for i, line := range s.history {
+			fmt.Fprintf(s.out, "%5d  %s\n", i+1, line)
+		}
+	default:
+		return fmt.Errorf("unknown command %s; see :help", name)
+	}
+	return nil
+}
+
+// image takes the image a session command works on from the front of args: a
+// $name, or else the last result.
+func (s *session) image(args []string) (image.Image, []string, error) {
+	if len(args) > 0 && strings.HasPrefix(args[0], "$") {
+		img, ok := s.interp.Vars[args[0][1:]]
+		if !ok {
+			return nil, nil, fmt.Errorf("undefined variable %s", args[0])
+		}
+		return img, args[1:], nil
+	}
+	if img := s.last(); img != nil {
+		return img, args, nil
+	}
+	return nil, nil, errors.New("there is no result yet")
+}
+
+// parseBounds reads bounds given as a width and height or as minX,minY,maxX,maxY.
+func parseBounds(args []string) (image.Rectangle, error) {
+	if len(args) == 2 {
+		w, err1 := strconv.Atoi(args[0])
+		h, err2 := strconv.Atoi(args[1])
+		if err1 != nil || err2 != nil || w <= 0 || h <= 0 {
+			return image.Rectangle{}, fmt.Errorf("invalid size %s %s", args[0], args[1])
+		}
+		return image.Rect(0, 0, w, h), nil
+	}
+	return renderBounds(image.Rectangle{}, 0, 0, strings.Join(args, ""))
+}
+
+// recall expands a history reference: !! for the last line or !n for line n.
+func (s *session) recall(ref string) (string, error) {
+	n := len(s.history)
+	if ref != "!!" {
+		var err error
+		if n, err = strconv.Atoi(ref[1:]); err != nil {
+			return "", fmt.Errorf("invalid history reference %s", ref)
+		}
+	}
+	if n < 1 || n > len(s.history) {
+		return "", fmt.Errorf("no history entry %s", ref)
+	}
+	return s.history[n-1], nil
+}
+
+func (s *session) loadHistory() {
+	data, err := os.ReadFile(s.historyFile)
+	if err != nil {
+		return
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if line != "" {
+			s.history = append(s.history, line)
+		}
+	}
+}
+
+// addHistory records a line, appending it to the history file. The file is
+// rewritten when it grows past maxHistory lines.
+func (s *session) addHistory(line string) {
+	s.history = append(s.history, line)
+	if s.historyFile == "" {
+		return
+	}
+	if len(s.history) > maxHistory {
+		s.history = s.history[len(s.history)-maxHistory:]
+		_ = os.WriteFile(s.historyFile, []byte(strings.Join(s.history, "\n")+"\n"), 0600)
+		return
+	}
+	f, err := os.OpenFile(s.historyFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(f, line)
+	f.Close()
+}
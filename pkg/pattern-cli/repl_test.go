@@ -0,0 +1,108 @@
+package pattern_cli
+
+import (
+	"image"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/arran4/go-pattern"
+)
+
+func TestSession(t *testing.T) {
+	var out strings.Builder
+	s := newSession(&out)
+	s.historyFile = filepath.Join(t.TempDir(), "history")
+	for _, line := range []string{
+		":bounds 40 20",
+		":seed 7",
+		"worley_noise",
+		":name cells",
+		"| rotate 90",
+		"checker",
+	} {
+		if err := s.exec(line); err != nil {
+			t.Fatalf("%s: %v", line, err)
+		}
+	}
+	cells, ok := s.interp.Vars["cells"].(*pattern.WorleyNoise)
+	if !ok {
+		t.Fatalf("Expected $cells to be worley noise, got %T", s.interp.Vars["cells"])
+	}
+	if cells.Seed.Seed != 7 || cells.Bounds() != image.Rect(0, 0, 40, 20) {
+		t.Errorf("Expected the session seed 7 and bounds 40x20, got %d and %v", cells.Seed.Seed, cells.Bounds())
+	}
+	if _, ok := s.last().(*pattern.Checker); !ok {
+		t.Errorf("Expected $_ to be the last result, got %T", s.last())
+	}
+	if !strings.Contains(out.String(), "_ = rotate ") {
+		t.Errorf("Expected the rotated result to be reported, got:\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := s.exec(":show $cells 20"); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n"); len(lines) != 5 || strings.Count(lines[0], "▀") != 20 {
+		t.Errorf("Expected 5 lines of 20 cells, got:\n%s", out.String())
+	}
+	out.Reset()
+	if err := s.exec(":show $cells sixel 20"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out.String(), "\x1bP0;1;0q\"1;1;20;") {
+		t.Errorf("Expected a 20 pixel wide sixel image, got %q", out.String())
+	}
+
+	file := filepath.Join(t.TempDir(), "out.png")
+	if err := s.exec(":save " + file + " $cells"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(file); err != nil {
+		t.Error(err)
+	}
+
+	for line, want := range map[string]string{
+		":bounds 1":     "expected minX,minY,maxX,maxY",
+		":seed x":       "invalid seed",
+		":show $nosuch": "undefined variable",
+		":show wide":    "invalid format or width",
+		":frobnicate":   "unknown command",
+		"!99":           "no history entry",
+	} {
+		if err := s.exec(line); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected an error containing %q, got %v", line, want, err)
+		}
+	}
+}
+
+func TestSessionHistory(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "history")
+	s := newSession(&strings.Builder{})
+	s.historyFile = file
+	for _, line := range []string{"checker", ":seed 3"} {
+		if err := s.exec(line); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var out strings.Builder
+	s = newSession(&out)
+	s.historyFile = file
+	s.loadHistory()
+	if err := s.exec("!1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := s.last().(*pattern.Checker); !ok {
+		t.Errorf("Expected !1 to run checker again, got %T", s.last())
+	}
+	if err := s.exec(":history"); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"1  checker", "2  :seed 3", "3  checker", "4  :history"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Expected the history to contain %q, got:\n%s", want, out.String())
+		}
+	}
+}
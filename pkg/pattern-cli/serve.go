@@ -0,0 +1,331 @@
+package pattern_cli
+
+import (
+	"cmp"
+	"container/list"
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"image"
+	"log"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/arran4/go-pattern"
+	"github.com/arran4/go-pattern/dsl"
+)
+
+//go:embed "serve_index.html.gotmpl"
+var serveIndexRaw string
+
+//go:embed "serve_live.html.gotmpl"
+var serveLiveRaw string
+
+var (
+	serveIndex = template.Must(template.New("index").Parse(serveIndexRaw))
+	serveLive  = template.Must(template.New("live").Parse(serveLiveRaw))
+)
+
+const (
+	// maxServeSize is the largest width or height the server renders.
+	maxServeSize = 4096
+	// maxServeCacheBytes is the total size of the rendered images the server
+	// keeps, at 4 bytes a pixel; it holds 4 of the largest.
+	maxServeCacheBytes = 256 << 20
+)
+
+// Server is an http.Handler serving previews of patterns and pipelines:
+//
+//	/                      an index of the registered generators
+//	/pattern/{name}.{ext}  the named command; the query holds its named arguments
+//	/pipeline?src=...      a pipeline or script, as png unless format is given
+//	/live                  a page showing Script, reloaded whenever the file changes
+//
+// The w and h query parameters set the size of any image. Rendered images are kept
+// in Buffers keyed by the request, so repeated requests are not rendered again; the
+// least recently used are dropped once they take more than maxServeCacheBytes. The
+// save and preview commands are left out, so requests cannot write files or the
+// server's terminal.
+type Server struct {
+	// Script is the file shown by /live. It may be empty.
+	Script string
+
+	funcs dsl.FuncMap
+	mux   *http.ServeMux
+	mu    sync.Mutex
+	cache map[string]*list.Element
+	// recent orders the cached serveEntries, most recently used first.
+	recent *list.List
+	// cached is the total size of the cached images, and cacheLimit the most it
+	// may reach.
+	cached, cacheLimit int
+}
+
+// serveEntry is a rendered image in the Server's cache.
+type serveEntry struct {
+	key    string
+	buffer *pattern.Buffer
+	size   int
+}
+
+// NewServer creates a Server, showing script on its live page.
+func NewServer(script string) *Server {
+	s := &Server{
+		Script: script,
+		funcs:  make(dsl.FuncMap),
+		mux:    http.NewServeMux(),
+		cache:  map[string]*list.Element{},
+		recent: list.New(),
+
+		cacheLimit: maxServeCacheBytes,
+	}
+	registerCommands(s.funcs)
+	delete(s.funcs, "save")
+	delete(s.funcs, "preview")
+	s.mux.HandleFunc("GET /{$}", s.index)
+	s.mux.HandleFunc("GET /pattern/{file}", s.pattern)
+	s.mux.HandleFunc("GET /pipeline", s.pipeline)
+	s.mux.HandleFunc("GET /live", s.live)
+	s.mux.HandleFunc("GET /live/version", s.liveVersion)
+	s.mux.HandleFunc("GET /live/image", s.liveImage)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Serve is a subcommand `pattern-cli serve`
+//
+// It serves previews of patterns and pipelines on addr, and of the script file
+// at script on /live.
+func Serve(addr, script string) error {
+	if script != "" {
+		if _, err := os.Stat(script); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("Serving on http://%s/\n", addr)
+	return http.ListenAndServe(addr, NewServer(script))
+}
+
+func (s *Server) index(w http.ResponseWriter, r *http.Request) {
+	type entry struct{ Name, Summary string }
+	var data struct {
+		Generators []entry
+		Script     string
+	}
+	docs := commandDocs()
+	for _, t := range pattern.Patterns() {
+		if t.Category == pattern.CategoryGenerator {
+			data.Generators = append(data.Generators, entry{t.Name, summary(docs[t.Name].Description)})
+		}
+	}
+	data.Script = s.Script
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := serveIndex.Execute(w, data); err != nil {
+		log.Printf("serve: %v", err)
+	}
+}
+
+func (s *Server) pattern(w http.ResponseWriter, r *http.Request) {
+	file := r.PathValue("file")
+	name := strings.TrimSuffix(file, path.Ext(file))
+	fn, ok := s.funcs[name]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	format, err := serveFormat(cmp.Or(FormatOf(file), "png"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	q := r.URL.Query()
+	var args dsl.Args
+	for _, k := range slices.Sorted(maps.Keys(q)) {
+		if k != "w" && k != "h" {
+			args = append(args, dsl.Arg{Name: k, Value: q.Get(k)})
+		}
+	}
+	s.serveImage(w, r, "pattern/"+name+"?"+q.Encode(), format, func() (image.Image, error) {
+		return fn(args, nil)
+	})
+}
+
+func (s *Server) pipeline(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	src := q.Get("src")
+	if strings.TrimSpace(src) == "" {
+		http.Error(w, "pipeline requires a src parameter", http.StatusBadRequest)
+		return
+	}
+	format, err := serveFormat(cmp.Or(q.Get("format"), "png"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	s.serveImage(w, r, "pipeline?"+q.Encode(), format, func() (image.Image, error) {
+		return s.run(src)
+	})
+}
+
+// serveFormat parses the format of an image to serve, which cannot be a terminal
+// format.
+func serveFormat(name string) (string, error) {
+	format, err := ParseFormat(name)
+	if err == nil && IsTerminalFormat(format) {
+		err = fmt.Errorf("%s is a terminal format and cannot be served", format)
+	}
+	return format, err
+}
+
+func (s *Server) live(w http.ResponseWriter, r *http.Request) {
+	if s.Script == "" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := serveLive.Execute(w, s.Script); err != nil {
+		log.Printf("serve: %v", err)
+	}
+}
+
+// liveVersion writes the modification time of the script, which the live page
+// polls to see when to reload.
+func (s *Server) liveVersion(w http.ResponseWriter, r *http.Request) {
+	version, err := s.scriptVersion()
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	fmt.Fprint(w, version)
+}
+
+func (s *Server) liveImage(w http.ResponseWriter, r *http.Request) {
+	version, err := s.scriptVersion()
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	q := r.URL.Query()
+	s.serveImage(w, r, "live/"+version+"?"+q.Encode(), "png", func() (image.Image, error) {
+		src, err := os.ReadFile(s.Script)
+		if err != nil {
+			return nil, err
+		}
+		return s.run(string(src))
+	})
+}
+
+func (s *Server) scriptVersion() (string, error) {
+	if s.Script == "" {
+		return "", os.ErrNotExist
+	}
+	fi, err := os.Stat(s.Script)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(fi.ModTime().UnixNano(), 10), nil
+}
+
+// run runs a script with a new interpreter.
+func (s *Server) run(src string) (image.Image, error) {
+	script, err := dsl.ParseScript(src)
+	if err != nil {
+		return nil, err
+	}
+	img, err := dsl.NewInterpreter(s.funcs).Run(script, nil)
+	if err != nil {
+		return nil, err
+	}
+	if img == nil {
+		return nil, errors.New("the pipeline produced no image")
+	}
+	return img, nil
+}
+
+// serveImage writes the image build makes, at the size the request asks for, in
+// format. The rendered image is cached under key.
+func (s *Server) serveImage(w http.ResponseWriter, r *http.Request, key, format string, build func() (image.Image, error)) {
+	b, err := s.buffer(r.Context(), key, r.URL.Query(), build)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "image/"+format)
+	// The rendered image is encoded rather than the Buffer, whose At locks for
+	// every pixel.
+	if err := Encode(w, b.Cached, format, 0); err != nil {
+		log.Printf("serve: %s: %v", key, err)
+	}
+}
+
+// buffer returns the Buffer cached under key, or builds the image, sizes it from
+// the w and h query parameters and renders it into a new one.
+func (s *Server) buffer(ctx context.Context, key string, q url.Values, build func() (image.Image, error)) (*pattern.Buffer, error) {
+	s.mu.Lock()
+	if e, ok := s.cache[key]; ok {
+		s.recent.MoveToFront(e)
+		s.mu.Unlock()
+		return e.Value.(*serveEntry).buffer, nil
+	}
+	s.mu.Unlock()
+
+	var size [2]int
+	for i, name := range []string{"w", "h"} {
+		if v := q.Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 || n > maxServeSize {
+				return nil, fmt.Errorf("%s must be a whole number from 0 to %d, got %q", name, maxServeSize, v)
+			}
+			size[i] = n
+		}
+	}
+	img, err := build()
+	if err != nil {
+		return nil, err
+	}
+	rect, err := renderBounds(img.Bounds(), size[0], size[1], "")
+	if err != nil {
+		return nil, err
+	}
+	if rect.Dx() > maxServeSize || rect.Dy() > maxServeSize {
+		return nil, fmt.Errorf("%v is larger than %dx%d", rect, maxServeSize, maxServeSize)
+	}
+	if rect != img.Bounds() {
+		pattern.SetChainBounds(img, rect)
+	}
+	b := pattern.NewBuffer(img, pattern.SetBounds(rect))
+	if err := b.RefreshContext(ctx); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.cache[key]; ok {
+		// Another request rendered it meanwhile.
+		s.recent.MoveToFront(e)
+		return e.Value.(*serveEntry).buffer, nil
+	}
+	n := 4 * rect.Dx() * rect.Dy()
+	for s.recent.Len() > 0 && s.cached+n > s.cacheLimit {
+		oldest := s.recent.Remove(s.recent.Back()).(*serveEntry)
+		delete(s.cache, oldest.key)
+		s.cached -= oldest.size
+	}
+	s.cache[key] = s.recent.PushFront(&serveEntry{key: key, buffer: b, size: n})
+	s.cached += n
+	return b, nil
+}
@@ -0,0 +1,159 @@
+package pattern_cli
+
+import (
+	"image"
+	"image/png"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func get(t *testing.T, h http.Handler, target string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec
+}
+
+func decodePNG(t *testing.T, rec *httptest.ResponseRecorder) image.Image {
+	t.Helper()
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body)
+	}
+	img, err := png.Decode(rec.Body)
+	if err != nil {
+		t.Fatalf("Expected a PNG: %v", err)
+	}
+	return img
+}
+
+func TestServerPattern(t *testing.T) {
+	s := NewServer("")
+	img := decodePNG(t, get(t, s, "/pattern/brick.png?w=64&h=32&seed=3&width=10"))
+	if img.Bounds() != image.Rect(0, 0, 64, 32) {
+		t.Errorf("Expected 64x32, got %v", img.Bounds())
+	}
+	if len(s.cache) != 1 {
+		t.Fatalf("Expected the image to be cached, got %d entries", len(s.cache))
+	}
+	get(t, s, "/pattern/brick.png?width=10&h=32&w=64&seed=3")
+	if len(s.cache) != 1 {
+		t.Errorf("Expected the same query in another order to be cached once, got %d entries", len(s.cache))
+	}
+
+	for target, want := range map[string]int{
+		"/pattern/no_such_pattern.png":  http.StatusNotFound,
+		"/pattern/brick.webp":           http.StatusBadRequest,
+		"/pattern/brick.sixel":          http.StatusBadRequest,
+		"/pattern/brick.png?width=wide": http.StatusBadRequest,
+		"/pattern/brick.png?w=100000":   http.StatusBadRequest,
+		"/pattern/rotate.png":           http.StatusBadRequest,
+		"/pipeline":                     http.StatusBadRequest,
+		"/pipeline?src=" + url.QueryEscape("checker | no_such_command"): http.StatusBadRequest,
+		"/pipeline?src=" + url.QueryEscape("checker | save x.png"):      http.StatusBadRequest,
+	} {
+		if rec := get(t, s, target); rec.Code != want {
+			t.Errorf("%s: expected status %d, got %d: %s", target, want, rec.Code, rec.Body)
+		}
+	}
+}
+
+func TestServerCacheEviction(t *testing.T) {
+	s := NewServer("")
+	// Room for three 10x10 images.
+	s.cacheLimit = 3 * 4 * 100
+	names := []string{"checker", "polka", "brick", "rect"}
+	target := func(i int) string { return "/pattern/" + names[i] + ".png?w=10&h=10" }
+	key := func(i int) string { return "pattern/" + names[i] + "?h=10&w=10" }
+	for i := 0; i < 3; i++ {
+		get(t, s, target(i))
+	}
+	first := s.cache[key(0)]
+	if first == nil {
+		t.Fatal("Expected the first image to be cached")
+	}
+	// Using the first keeps it, so the second is dropped for a new image.
+	get(t, s, target(0))
+	get(t, s, target(3))
+	if len(s.cache) != 3 || s.cached != s.cacheLimit {
+		t.Errorf("Expected 3 cached images in %d bytes, got %d in %d", s.cacheLimit, len(s.cache), s.cached)
+	}
+	if s.cache[key(0)] != first {
+		t.Error("Expected the recently used first image to be kept")
+	}
+	if _, ok := s.cache[key(1)]; ok {
+		t.Error("Expected the least recently used image to be dropped")
+	}
+
+	// An image twice the size drops two.
+	get(t, s, "/pattern/checker.png?w=20&h=10")
+	if len(s.cache) != 2 || s.cached != s.cacheLimit {
+		t.Errorf("Expected 2 cached images in %d bytes, got %d in %d", s.cacheLimit, len(s.cache), s.cached)
+	}
+	if _, ok := s.cache[key(2)]; ok {
+		t.Error("Expected the least recently used images to make room")
+	}
+}
+
+func TestServerPipeline(t *testing.T) {
+	s := NewServer("")
+	src := url.QueryEscape("let c = checker\n$c | rotate 90")
+	img := decodePNG(t, get(t, s, "/pipeline?w=20&h=10&src="+src))
+	if img.Bounds() != image.Rect(0, 0, 20, 10) {
+		t.Errorf("Expected 20x10, got %v", img.Bounds())
+	}
+	rec := get(t, s, "/pipeline?format=jpeg&src="+src)
+	if ct := rec.Header().Get("Content-Type"); ct != "image/jpeg" {
+		t.Errorf("Expected a JPEG, got %s", ct)
+	}
+}
+
+func TestServerIndex(t *testing.T) {
+	rec := get(t, NewServer(""), "/")
+	body := rec.Body.String()
+	for _, want := range []string{`src="/pattern/brick.png?w=128&amp;h=128"`, "Alternates between two colors"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected the index to contain %q", want)
+		}
+	}
+	if strings.Contains(body, "/pattern/rotate.png") {
+		t.Error("Expected the index to list only generators")
+	}
+	if rec := get(t, NewServer(""), "/live"); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected no live page without a script, got %d", rec.Code)
+	}
+}
+
+func TestServerLive(t *testing.T) {
+	script := filepath.Join(t.TempDir(), "live.pattern")
+	if err := os.WriteFile(script, []byte("checker"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s := NewServer(script)
+	if rec := get(t, s, "/live"); rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "/live/version") {
+		t.Errorf("Expected the live page, got %d: %s", rec.Code, rec.Body)
+	}
+	v1 := get(t, s, "/live/version").Body.String()
+	decodePNG(t, get(t, s, "/live/image?w=8&h=8"))
+
+	if err := os.WriteFile(script, []byte("checker | no_such_command"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Second)
+	if err := os.Chtimes(script, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if v2 := get(t, s, "/live/version").Body.String(); v2 == v1 {
+		t.Errorf("Expected the version to change from %s", v1)
+	}
+	rec := get(t, s, "/live/image?w=8&h=8")
+	if body, _ := io.ReadAll(rec.Body); rec.Code != http.StatusBadRequest || !strings.Contains(string(body), "line 1") {
+		t.Errorf("Expected the script's error, got %d: %s", rec.Code, body)
+	}
+}
@@ -3,6 +3,7 @@ package pattern
 import (
 	"image"
 	"image/color"
+	"math"
 	"math/rand"
 	"sync"
 )
@@ -12,8 +13,11 @@ var _ image.Image = (*Plasma)(nil)
 
 // Plasma generates a plasma noise texture using Diamond-Square algorithm.
 // It supports RGB (independent channels) or Grayscale.
+// Animated with SetTime, it colour cycles: each value runs up to 1 and back down
+// again once a second.
 type Plasma struct {
 	Null
+	Time
 	Seed       int64
 	Roughness  float64
 	Color      bool // If true, generates RGB plasma. If false, grayscale.
@@ -117,11 +121,11 @@ func (p *Plasma) At(x, y int) color.Color {
 	gy := y % gh
 	if gy < 0 { gy += gh }
 
-	r := clampFloat(p.gridR[gx][gy])
+	r := p.cycle(p.gridR[gx][gy])
 
 	if p.Color {
-		g := clampFloat(p.gridG[gx][gy])
-		b := clampFloat(p.gridB[gx][gy])
+		g := p.cycle(p.gridG[gx][gy])
+		b := p.cycle(p.gridB[gx][gy])
 		return color.RGBA{uint8(r * 255), uint8(g * 255), uint8(b * 255), 255}
 	}
 
@@ -129,6 +133,23 @@ func (p *Plasma) At(x, y int) color.Color {
 	return color.RGBA{v, v, v, 255}
 }
 
+// cycle clamps a grid value to 0..1 and moves it along the colour cycle to the
+// current time.
+func (p *Plasma) cycle(v float64) float64 {
+	v = clampFloat(v)
+	if p.Time.Time == 0 {
+		return v
+	}
+	u := math.Mod(v+2*p.Time.Time, 2)
+	if u < 0 {
+		u += 2
+	}
+	if u > 1 {
+		u = 2 - u
+	}
+	return u
+}
+
 func clampFloat(v float64) float64 {
 	if v < 0 {
 		return 0
@@ -155,3 +176,7 @@ func NewPlasma(ops ...func(any)) image.Image {
 	}
 	return p
 }
+
+func init() {
+	RegisterPattern(generatorType("plasma", &Plasma{}, NewPlasma))
+}
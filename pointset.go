@@ -0,0 +1,274 @@
+package pattern
+
+import (
+	"image"
+	"math"
+)
+
+// The point set functions scatter sites over a rectangle for Voronoi, Scatter and
+// the like. They are deterministic: the same arguments, seed included, always give
+// the same points, drawn from StableHash. Points are rounded down to the pixel
+// containing them.
+
+// hashUnit returns a deterministic value in [0, 1) for (i, j) and seed.
+func hashUnit(i, j int, seed uint64) float64 {
+	return float64(StableHash(i, j, seed)>>11) / (1 << 53)
+}
+
+// unitToBounds maps a point of the unit square into b.
+func unitToBounds(b image.Rectangle, u, v float64) image.Point {
+	return image.Point{
+		X: b.Min.X + int(math.Floor(u*float64(b.Dx()))),
+		Y: b.Min.Y + int(math.Floor(v*float64(b.Dy()))),
+	}
+}
+
+// JitteredGridPoints places a point in each square of a grid spacing pixels
+// apart over b, moved from the centre of its square by up to jitter squares
+// either way. A jitter of 0 gives a regular grid and 0.5 lets the points reach
+// anywhere in their squares.
+func JitteredGridPoints(b image.Rectangle, spacing, jitter float64, seed uint64) []image.Point {
+	if spacing <= 0 || b.Empty() {
+		return nil
+	}
+	cols := int(math.Ceil(float64(b.Dx()) / spacing))
+	rows := int(math.Ceil(float64(b.Dy()) / spacing))
+	points := make([]image.Point, 0, cols*rows)
+	for j := 0; j < rows; j++ {
+		for i := 0; i < cols; i++ {
+			x := (float64(i) + 0.5 + jitter*(2*hashUnit(i, j, seed)-1)) * spacing
+			y := (float64(j) + 0.5 + jitter*(2*hashUnit(i, j, seed^0x5bd1e995)-1)) * spacing
+			p := image.Point{b.Min.X + int(math.Floor(x)), b.Min.Y + int(math.Floor(y))}
+			if p.In(b) {
+				points = append(points, p)
+			}
+		}
+	}
+	return points
+}
+
+// HaltonPoints returns the first n points of the Halton sequence in bases 2 and 3
+// over b. The sequence fills space evenly at any length, without the clumps of
+// uniform random points. The seed shifts the sequence around the rectangle as
+// though it were a torus, which keeps it even.
+func HaltonPoints(b image.Rectangle, n int, seed uint64) []image.Point {
+	shiftX, shiftY := hashUnit(0, 0, seed), hashUnit(1, 0, seed)
+	points := make([]image.Point, n)
+	for i := range points {
+		u := math.Mod(radicalInverse(i+1, 2)+shiftX, 1)
+		v := math.Mod(radicalInverse(i+1, 3)+shiftY, 1)
+		points[i] = unitToBounds(b, u, v)
+	}
+	return points
+}
+
+// radicalInverse mirrors the digits of i in base about the radix point.
+func radicalInverse(i, base int) float64 {
+	var v float64
+	f := 1 / float64(base)
+	for scale := f; i > 0; i /= base {
+		v += float64(i%base) * scale
+		scale *= f
+	}
+	return v
+}
+
+// SobolPoints returns the first n points of the two dimensional Sobol sequence
+// over b, which is even at every power of two points. The seed scrambles the
+// sequence by a digital shift, flipping the same bits of every coordinate, which
+// keeps it even.
+func SobolPoints(b image.Rectangle, n int, seed uint64) []image.Point {
+	// The direction numbers of the first dimension give the van der Corput
+	// sequence, and those of the second come from the polynomial x + 1.
+	var dirX, dirY [32]uint32
+	for k := range dirX {
+		dirX[k] = 1 << (31 - k)
+		if k == 0 {
+			dirY[k] = 1 << 31
+		} else {
+			dirY[k] = dirY[k-1] ^ dirY[k-1]>>1
+		}
+	}
+	h := StableHash(0, 0, seed)
+	shiftX, shiftY := uint32(h), uint32(h>>32)
+	points := make([]image.Point, n)
+	for i := range points {
+		x, y := shiftX, shiftY
+		for k := 0; k < 32 && i>>k != 0; k++ {
+			if i>>k&1 != 0 {
+				x ^= dirX[k]
+				y ^= dirY[k]
+			}
+		}
+		points[i] = unitToBounds(b, float64(x)/(1<<32), float64(y)/(1<<32))
+	}
+	return points
+}
+
+// PoissonDiskOptions are the options for PoissonDiskPoints.
+type PoissonDiskOptions struct {
+	// Density, read with ScalarFieldOf, varies the spacing of the points: where it
+	// is 1 they are the radius apart, and where it is 0, MaxRadius apart. To stipple
+	// an image, whose dark areas want the most points, invert it first.
+	Density image.Image
+	// MaxRadius is the spacing where the density is 0. It defaults to twice the
+	// radius.
+	MaxRadius float64
+	// Attempts is the number of places tried around each point before it is given
+	// up on. It defaults to 30.
+	Attempts int
+}
+
+// PoissonDiskPoints fills b with points no closer together than radius, by
+// Bridson's algorithm: each new point is tried in the ring between one and two
+// spacings from a point already placed, and kept if it is far enough from every
+// other. The result is blue noise, random but even, which is what sites of a
+// natural looking Voronoi or stipples want. Where the spacing varies with a
+// density, two points are kept the larger of their spacings apart. Points are
+// placed on whole pixels, so the spacing holds after rounding.
+func PoissonDiskPoints(b image.Rectangle, radius float64, seed uint64, o *PoissonDiskOptions) []image.Point {
+	if radius <= 0 || b.Empty() {
+		return nil
+	}
+	maxRadius, attempts := radius, 30
+	var density ScalarField
+	if o != nil {
+		if o.Attempts > 0 {
+			attempts = o.Attempts
+		}
+		if o.Density != nil {
+			density = ScalarFieldOf(o.Density)
+			maxRadius = 2 * radius
+			if o.MaxRadius > 0 {
+				maxRadius = o.MaxRadius
+			}
+		}
+	}
+	spacing := func(x, y float64) float64 {
+		if density == nil {
+			return radius
+		}
+		return maxRadius - (maxRadius-radius)*clamp01(density.ValueAt(x, y))
+	}
+	reach := math.Max(radius, maxRadius)
+
+	// No two points are closer than the least spacing, so the cells of the grid,
+	// whose diagonal is that spacing, hold at most one each.
+	cellSize := math.Min(radius, maxRadius) / math.Sqrt2
+	minX, minY := float64(b.Min.X), float64(b.Min.Y)
+	cols := int(math.Ceil(float64(b.Dx())/cellSize)) + 1
+	rows := int(math.Ceil(float64(b.Dy())/cellSize)) + 1
+	grid := make([]int, cols*rows)
+	for i := range grid {
+		grid[i] = -1
+	}
+	type sample struct{ x, y, r float64 }
+	var samples []sample
+	var active []int
+	cellOf := func(x, y float64) (int, int) {
+		return int((x - minX) / cellSize), int((y - minY) / cellSize)
+	}
+	add := func(x, y float64) {
+		cx, cy := cellOf(x, y)
+		grid[cy*cols+cx] = len(samples)
+		active = append(active, len(samples))
+		samples = append(samples, sample{x, y, spacing(x, y)})
+	}
+	fits := func(x, y, r float64) bool {
+		if x < minX || y < minY || x >= float64(b.Max.X) || y >= float64(b.Max.Y) {
+			return false
+		}
+		cx, cy := cellOf(x, y)
+		n := int(math.Ceil(reach / cellSize))
+		for gy := cy - n; gy <= cy+n; gy++ {
+			for gx := cx - n; gx <= cx+n; gx++ {
+				if gx < 0 || gy < 0 || gx >= cols || gy >= rows || grid[gy*cols+gx] < 0 {
+					continue
+				}
+				s := samples[grid[gy*cols+gx]]
+				if math.Hypot(s.x-x, s.y-y) < math.Max(r, s.r) {
+					return false
+				}
+			}
+		}
+		return true
+	}
+
+	// draw counts the random values taken, so each is drawn from its own hash.
+	draw := 0
+	random := func() float64 {
+		draw++
+		return hashUnit(draw, 0, seed)
+	}
+	add(math.Floor(minX+random()*float64(b.Dx())), math.Floor(minY+random()*float64(b.Dy())))
+	for len(active) > 0 {
+		k := int(random() * float64(len(active)))
+		s := samples[active[k]]
+		placed := false
+		for a := 0; a < attempts; a++ {
+			// Uniform over the area of the ring between r and 2r.
+			d := s.r * math.Sqrt(1+3*random())
+			angle := 2 * math.Pi * random()
+			x, y := math.Floor(s.x+d*math.Cos(angle)), math.Floor(s.y+d*math.Sin(angle))
+			if fits(x, y, spacing(x, y)) {
+				add(x, y)
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			active[k] = active[len(active)-1]
+			active = active[:len(active)-1]
+		}
+	}
+
+	points := make([]image.Point, len(samples))
+	for i, s := range samples {
+		points[i] = image.Point{int(s.x), int(s.y)}
+	}
+	return points
+}
+
+// LloydRelax moves each point to the centroid of its Voronoi cell over b,
+// iterations times, which evens out their spacing towards a centroidal Voronoi
+// tessellation. With a density, read with ScalarFieldOf, the centroids are weighted
+// by it, so points gather where it is high: weighted Voronoi stippling. Points
+// whose cells hold no pixels, or no weight, stay put.
+func LloydRelax(points []image.Point, b image.Rectangle, iterations int, density image.Image) []image.Point {
+	var field ScalarField
+	if density != nil {
+		field = ScalarFieldOf(density)
+	}
+	points = append([]image.Point(nil), points...)
+	sumX := make([]float64, len(points))
+	sumY := make([]float64, len(points))
+	mass := make([]float64, len(points))
+	for it := 0; it < iterations && len(points) > 0; it++ {
+		v := &Voronoi{Points: points}
+		v.SetBounds(b)
+		for i := range points {
+			sumX[i], sumY[i], mass[i] = 0, 0, 0
+		}
+		for y := b.Min.Y; y < b.Max.Y; y++ {
+			for x := b.Min.X; x < b.Max.X; x++ {
+				w := 1.0
+				if field != nil {
+					w = field.ValueAt(float64(x), float64(y))
+				}
+				if w <= 0 {
+					continue
+				}
+				i := v.nearest(float64(x), float64(y))
+				sumX[i] += w * float64(x)
+				sumY[i] += w * float64(y)
+				mass[i] += w
+			}
+		}
+		for i := range points {
+			if mass[i] > 0 {
+				points[i] = image.Point{int(math.Round(sumX[i] / mass[i])), int(math.Round(sumY[i] / mass[i]))}
+			}
+		}
+	}
+	return points
+}
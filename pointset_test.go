@@ -0,0 +1,178 @@
+package pattern
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"reflect"
+	"testing"
+)
+
+// minSpacing returns the least distance between two of points.
+func minSpacing(points []image.Point) float64 {
+	least := math.Inf(1)
+	for i, p := range points {
+		for _, q := range points[i+1:] {
+			least = math.Min(least, math.Hypot(float64(p.X-q.X), float64(p.Y-q.Y)))
+		}
+	}
+	return least
+}
+
+func TestPointSetsDeterministic(t *testing.T) {
+	b := image.Rect(10, 20, 110, 90)
+	sets := map[string]func(seed uint64) []image.Point{
+		"poisson": func(seed uint64) []image.Point { return PoissonDiskPoints(b, 8, seed, nil) },
+		"jitter":  func(seed uint64) []image.Point { return JitteredGridPoints(b, 10, 0.4, seed) },
+		"halton":  func(seed uint64) []image.Point { return HaltonPoints(b, 50, seed) },
+		"sobol":   func(seed uint64) []image.Point { return SobolPoints(b, 50, seed) },
+	}
+	for name, set := range sets {
+		points := set(1)
+		if len(points) == 0 {
+			t.Errorf("%s: no points", name)
+		}
+		for _, p := range points {
+			if !p.In(b) {
+				t.Errorf("%s: %v is outside %v", name, p, b)
+			}
+		}
+		if !reflect.DeepEqual(points, set(1)) {
+			t.Errorf("%s: points differ for the same seed", name)
+		}
+		if reflect.DeepEqual(points, set(2)) {
+			t.Errorf("%s: points are the same for another seed", name)
+		}
+	}
+}
+
+func TestPoissonDiskPoints(t *testing.T) {
+	b := image.Rect(0, 0, 200, 200)
+	points := PoissonDiskPoints(b, 10, 3, nil)
+	if d := minSpacing(points); d < 10 {
+		t.Errorf("points are %v apart, want at least 10", d)
+	}
+	// Maximal disk packings cover between about a third and 0.9 of the plane.
+	if n := len(points); n < 200 || n > 450 {
+		t.Errorf("got %d points, want a maximal packing", n)
+	}
+
+	// The left half is dense, the right half sparse.
+	density := NewGeneric(func(x, y int) color.Color {
+		if x < 100 {
+			return color.White
+		}
+		return color.Black
+	})
+	points = PoissonDiskPoints(b, 5, 3, &PoissonDiskOptions{Density: density, MaxRadius: 15})
+	if d := minSpacing(points); d < 5 {
+		t.Errorf("points are %v apart, want at least 5", d)
+	}
+	var left, right int
+	for _, p := range points {
+		if p.X < 100 {
+			left++
+		} else {
+			right++
+		}
+	}
+	if left < 5*right {
+		t.Errorf("%d points on the dense side and %d on the sparse side, want far more on the dense", left, right)
+	}
+}
+
+func TestSobolPointsStratified(t *testing.T) {
+	// Every power of two points of the sequence is a (0, m, 2)-net: each of the
+	// 8x8 squares holds exactly one of 64 points, however it is shifted.
+	for seed := uint64(0); seed < 4; seed++ {
+		var seen [8][8]int
+		for _, p := range SobolPoints(image.Rect(0, 0, 64, 64), 64, seed) {
+			seen[p.Y/8][p.X/8]++
+		}
+		for y := range seen {
+			for x, n := range seen[y] {
+				if n != 1 {
+					t.Fatalf("seed %d: square (%d, %d) holds %d points", seed, x, y, n)
+				}
+			}
+		}
+	}
+}
+
+func TestJitteredGridPoints(t *testing.T) {
+	b := image.Rect(0, 0, 100, 50)
+	if got := JitteredGridPoints(b, 10, 0, 1); len(got) != 50 || got[0] != (image.Point{5, 5}) || got[11] != (image.Point{15, 15}) {
+		t.Errorf("unjittered grid = %v, want 10 by 5 points at the square centres", got)
+	}
+	for _, p := range JitteredGridPoints(b, 10, 0.5, 1) {
+		if !p.In(b) {
+			t.Errorf("%v is outside %v", p, b)
+		}
+	}
+}
+
+func TestLloydRelax(t *testing.T) {
+	b := image.Rect(0, 0, 100, 100)
+	// Uniform random points clump, and relaxing them evens them out.
+	random := make([]image.Point, 50)
+	for i := range random {
+		h := StableHash(i, 0, 5)
+		random[i] = image.Point{int(h % 100), int(h >> 32 % 100)}
+	}
+	before := minSpacing(random)
+	relaxed := LloydRelax(random, b, 10, nil)
+	if d := minSpacing(relaxed); d < 3*before || d < 6 {
+		t.Errorf("relaxed points are %v apart, %v before, want them spread out", d, before)
+	}
+	if minSpacing(random) != before {
+		t.Error("LloydRelax changed its argument")
+	}
+
+	// With a density they gather where it is high.
+	density := NewGeneric(func(x, y int) color.Color {
+		if y < 30 {
+			return color.White
+		}
+		return color.Gray{Y: 10}
+	})
+	top := func(points []image.Point) int {
+		n := 0
+		for _, p := range points {
+			if p.Y < 30 {
+				n++
+			}
+		}
+		return n
+	}
+	grid := JitteredGridPoints(b, 10, 0.3, 2)
+	plain, weighted := top(LloydRelax(grid, b, 5, nil)), top(LloydRelax(grid, b, 5, density))
+	if weighted < plain+5 {
+		t.Errorf("%d points in the dense top with the density, %d without, want more with it", weighted, plain)
+	}
+}
+
+func TestScatterPoints(t *testing.T) {
+	points := []image.Point{{20, 20}, {60, 40}}
+	s := NewScatter(SetScatterPoints(points), SetScatterGenerator(func(u, v float64, hash uint64) (color.Color, float64) {
+		if u*u+v*v > 4 {
+			return color.Transparent, 0
+		}
+		return color.White, 0
+	}))
+	for _, p := range points {
+		if got := s.At(p.X, p.Y); !sameColor(got, color.White) {
+			t.Errorf("At(%v) = %v, want white", p, got)
+		}
+	}
+	if got := s.At(40, 30); !sameColor(got, color.Black) {
+		t.Errorf("At(40, 30) = %v, want the black background", got)
+	}
+}
+
+func TestPoissonDiskPointsSpacing(t *testing.T) {
+	// Placing points on whole pixels must not bring them closer than the radius.
+	points := PoissonDiskPoints(image.Rect(0, 0, 300, 200), 7, 1, nil)
+	if d := minSpacing(points); d < 7 {
+		t.Errorf("points are %v apart, want at least 7", d)
+	}
+}
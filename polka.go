@@ -3,10 +3,12 @@ package pattern
 import (
 	"image"
 	"image/color"
+	"math"
 )
 
-// Ensure Polka implements the image.Image interface.
+// Ensure Polka implements the image.Image and Sampler interfaces.
 var _ image.Image = (*Polka)(nil)
+var _ Sampler = (*Polka)(nil)
 
 // Polka is a pattern that displays a grid of circles (polka dots).
 type Polka struct {
@@ -15,6 +17,7 @@ type Polka struct {
 	Spacing
 	FillColor
 	SpaceColor
+	AntiAlias
 }
 
 func (p *Polka) ColorModel() color.Model {
@@ -26,6 +29,9 @@ func (p *Polka) Bounds() image.Rectangle {
 }
 
 func (p *Polka) At(x, y int) color.Color {
+	if p.AntiAlias.AntiAlias {
+		return p.SampleAt(float64(x)+0.5, float64(y)+0.5)
+	}
 	spacing := p.Spacing.Spacing
 	if spacing <= 0 {
 		return p.SpaceColor.SpaceColor
@@ -63,6 +69,35 @@ func (p *Polka) At(x, y int) color.Color {
 	return p.SpaceColor.SpaceColor
 }
 
+// SampleAt returns the colour at the continuous position (x, y).
+// With AntiAlias set, the dot edges are blended by coverage.
+func (p *Polka) SampleAt(x, y float64) color.Color {
+	spacing := p.Spacing.Spacing
+	if spacing <= 0 {
+		return p.SpaceColor.SpaceColor
+	}
+
+	// At treats the integer pixel position as the sample point, so shift back from the pixel centre.
+	s := float64(spacing)
+	u := x - 0.5
+	v := y - 0.5
+	dx := u - math.Floor(u/s)*s
+	dy := v - math.Floor(v/s)*s
+
+	c := float64(spacing / 2)
+	dist := math.Hypot(dx-c, dy-c)
+	radius := float64(p.Radius.Radius)
+
+	if p.AntiAlias.AntiAlias {
+		cov := edgeCoverage(radius - dist)
+		return mixCoverage([]color.Color{p.FillColor.FillColor, p.SpaceColor.SpaceColor}, []float64{cov, 1 - cov})
+	}
+	if dist < radius {
+		return p.FillColor.FillColor
+	}
+	return p.SpaceColor.SpaceColor
+}
+
 // NewPolka creates a new Polka pattern.
 // Default Radius is 10.
 // Default Spacing is 40.
@@ -89,3 +124,8 @@ func NewPolka(ops ...func(any)) image.Image {
 func NewDemoPolka(ops ...func(any)) image.Image {
 	return NewPolka(ops...)
 }
+
+func init() {
+	RegisterPattern(generatorType("polka", &Polka{}, NewPolka))
+	RegisterPattern(generatorType("demo_polka", nil, NewDemoPolka))
+}
@@ -93,3 +93,24 @@ func NewQuantize(img image.Image, levels int, ops ...func(any)) image.Image {
 	}
 	return p
 }
+
+func init() {
+	RegisterPattern(&PatternType{
+		Name:     "quantize",
+		Category: CategoryFilter,
+		Inputs:   []Input{{Name: "source"}},
+		Params: []Param{
+			{Name: "levels", Type: ParamInt, Default: 4, Min: 2, Max: 256, Doc: "Number of levels per channel."},
+		},
+		Sample: &Quantize{},
+		New: func(a *Args) (image.Image, error) {
+			return NewQuantize(a.Input("source"), a.Int("levels"), a.Options...), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			p := img.(*Quantize)
+			a.SetInput("source", p.img)
+			a.Set("levels", p.levels)
+			return nil
+		},
+	})
+}
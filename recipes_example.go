@@ -259,7 +259,7 @@ func ExampleNewStripe() image.Image {
 // Abstract Art: Renamed from Crystal (Original implementation)
 func ExampleNewAbstractArt() image.Image {
 	v := NewVoronoi(
-		makePoints(30, 150, 150),
+		PoissonDiskPoints(image.Rect(0, 0, 150, 150), 24, 50, nil),
 		[]color.Color{
 			color.RGBA{200, 220, 255, 255},
 			color.RGBA{100, 150, 250, 255},
@@ -593,19 +593,6 @@ func (p *Generic) ColorModel() color.Model { return color.RGBAModel }
 func NewGeneric(f func(x,y int) color.Color) image.Image { return &Generic{Func: f} }
 
 
-func makePoints(n, w, h int) []image.Point {
-	pts := make([]image.Point, n)
-	seed := 50
-	for i := 0; i < n; i++ {
-		seed = (seed * 1103515245 + 12345) & 0x7FFFFFFF
-		x := seed % w
-		seed = (seed * 1103515245 + 12345) & 0x7FFFFFFF
-		y := seed % h
-		pts[i] = image.Point{x, y}
-	}
-	return pts
-}
-
 func GenerateDungeon(rect image.Rectangle) image.Image {
 	return ExampleNewDungeon()
 }
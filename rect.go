@@ -3,10 +3,12 @@ package pattern
 import (
 	"image"
 	"image/color"
+	"math"
 )
 
-// Ensure Rect implements the image.Image interface.
+// Ensure Rect implements the image.Image and Sampler interfaces.
 var _ image.Image = (*Rect)(nil)
+var _ Sampler = (*Rect)(nil)
 
 // Rect is a pattern that draws a filled rectangle.
 type Rect struct {
@@ -15,6 +17,7 @@ type Rect struct {
 	LineSize
 	LineColor
 	LineImageSource
+	AntiAlias
 }
 
 func (r *Rect) At(x, y int) color.Color {
@@ -50,6 +53,69 @@ func (r *Rect) At(x, y int) color.Color {
 	return r.FillColor.FillColor
 }
 
+// SampleAt returns the colour at the continuous position (x, y).
+// With AntiAlias set, the coverage of the rectangle and its border over the one pixel
+// square centred on (x, y) is computed exactly, which matters when the rectangle is
+// sampled at fractional positions by Warp or Supersample.
+func (r *Rect) SampleAt(x, y float64) color.Color {
+	outer := r.bounds
+	inner := outer
+	if ls := r.LineSize.LineSize; ls > 0 {
+		inner = image.Rect(outer.Min.X+ls, outer.Min.Y+ls, outer.Max.X-ls, outer.Max.Y-ls)
+	}
+
+	var outerCov, innerCov float64
+	if r.AntiAlias.AntiAlias {
+		outerCov = boxCoverage(outer, x, y)
+		innerCov = boxCoverage(inner, x, y)
+	} else {
+		if pointInRect(outer, x, y) {
+			outerCov = 1
+		}
+		if pointInRect(inner, x, y) {
+			innerCov = 1
+		}
+	}
+
+	if outerCov == 0 {
+		return color.RGBA{}
+	}
+	var line color.Color = color.Black
+	if r.LineImageSource.LineImageSource != nil {
+		line = sampleImage(r.LineImageSource.LineImageSource, x, y)
+	} else if r.LineColor.LineColor != nil {
+		line = r.LineColor.LineColor
+	}
+	if outerCov == 1 && innerCov == 1 {
+		return r.FillColor.FillColor
+	}
+	if outerCov == 1 && innerCov == 0 {
+		return line
+	}
+	return mixCoverage(
+		[]color.Color{line, r.FillColor.FillColor},
+		[]float64{outerCov - innerCov, innerCov},
+	)
+}
+
+// pointInRect reports whether the continuous point (x, y) lies in r.
+func pointInRect(r image.Rectangle, x, y float64) bool {
+	return x >= float64(r.Min.X) && x < float64(r.Max.X) && y >= float64(r.Min.Y) && y < float64(r.Max.Y)
+}
+
+// boxCoverage returns the fraction of the one pixel square centred on (x, y) that lies in r.
+func boxCoverage(r image.Rectangle, x, y float64) float64 {
+	if r.Empty() {
+		return 0
+	}
+	ox := math.Min(x+0.5, float64(r.Max.X)) - math.Max(x-0.5, float64(r.Min.X))
+	oy := math.Min(y+0.5, float64(r.Max.Y)) - math.Max(y-0.5, float64(r.Min.Y))
+	if ox <= 0 || oy <= 0 {
+		return 0
+	}
+	return ox * oy
+}
+
 func (r *Rect) getLineColor(x, y int) color.Color {
 	if r.LineImageSource.LineImageSource != nil {
 		return r.LineImageSource.LineImageSource.At(x, y)
@@ -84,3 +150,8 @@ func NewRect(ops ...func(any)) image.Image {
 func NewDemoRect(ops ...func(any)) image.Image {
 	return NewRect(ops...)
 }
+
+func init() {
+	RegisterPattern(generatorType("rect", &Rect{}, NewRect))
+	RegisterPattern(generatorType("demo_rect", nil, NewDemoRect))
+}
@@ -1,6 +1,14 @@
 package pattern
 
-import "image"
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"maps"
+	"reflect"
+	"slices"
+	"sort"
+)
 
 var (
 	GlobalGenerators = make(map[string]func(image.Rectangle) image.Image)
@@ -14,3 +22,283 @@ func RegisterGenerator(name string, gen func(image.Rectangle) image.Image) {
 func RegisterReferences(name string, refs func() (map[string]func(image.Rectangle) image.Image, []string)) {
 	GlobalReferences[name] = refs
 }
+
+// Category groups registered pattern types by the role they play in a pipeline.
+type Category string
+
+const (
+	CategoryGenerator  Category = "generator"  // Produces an image from its parameters alone.
+	CategoryFilter     Category = "filter"     // Transforms a single input image.
+	CategoryDither     Category = "dither"     // Reduces an input image to a palette.
+	CategoryCompositor Category = "compositor" // Combines several input images.
+)
+
+// Input describes an input slot of a registered pattern type.
+type Input struct {
+	Name string
+	// Optional slots may be left unconnected.
+	Optional bool
+	// Variadic slots take any number of images.
+	Variadic bool
+}
+
+// PatternType is the descriptor of a registered pattern: its name, category, inputs and
+// parameter schema, and how to build it. The DSL, the CLI, the graph format and the
+// bootstrap generator all work from these descriptors.
+//
+// The shared Set* options are not listed as parameters; Describe reports which of them
+// apply to a pattern.
+type PatternType struct {
+	Name     string
+	Category Category
+	Inputs   []Input
+	Params   []Param
+	// Sample is a value of the concrete type New returns, used by PatternTypeOf.
+	Sample image.Image
+	// Match distinguishes pattern types that share a concrete type. It may be nil.
+	Match func(img image.Image) bool
+	// New builds the pattern. Build has already checked the inputs and filled every
+	// parameter with a value of its Go type.
+	New func(a *Args) (image.Image, error)
+	// Encode reads the inputs and parameters back from an existing pattern, so it can be
+	// saved as a graph. It may be nil for types with no inputs or parameters.
+	Encode func(img image.Image, a *Args) error
+}
+
+// Args holds the inputs, parameter values and options a PatternType is built with.
+type Args struct {
+	// Inputs holds the images connected to each input slot.
+	Inputs map[string][]image.Image
+	// Values holds parameter values by name.
+	Values map[string]any
+	// Options are passed on to the pattern's constructor.
+	Options []func(any)
+}
+
+// Input returns the first image connected to slot, or nil.
+func (a *Args) Input(slot string) image.Image {
+	if l := a.Inputs[slot]; len(l) > 0 {
+		return l[0]
+	}
+	return nil
+}
+
+// InputList returns every image connected to slot.
+func (a *Args) InputList(slot string) []image.Image {
+	return a.Inputs[slot]
+}
+
+// SetInput connects imgs to slot, skipping nil images.
+func (a *Args) SetInput(slot string, imgs ...image.Image) {
+	for _, img := range imgs {
+		if img == nil {
+			continue
+		}
+		if a.Inputs == nil {
+			a.Inputs = map[string][]image.Image{}
+		}
+		a.Inputs[slot] = append(a.Inputs[slot], img)
+	}
+}
+
+// Set sets a parameter value.
+func (a *Args) Set(name string, v any) {
+	if a.Values == nil {
+		a.Values = map[string]any{}
+	}
+	a.Values[name] = v
+}
+
+// The value getters return the zero value when a parameter is unset.
+
+func (a *Args) Int(name string) int              { v, _ := a.Values[name].(int); return v }
+func (a *Args) Float(name string) float64        { v, _ := a.Values[name].(float64); return v }
+func (a *Args) Bool(name string) bool            { v, _ := a.Values[name].(bool); return v }
+func (a *Args) String(name string) string        { v, _ := a.Values[name].(string); return v }
+func (a *Args) Color(name string) color.Color    { v, _ := a.Values[name].(color.Color); return v }
+func (a *Args) Colors(name string) []color.Color { v, _ := a.Values[name].([]color.Color); return v }
+func (a *Args) Floats(name string) []float64     { v, _ := a.Values[name].([]float64); return v }
+func (a *Args) Points(name string) []image.Point { v, _ := a.Values[name].([]image.Point); return v }
+func (a *Args) Rect(name string) image.Rectangle { v, _ := a.Values[name].(image.Rectangle); return v }
+func (a *Args) ColorStops(name string) []ColorStop {
+	v, _ := a.Values[name].([]ColorStop)
+	return v
+}
+
+// Enum returns the index of an enum parameter's value within names.
+func (a *Args) Enum(name string, names []string) int {
+	s := a.String(name)
+	for i, n := range names {
+		if n == s {
+			return i
+		}
+	}
+	return 0
+}
+
+// Param returns the named parameter, or nil.
+func (t *PatternType) Param(name string) *Param {
+	for i := range t.Params {
+		if t.Params[i].Name == name {
+			return &t.Params[i]
+		}
+	}
+	return nil
+}
+
+// Input returns the named input slot, or nil.
+func (t *PatternType) Input(name string) *Input {
+	for i := range t.Inputs {
+		if t.Inputs[i].Name == name {
+			return &t.Inputs[i]
+		}
+	}
+	return nil
+}
+
+// Arity returns the minimum and maximum number of input images. Max is -1 when a slot
+// is variadic.
+func (t *PatternType) Arity() (min, max int) {
+	for _, in := range t.Inputs {
+		if !in.Optional {
+			min++
+		}
+		if in.Variadic {
+			max = -1
+		} else if max >= 0 {
+			max++
+		}
+	}
+	return min, max
+}
+
+// Build checks a against the type's inputs and parameters, fills in default values and
+// builds the pattern. Values may be given as any form Param.Convert accepts.
+func (t *PatternType) Build(a Args) (img image.Image, err error) {
+	values := make(map[string]any, len(t.Params))
+	for _, name := range slices.Sorted(maps.Keys(a.Values)) {
+		p := t.Param(name)
+		if p == nil {
+			return nil, fmt.Errorf("%s: unknown parameter %q", t.Name, name)
+		}
+		cv, err := p.Convert(a.Values[name])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", t.Name, err)
+		}
+		values[name] = cv
+	}
+	for _, p := range t.Params {
+		if _, ok := values[p.Name]; !ok {
+			values[p.Name] = p.Default
+		}
+	}
+	for slot := range a.Inputs {
+		if t.Input(slot) == nil {
+			return nil, fmt.Errorf("%s: unknown input %q", t.Name, slot)
+		}
+	}
+	for _, in := range t.Inputs {
+		n := len(a.Inputs[in.Name])
+		if n == 0 && !in.Optional {
+			return nil, fmt.Errorf("%s: requires input %q", t.Name, in.Name)
+		}
+		if n > 1 && !in.Variadic {
+			return nil, fmt.Errorf("%s: input %q takes a single image, got %d", t.Name, in.Name, n)
+		}
+	}
+	a.Values = values
+
+	// Constructors panic on some invalid arguments; report those as errors.
+	defer func() {
+		if r := recover(); r != nil {
+			img, err = nil, fmt.Errorf("%s: %v", t.Name, r)
+		}
+	}()
+	return t.New(&a)
+}
+
+var (
+	patternTypes       = map[string]*PatternType{}
+	patternTypesByType = map[reflect.Type][]*PatternType{}
+)
+
+// RegisterPattern adds t to the pattern registry, replacing any type of the same name.
+func RegisterPattern(t *PatternType) {
+	patternTypes[t.Name] = t
+	if t.Sample != nil {
+		rt := reflect.TypeOf(t.Sample)
+		patternTypesByType[rt] = append(patternTypesByType[rt], t)
+	}
+}
+
+// LookupPattern returns the registered pattern type with the given name.
+func LookupPattern(name string) (*PatternType, bool) {
+	t, ok := patternTypes[name]
+	return t, ok
+}
+
+// Patterns returns every registered pattern type, sorted by name.
+func Patterns() []*PatternType {
+	out := make([]*PatternType, 0, len(patternTypes))
+	for _, t := range patternTypes {
+		out = append(out, t)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// PatternTypeOf returns the registered type of an existing pattern.
+func PatternTypeOf(img image.Image) (*PatternType, bool) {
+	for _, t := range patternTypesByType[reflect.TypeOf(img)] {
+		if t.Match == nil || t.Match(img) {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// generatorType describes a generator configured entirely by its Set* options.
+// A nil sample keeps it from PatternTypeOf, as for the NewDemo* patterns, which
+// share the concrete types of the patterns they show.
+func generatorType(name string, sample image.Image, newFn func(ops ...func(any)) image.Image) *PatternType {
+	return &PatternType{
+		Name:     name,
+		Category: CategoryGenerator,
+		Sample:   sample,
+		New: func(a *Args) (image.Image, error) {
+			return newFn(a.Options...), nil
+		},
+	}
+}
+
+// filterType describes a filter of a single "source" image configured by its Set* options.
+func filterType(name string, sample image.Image, newFn func(img image.Image, ops ...func(any)) image.Image, source func(img image.Image) image.Image) *PatternType {
+	return &PatternType{
+		Name:     name,
+		Category: CategoryFilter,
+		Inputs:   []Input{{Name: "source"}},
+		Sample:   sample,
+		New: func(a *Args) (image.Image, error) {
+			return newFn(a.Input("source"), a.Options...), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			a.SetInput("source", source(img))
+			return nil
+		},
+	}
+}
+
+// applyOps applies ops to p, for constructors that do not take options.
+func applyOps(p image.Image, ops []func(any)) image.Image {
+	for _, op := range ops {
+		op(p)
+	}
+	return p
+}
+
+func enumName(names []string, i int) string {
+	if i < 0 || i >= len(names) {
+		return fmt.Sprint(i)
+	}
+	return names[i]
+}
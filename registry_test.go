@@ -0,0 +1,158 @@
+package pattern
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPatternBuild(t *testing.T) {
+	brick, ok := LookupPattern("brick")
+	if !ok {
+		t.Fatal("brick is not registered")
+	}
+	img, err := brick.Build(Args{
+		Values:  map[string]any{"width": "30", "offset": 0.25},
+		Options: []func(any){SetMortarSize(2)},
+	})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	p := img.(*Brick)
+	if p.Width != 30 || p.Height != 20 || p.Offset != 0.25 || p.MortarSize != 2 {
+		t.Errorf("Expected width 30, default height 20, offset 0.25 and mortar 2, got %d, %d, %v and %d", p.Width, p.Height, p.Offset, p.MortarSize)
+	}
+}
+
+func TestPatternBuildErrors(t *testing.T) {
+	src := NewNull()
+	tests := []struct {
+		name string
+		typ  string
+		args Args
+		want string
+	}{
+		{"UnknownParam", "checker", Args{Values: map[string]any{"colour1": "red"}}, `unknown parameter "colour1"`},
+		{"BadColor", "checker", Args{Values: map[string]any{"color1": "#12"}}, `invalid colour "#12"`},
+		{"BadEnum", "worley_noise", Args{Values: map[string]any{"metric": "hexagon"}}, `expected one of euclidean, manhattan, chebyshev`},
+		{"Range", "quantize", Args{Inputs: map[string][]image.Image{"source": {src}}, Values: map[string]any{"levels": 1}}, `outside the range 2 to 256`},
+		{"NotInteger", "rotate", Args{Inputs: map[string][]image.Image{"source": {src}}, Values: map[string]any{"degrees": 1.5}}, `expected an integer`},
+		{"MissingInput", "rotate", Args{}, `requires input "source"`},
+		{"UnknownInput", "rotate", Args{Inputs: map[string][]image.Image{"src": {src}}}, `unknown input "src"`},
+		{"TooManyInputs", "rotate", Args{Inputs: map[string][]image.Image{"source": {src, src}}}, `takes a single image, got 2`},
+		{"Panic", "xor", Args{Inputs: map[string][]image.Image{"inputs": {src}}}, `requires exactly 2 inputs`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pt, ok := LookupPattern(tt.typ)
+			if !ok {
+				t.Fatalf("%s is not registered", tt.typ)
+			}
+			_, err := pt.Build(tt.args)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Expected error containing %q, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestPatternTypeOf(t *testing.T) {
+	in := []image.Image{NewNull(), NewNull()}
+	for img, want := range map[image.Image]string{
+		NewAnd(in):                            "and",
+		NewBitwiseAnd(in):                     "bitwise_and",
+		NewBitwiseNot(in[0]):                  "bitwise_not",
+		NewChecker(color.Black, nil):          "checker",
+		NewRotate(in[0], 90):                  "rotate",
+		NewErrorDiffusion(in[0], Stucki, nil): "error_diffusion",
+	} {
+		if pt, ok := PatternTypeOf(img); !ok || pt.Name != want {
+			t.Errorf("Expected %T to be %q, got %v", img, want, pt)
+		}
+	}
+	if _, ok := PatternTypeOf(NewGeneric(func(x, y int) color.Color { return color.Black })); ok {
+		t.Error("Expected Generic to have no registered type")
+	}
+}
+
+func TestPatternArity(t *testing.T) {
+	for name, want := range map[string][2]int{
+		"checker": {0, 0},
+		"rotate":  {1, 1},
+		"blend":   {2, 2},
+		"warp":    {1, 4},
+		"and":     {1, -1},
+		"brick":   {0, -1},
+	} {
+		pt, _ := LookupPattern(name)
+		if min, max := pt.Arity(); min != want[0] || max != want[1] {
+			t.Errorf("Expected %s to take %d to %d inputs, got %d to %d", name, want[0], want[1], min, max)
+		}
+	}
+}
+
+func TestPatternRegistry(t *testing.T) {
+	for _, pt := range Patterns() {
+		if pt.New == nil {
+			t.Errorf("%s has no constructor", pt.Name)
+		}
+		if pt.Sample != nil && len(pt.Inputs) > 0 && pt.Encode == nil {
+			t.Errorf("%s has inputs but cannot be encoded", pt.Name)
+		}
+		for _, p := range pt.Params {
+			if p.Default == nil {
+				continue
+			}
+			if v, err := p.Convert(p.Default); err != nil || !reflect.DeepEqual(v, p.Default) {
+				t.Errorf("%s: default of %q does not convert to itself: %v", pt.Name, p.Name, err)
+			}
+		}
+	}
+}
+
+func TestParamConvertStrings(t *testing.T) {
+	tests := []struct {
+		param Param
+		in    string
+		want  any
+	}{
+		{Param{Type: ParamInt}, "12", 12},
+		{Param{Type: ParamFloat}, "0.5", 0.5},
+		{Param{Type: ParamBool}, "true", true},
+		{Param{Type: ParamColor}, "#f00", color.RGBA{255, 0, 0, 255}},
+		{Param{Type: ParamColors}, "black, #ffffff80", []color.Color{color.RGBA{0, 0, 0, 255}, color.NRGBA{255, 255, 255, 128}}},
+		{Param{Type: ParamFloats}, "1,2.5", []float64{1, 2.5}},
+		{Param{Type: ParamPoints}, "1:2,3:4", []image.Point{{1, 2}, {3, 4}}},
+		{Param{Type: ParamRect}, "0,0,10,20", image.Rect(0, 0, 10, 20)},
+		{Param{Type: ParamColorStops}, "0:black,1:white", []ColorStop{{0, color.RGBA{0, 0, 0, 255}}, {1, color.RGBA{255, 255, 255, 255}}}},
+	}
+	for _, tt := range tests {
+		got, err := tt.param.Convert(tt.in)
+		if err != nil {
+			t.Errorf("Convert(%q) as %s failed: %v", tt.in, tt.param.Type, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Convert(%q) as %s: expected %v, got %v", tt.in, tt.param.Type, tt.want, got)
+		}
+	}
+}
+
+func TestWarpBuildBounds(t *testing.T) {
+	src := NewChecker(color.Black, color.White, SetBounds(image.Rect(0, 0, 40, 40)))
+	b := image.Rect(5, 5, 25, 30)
+	direct := NewWarp(src, SetBounds(b))
+	pt, _ := LookupPattern("warp")
+	built, err := pt.Build(Args{Inputs: map[string][]image.Image{"source": {src}}, Options: []func(any){SetBounds(b)}})
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if direct.Bounds() != b || built.Bounds() != b {
+		t.Errorf("Expected both to take the bounds set, got %v directly and %v from the registry", direct.Bounds(), built.Bounds())
+	}
+	if got := NewWarp(src).Bounds(); got != src.Bounds() {
+		t.Errorf("Expected the source bounds without options, got %v", got)
+	}
+}
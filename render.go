@@ -0,0 +1,219 @@
+package pattern
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"image/draw"
+	"runtime"
+	"sync"
+)
+
+// RenderProgressFunc is called by Render after each tile has been evaluated.
+// done is the number of completed tiles and total is the number of tiles in the render.
+// Calls are serialised, so the callback does not need to be safe for concurrent use.
+type RenderProgressFunc func(done, total int)
+
+// RenderDepth selects the pixel format of the image produced by Render.
+type RenderDepth int
+
+const (
+	// RenderDepth8 renders into an *image.RGBA.
+	RenderDepth8 RenderDepth = iota
+	// RenderDepth16 renders into an *image.RGBA64, preserving 16-bit precision.
+	RenderDepth16
+)
+
+// Renderer holds the configuration used by Render.
+// It is configured with the same functional options as the patterns.
+type Renderer struct {
+	TileSize
+	Workers
+	Depth    RenderDepth
+	Progress RenderProgressFunc
+
+	bounds    image.Rectangle
+	hasBounds bool
+}
+
+// SetBounds overrides the region that is rendered. By default the bounds of the source image are used.
+func (r *Renderer) SetBounds(bounds image.Rectangle) {
+	r.bounds = bounds
+	r.hasBounds = true
+}
+
+// SetRenderDepth sets the pixel format of the rendered image.
+func (r *Renderer) SetRenderDepth(v RenderDepth) {
+	r.Depth = v
+}
+
+// SetRenderProgress sets the progress callback.
+func (r *Renderer) SetRenderProgress(v RenderProgressFunc) {
+	r.Progress = v
+}
+
+type hasRenderDepth interface {
+	SetRenderDepth(RenderDepth)
+}
+
+// SetRenderDepth creates an option to set the pixel format used by Render.
+func SetRenderDepth(v RenderDepth) func(any) {
+	return func(i any) {
+		if h, ok := i.(hasRenderDepth); ok {
+			h.SetRenderDepth(v)
+		}
+	}
+}
+
+type hasRenderProgress interface {
+	SetRenderProgress(RenderProgressFunc)
+}
+
+// SetRenderProgress creates an option to receive progress callbacks from Render.
+func SetRenderProgress(v RenderProgressFunc) func(any) {
+	return func(i any) {
+		if h, ok := i.(hasRenderProgress); ok {
+			h.SetRenderProgress(v)
+		}
+	}
+}
+
+// NewRenderer creates a Renderer with the default tile size and one worker per CPU.
+func NewRenderer(ops ...func(any)) *Renderer {
+	r := &Renderer{
+		TileSize: TileSize{TileSize: 64},
+		Workers:  Workers{Workers: runtime.GOMAXPROCS(0)},
+	}
+	for _, op := range ops {
+		op(r)
+	}
+	return r
+}
+
+// Render rasterises img into an *image.RGBA (or *image.RGBA64 with SetRenderDepth(RenderDepth16)).
+// The bounds are split into square tiles which are evaluated concurrently by a pool of workers,
+// so the source pattern must be safe for concurrent calls to At.
+// If ctx is cancelled before all tiles are complete, Render returns ctx.Err().
+func Render(ctx context.Context, img image.Image, ops ...func(any)) (draw.Image, error) {
+	return NewRenderer(ops...).Render(ctx, img)
+}
+
+// Render rasterises img using the renderer's configuration. See the package level Render.
+func (r *Renderer) Render(ctx context.Context, img image.Image) (draw.Image, error) {
+	bounds := img.Bounds()
+	if r.hasBounds {
+		bounds = r.bounds
+	}
+
+	var dst draw.Image
+	switch r.Depth {
+	case RenderDepth16:
+		dst = image.NewRGBA64(bounds)
+	default:
+		dst = image.NewRGBA(bounds)
+	}
+
+	if err := r.RenderInto(ctx, dst, img); err != nil {
+		return nil, err
+	}
+	return dst, nil
+}
+
+// RenderInto evaluates img over the bounds of dst, writing each pixel with draw.Src semantics.
+func (r *Renderer) RenderInto(ctx context.Context, dst draw.Image, img image.Image) error {
+	bounds := dst.Bounds()
+	tiles := splitTiles(bounds, r.TileSize.TileSize)
+	if len(tiles) == 0 {
+		return ctx.Err()
+	}
+
+	workers := r.Workers.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(tiles) {
+		workers = len(tiles)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	jobs := make(chan image.Rectangle)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	done := 0
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for tile := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				renderTile(dst, img, tile)
+				if r.Progress != nil {
+					mu.Lock()
+					done++
+					r.Progress(done, len(tiles))
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+feed:
+	for _, tile := range tiles {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- tile:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return ctx.Err()
+}
+
+// renderTile copies one tile of src into dst. Distinct tiles of an *image.RGBA or
+// *image.RGBA64 never share bytes, so tiles can be written concurrently.
+func renderTile(dst draw.Image, src image.Image, tile image.Rectangle) {
+	switch d := dst.(type) {
+	case *image.RGBA:
+		for y := tile.Min.Y; y < tile.Max.Y; y++ {
+			for x := tile.Min.X; x < tile.Max.X; x++ {
+				d.SetRGBA(x, y, color.RGBAModel.Convert(src.At(x, y)).(color.RGBA))
+			}
+		}
+	case *image.RGBA64:
+		for y := tile.Min.Y; y < tile.Max.Y; y++ {
+			for x := tile.Min.X; x < tile.Max.X; x++ {
+				d.SetRGBA64(x, y, color.RGBA64Model.Convert(src.At(x, y)).(color.RGBA64))
+			}
+		}
+	default:
+		for y := tile.Min.Y; y < tile.Max.Y; y++ {
+			for x := tile.Min.X; x < tile.Max.X; x++ {
+				d.Set(x, y, src.At(x, y))
+			}
+		}
+	}
+}
+
+// splitTiles divides r into square tiles of the given size, row by row.
+func splitTiles(r image.Rectangle, size int) []image.Rectangle {
+	if r.Empty() {
+		return nil
+	}
+	if size <= 0 {
+		size = 64
+	}
+	var tiles []image.Rectangle
+	for y := r.Min.Y; y < r.Max.Y; y += size {
+		for x := r.Min.X; x < r.Max.X; x += size {
+			tiles = append(tiles, image.Rect(x, y, x+size, y+size).Intersect(r))
+		}
+	}
+	return tiles
+}
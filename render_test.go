@@ -0,0 +1,79 @@
+package pattern
+
+import (
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+func TestRenderMatchesDraw(t *testing.T) {
+	src := NewChecker(color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 128}, SetBounds(image.Rect(-7, 3, 150, 97)))
+
+	want := image.NewRGBA(src.Bounds())
+	draw.Draw(want, want.Bounds(), src, src.Bounds().Min, draw.Src)
+
+	got, err := Render(context.Background(), src, SetTileSize(16), SetWorkers(3))
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	rgba, ok := got.(*image.RGBA)
+	if !ok {
+		t.Fatalf("Expected *image.RGBA, got %T", got)
+	}
+	if rgba.Bounds() != want.Bounds() {
+		t.Fatalf("Expected bounds %v, got %v", want.Bounds(), rgba.Bounds())
+	}
+	for i := range want.Pix {
+		if want.Pix[i] != rgba.Pix[i] {
+			t.Fatalf("Pixel data differs at byte %d: %d vs %d", i, rgba.Pix[i], want.Pix[i])
+		}
+	}
+}
+
+func TestRenderDepth16(t *testing.T) {
+	src := NewLinearGradient(SetBounds(image.Rect(0, 0, 40, 10)))
+	got, err := Render(context.Background(), src, SetRenderDepth(RenderDepth16))
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if _, ok := got.(*image.RGBA64); !ok {
+		t.Fatalf("Expected *image.RGBA64, got %T", got)
+	}
+}
+
+func TestRenderBoundsAndProgress(t *testing.T) {
+	src := NewNull()
+	r := image.Rect(0, 0, 100, 50)
+
+	var last, total int
+	calls := 0
+	got, err := Render(context.Background(), src,
+		SetBounds(r),
+		SetTileSize(25),
+		SetRenderProgress(func(done, n int) {
+			calls++
+			last, total = done, n
+		}),
+	)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if got.Bounds() != r {
+		t.Errorf("Expected bounds %v, got %v", r, got.Bounds())
+	}
+	if total != 8 || last != 8 || calls != 8 {
+		t.Errorf("Expected 8 progress calls ending at 8/8, got %d calls ending at %d/%d", calls, last, total)
+	}
+}
+
+func TestRenderCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := Render(ctx, NewNull())
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
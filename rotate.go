@@ -5,8 +5,9 @@ import (
 	"image/color"
 )
 
-// Ensure Rotate implements the image.Image interface.
+// Ensure Rotate implements the image.Image and Sampler interfaces.
 var _ image.Image = (*Rotate)(nil)
+var _ Sampler = (*Rotate)(nil)
 
 // Rotate is a pattern that rotates an underlying image by 90, 180, or 270 degrees.
 type Rotate struct {
@@ -73,6 +74,35 @@ func (r *Rotate) At(x, y int) color.Color {
 	return r.img.At(b.Min.X+sx, b.Min.Y+sy)
 }
 
+// SampleAt returns the colour at the continuous position (x, y), rotating the
+// coordinates and sampling the source with SampleAt when it supports it.
+func (r *Rotate) SampleAt(x, y float64) color.Color {
+	b := r.img.Bounds()
+	min := r.Bounds().Min
+	dx := x - float64(min.X)
+	dy := y - float64(min.Y)
+	w := float64(b.Dx())
+	h := float64(b.Dy())
+
+	var sx, sy float64
+	switch r.degrees {
+	case 90:
+		sx = dy
+		sy = h - dx
+	case 180:
+		sx = w - dx
+		sy = h - dy
+	case 270:
+		sx = w - dy
+		sy = dx
+	default:
+		sx = dx
+		sy = dy
+	}
+
+	return sampleImage(r.img, float64(b.Min.X)+sx, float64(b.Min.Y)+sy)
+}
+
 // NewRotate creates a new Rotate from an existing image.
 // degrees: 90, 180, 270 (values are normalized to these).
 func NewRotate(img image.Image, degrees int, ops ...func(any)) image.Image {
@@ -89,3 +119,24 @@ func NewRotate(img image.Image, degrees int, ops ...func(any)) image.Image {
 	}
 	return r
 }
+
+func init() {
+	RegisterPattern(&PatternType{
+		Name:     "rotate",
+		Category: CategoryFilter,
+		Inputs:   []Input{{Name: "source"}},
+		Params: []Param{
+			{Name: "degrees", Type: ParamInt, Default: 90, Doc: "Clockwise rotation: 90, 180 or 270."},
+		},
+		Sample: &Rotate{},
+		New: func(a *Args) (image.Image, error) {
+			return NewRotate(a.Input("source"), a.Int("degrees"), a.Options...), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			p := img.(*Rotate)
+			a.SetInput("source", p.img)
+			a.Set("degrees", p.degrees)
+			return nil
+		},
+	})
+}
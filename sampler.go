@@ -0,0 +1,161 @@
+package pattern
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// Sampler is implemented by patterns that can be evaluated at fractional coordinates.
+// The pixel At(x, y) covers the square [x, x+1) × [y, y+1), so SampleAt(x+0.5, y+0.5)
+// samples its centre and, for patterns with hard edges, returns the same colour as At(x, y).
+type Sampler interface {
+	SampleAt(x, y float64) color.Color
+}
+
+// sampleImage samples img at the continuous position (x, y).
+// Samplers are evaluated exactly; other images return the pixel containing the point.
+func sampleImage(img image.Image, x, y float64) color.Color {
+	if s, ok := img.(Sampler); ok {
+		return s.SampleAt(x, y)
+	}
+	return img.At(int(math.Floor(x)), int(math.Floor(y)))
+}
+
+// Ensure Supersample implements the image.Image and Sampler interfaces.
+var _ image.Image = (*Supersample)(nil)
+var _ Sampler = (*Supersample)(nil)
+
+// Supersample anti-aliases a Sampler by averaging an n×n grid of samples over each pixel.
+// Sources that do not implement Sampler are passed through unchanged.
+type Supersample struct {
+	Null
+	Source  image.Image
+	Samples int
+}
+
+func (s *Supersample) ColorModel() color.Model {
+	return color.RGBA64Model
+}
+
+func (s *Supersample) At(x, y int) color.Color {
+	if _, ok := s.Source.(Sampler); !ok {
+		if s.Source == nil {
+			return color.RGBA{}
+		}
+		return s.Source.At(x, y)
+	}
+	return s.SampleAt(float64(x)+0.5, float64(y)+0.5)
+}
+
+// SampleAt returns the average of the source over the one pixel square centred on (x, y).
+func (s *Supersample) SampleAt(x, y float64) color.Color {
+	if s.Source == nil {
+		return color.RGBA{}
+	}
+	n := s.Samples
+	if n < 1 {
+		n = 1
+	}
+	step := 1.0 / float64(n)
+	var r, g, b, a float64
+	for j := 0; j < n; j++ {
+		for i := 0; i < n; i++ {
+			sx := x - 0.5 + (float64(i)+0.5)*step
+			sy := y - 0.5 + (float64(j)+0.5)*step
+			cr, cg, cb, ca := sampleImage(s.Source, sx, sy).RGBA()
+			r += float64(cr)
+			g += float64(cg)
+			b += float64(cb)
+			a += float64(ca)
+		}
+	}
+	total := float64(n * n)
+	return color.RGBA64{
+		R: uint16(r/total + 0.5),
+		G: uint16(g/total + 0.5),
+		B: uint16(b/total + 0.5),
+		A: uint16(a/total + 0.5),
+	}
+}
+
+// NewSupersample creates a Supersample pattern taking n×n samples per pixel.
+// The bounds default to those of the source.
+func NewSupersample(img image.Image, n int, ops ...func(any)) image.Image {
+	b := image.Rect(0, 0, 255, 255)
+	if img != nil {
+		b = img.Bounds()
+	}
+	p := &Supersample{
+		Null: Null{
+			bounds: b,
+		},
+		Source:  img,
+		Samples: n,
+	}
+	for _, op := range ops {
+		op(p)
+	}
+	return p
+}
+
+// bandCoverage returns the fraction of [a, b] covered by the repeating bands
+// [k*period, k*period+size) for integer k.
+func bandCoverage(a, b, size, period float64) float64 {
+	if b <= a || period <= 0 {
+		return 0
+	}
+	cumulative := func(t float64) float64 {
+		k := math.Floor(t / period)
+		return k*size + math.Min(t-k*period, size)
+	}
+	return (cumulative(b) - cumulative(a)) / (b - a)
+}
+
+// edgeCoverage approximates the coverage of a pixel whose centre is dist pixels inside
+// an edge (negative when outside), using a one pixel wide linear ramp.
+func edgeCoverage(dist float64) float64 {
+	return clamp01(dist + 0.5)
+}
+
+// mixCoverage blends colours weighted by their coverage. Nil colours are transparent.
+func mixCoverage(colors []color.Color, weights []float64) color.Color {
+	var r, g, b, a float64
+	for i, c := range colors {
+		if c == nil || weights[i] <= 0 {
+			continue
+		}
+		cr, cg, cb, ca := c.RGBA()
+		r += float64(cr) * weights[i]
+		g += float64(cg) * weights[i]
+		b += float64(cb) * weights[i]
+		a += float64(ca) * weights[i]
+	}
+	return color.RGBA64{
+		R: uint16(clampFloatRange(r+0.5, 0, 65535)),
+		G: uint16(clampFloatRange(g+0.5, 0, 65535)),
+		B: uint16(clampFloatRange(b+0.5, 0, 65535)),
+		A: uint16(clampFloatRange(a+0.5, 0, 65535)),
+	}
+}
+
+func init() {
+	RegisterPattern(&PatternType{
+		Name:     "supersample",
+		Category: CategoryFilter,
+		Inputs:   []Input{{Name: "source"}},
+		Params: []Param{
+			{Name: "samples", Type: ParamInt, Default: 4, Min: 1, Max: 16, Doc: "Samples per axis within each pixel."},
+		},
+		Sample: &Supersample{},
+		New: func(a *Args) (image.Image, error) {
+			return NewSupersample(a.Input("source"), a.Int("samples"), a.Options...), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			p := img.(*Supersample)
+			a.SetInput("source", p.Source)
+			a.Set("samples", p.Samples)
+			return nil
+		},
+	})
+}
@@ -0,0 +1,108 @@
+package pattern
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func sameColor(a, b color.Color) bool {
+	r1, g1, b1, a1 := a.RGBA()
+	r2, g2, b2, a2 := b.RGBA()
+	return r1 == r2 && g1 == g2 && b1 == b2 && a1 == a2
+}
+
+func TestSampleAtMatchesAtAtPixelCentres(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	patterns := map[string]image.Image{
+		"Checker":        NewChecker(red, blue),
+		"Circle":         NewCircle(SetLineSize(5), SetLineColor(red), SetFillColor(blue), SetBounds(image.Rect(0, 0, 41, 30))),
+		"Polka":          NewPolka(SetRadius(6), SetSpacing(17)),
+		"Rect":           NewRect(SetLineSize(3), SetLineColor(red), SetBounds(image.Rect(2, 2, 30, 20))),
+		"HexGrid":        NewHexGrid(SetRadius(9)),
+		"Brick":          NewBrick(SetBrickSize(13, 7), SetMortarSize(3)),
+		"HorizontalLine": NewHorizontalLine(SetLineSize(3), SetSpaceSize(4), SetPhase(2)),
+		"VerticalLine":   NewVerticalLine(SetLineSize(2), SetSpaceSize(5)),
+		"Rotate":         NewRotate(NewChecker(red, blue, SetBounds(image.Rect(0, 0, 30, 20))), 90),
+	}
+	for name, p := range patterns {
+		s, ok := p.(Sampler)
+		if !ok {
+			t.Errorf("%s does not implement Sampler", name)
+			continue
+		}
+		r := p.Bounds().Intersect(image.Rect(0, 0, 45, 40))
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			for x := r.Min.X; x < r.Max.X; x++ {
+				if !sameColor(p.At(x, y), s.SampleAt(float64(x)+0.5, float64(y)+0.5)) {
+					t.Fatalf("%s: At(%d, %d) and SampleAt at the pixel centre differ", name, x, y)
+				}
+			}
+		}
+	}
+}
+
+func TestCheckerAntiAliasCoverage(t *testing.T) {
+	c := NewChecker(color.White, color.Black, SetSpaceSize(10), SetAntiAlias(true)).(*Checker)
+
+	// Straddling a vertical edge at x=10 gives a half and half mix.
+	got := color.RGBA64Model.Convert(c.SampleAt(10, 5)).(color.RGBA64)
+	if got.R < 32000 || got.R > 33600 {
+		t.Errorf("Expected a mid grey at the edge, got %v", got)
+	}
+
+	// Whole pixels within a cell are unaffected.
+	if !sameColor(c.At(3, 3), color.White) {
+		t.Errorf("Expected white inside the first cell, got %v", c.At(3, 3))
+	}
+}
+
+func TestCircleAntiAlias(t *testing.T) {
+	b := image.Rect(0, 0, 40, 40)
+	hard := NewCircle(SetFillColor(color.White), SetSpaceColor(color.Black), SetBounds(b))
+	soft := NewCircle(SetFillColor(color.White), SetSpaceColor(color.Black), SetBounds(b), SetAntiAlias(true))
+
+	partial := 0
+	for x := 0; x < 40; x++ {
+		r, _, _, _ := soft.At(x, 20+int(float64(x)/3)).RGBA()
+		if r > 0 && r < 0xffff {
+			partial++
+		}
+	}
+	if partial == 0 {
+		t.Error("Expected anti-aliased circle to contain partially covered pixels")
+	}
+	if !sameColor(hard.At(20, 20), soft.At(20, 20)) {
+		t.Error("Expected the circle centre to be unaffected by anti-aliasing")
+	}
+}
+
+func TestSupersample(t *testing.T) {
+	src := NewPolka(SetRadius(5), SetSpacing(16), SetFillColor(color.White), SetSpaceColor(color.Black))
+	ss := NewSupersample(src, 4)
+
+	if ss.Bounds() != src.Bounds() {
+		t.Errorf("Expected bounds %v, got %v", src.Bounds(), ss.Bounds())
+	}
+
+	partial := 0
+	for y := 0; y < 16; y++ {
+		for x := 0; x < 16; x++ {
+			r, _, _, _ := ss.At(x, y).RGBA()
+			if r > 0 && r < 0xffff {
+				partial++
+			}
+		}
+	}
+	if partial == 0 {
+		t.Error("Expected supersampled dots to have partially covered edge pixels")
+	}
+
+	// Non-samplers are passed through.
+	img := image.NewRGBA(image.Rect(0, 0, 2, 2))
+	img.Set(1, 1, color.White)
+	if !sameColor(NewSupersample(img, 4).At(1, 1), color.White) {
+		t.Error("Expected non-Sampler source to pass through")
+	}
+}
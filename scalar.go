@@ -0,0 +1,113 @@
+package pattern
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+)
+
+// ScalarField is a continuous two dimensional field of real values.
+// Coordinates are in the same pixel space as image.Image.At, but may be fractional.
+// Values are nominally in [0, 1], matching the luminance the pattern would render,
+// but are not clamped or quantised, so consumers such as NormalMap, Warp and ColorMap
+// can work at full precision instead of round tripping through color.Gray.
+type ScalarField interface {
+	ValueAt(x, y float64) float64
+}
+
+// ScalarFieldFunc adapts an ordinary function to the ScalarField interface.
+type ScalarFieldFunc func(x, y float64) float64
+
+// ValueAt calls f(x, y).
+func (f ScalarFieldFunc) ValueAt(x, y float64) float64 {
+	return f(x, y)
+}
+
+// ScalarFieldOf returns img as a ScalarField.
+// Images that already implement ScalarField are returned unchanged; any other image
+// is sampled by the luminance of the pixel containing (x, y).
+func ScalarFieldOf(img image.Image) ScalarField {
+	if f, ok := img.(ScalarField); ok {
+		return f
+	}
+	return &imageScalarField{img: img}
+}
+
+// imageScalarField samples the luminance of an ordinary image.
+type imageScalarField struct {
+	img image.Image
+}
+
+func (f *imageScalarField) ValueAt(x, y float64) float64 {
+	return getLuminanceForMaterial(f.img.At(int(math.Floor(x)), int(math.Floor(y))))
+}
+
+// Ensure ScalarImage implements the image.Image and ScalarField interfaces.
+var _ image.Image = (*ScalarImage)(nil)
+var _ ScalarField = (*ScalarImage)(nil)
+
+// ScalarImage renders a ScalarField as a 16-bit grayscale image.
+// It also passes the field through, so full precision is kept when the image is
+// consumed by a pattern that understands ScalarField.
+type ScalarImage struct {
+	Null
+	Field ScalarField
+}
+
+func (s *ScalarImage) ColorModel() color.Model {
+	return color.Gray16Model
+}
+
+func (s *ScalarImage) At(x, y int) color.Color {
+	if s.Field == nil {
+		return color.Gray16{}
+	}
+	v := clamp01(s.Field.ValueAt(float64(x), float64(y)))
+	return color.Gray16{Y: uint16(v*65535 + 0.5)}
+}
+
+// ValueAt returns the value of the underlying field.
+func (s *ScalarImage) ValueAt(x, y float64) float64 {
+	if s.Field == nil {
+		return 0
+	}
+	return s.Field.ValueAt(x, y)
+}
+
+// NewScalarImage creates an image from a ScalarField.
+func NewScalarImage(field ScalarField, ops ...func(any)) image.Image {
+	p := &ScalarImage{
+		Null: Null{
+			bounds: image.Rect(0, 0, 255, 255),
+		},
+		Field: field,
+	}
+	for _, op := range ops {
+		op(p)
+	}
+	return p
+}
+
+func init() {
+	RegisterPattern(&PatternType{
+		Name:     "scalar_image",
+		Category: CategoryFilter,
+		Inputs:   []Input{{Name: "field"}},
+		Sample:   &ScalarImage{},
+		New: func(a *Args) (image.Image, error) {
+			return NewScalarImage(ScalarFieldOf(a.Input("field")), a.Options...), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			switch f := img.(*ScalarImage).Field.(type) {
+			case *imageScalarField:
+				a.SetInput("field", f.img)
+			case image.Image:
+				a.SetInput("field", f)
+			default:
+				return fmt.Errorf("scalar field %T cannot be serialised", f)
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,91 @@
+package pattern
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestPerlinValueAtMatchesAt(t *testing.T) {
+	p := &PerlinNoise{Seed: 7, Octaves: 3}
+	for y := 0; y < 20; y++ {
+		for x := 0; x < 20; x++ {
+			want := p.At(x, y).(color.Gray).Y
+			got := uint8(clamp01(p.ValueAt(float64(x), float64(y))) * 255)
+			if got != want {
+				t.Fatalf("At(%d, %d) = %d but ValueAt quantises to %d", x, y, want, got)
+			}
+		}
+	}
+}
+
+func TestPerlinValueAtIsContinuous(t *testing.T) {
+	p := &PerlinNoise{Seed: 7, Frequency: 0.05}
+	a := p.ValueAt(10, 10)
+	b := p.ValueAt(10.25, 10)
+	if a == b {
+		t.Errorf("Expected fractional coordinates to produce a different value, got %v for both", a)
+	}
+}
+
+func TestScalarFieldOf(t *testing.T) {
+	n := NewNoise(SetNoiseAlgorithm(&PerlinNoise{Seed: 1}))
+	if f := ScalarFieldOf(n); f != n.(ScalarField) {
+		t.Errorf("Expected ScalarFieldOf to return the Noise pattern unchanged")
+	}
+
+	img := image.NewGray(image.Rect(0, 0, 2, 2))
+	img.SetGray(1, 1, color.Gray{Y: 255})
+	f := ScalarFieldOf(img)
+	if v := f.ValueAt(1.5, 1.9); math.Abs(v-1) > 1e-9 {
+		t.Errorf("Expected 1 for white pixel, got %v", v)
+	}
+	if v := f.ValueAt(0.5, 0.5); v != 0 {
+		t.Errorf("Expected 0 for black pixel, got %v", v)
+	}
+}
+
+func TestScalarImage(t *testing.T) {
+	field := ScalarFieldFunc(func(x, y float64) float64 { return x / 100 })
+	img := NewScalarImage(field, SetBounds(image.Rect(0, 0, 100, 1)))
+
+	g := img.At(50, 0).(color.Gray16)
+	if g.Y != 32768 {
+		t.Errorf("Expected 16-bit value 32768, got %d", g.Y)
+	}
+	if v := img.(ScalarField).ValueAt(150, 0); v != 1.5 {
+		t.Errorf("Expected unclamped value 1.5, got %v", v)
+	}
+}
+
+func TestHeatmapValueAt(t *testing.T) {
+	h := NewHeatmap(func(x, y float64) float64 { return x }, SetBounds(image.Rect(0, 0, 10, 10))).(*Heatmap)
+	if v := h.ValueAt(0, 0); v != 0 {
+		t.Errorf("Expected 0 at the left edge, got %v", v)
+	}
+	if v := h.ValueAt(5, 0); math.Abs(v-0.5) > 1e-9 {
+		t.Errorf("Expected 0.5 at the centre, got %v", v)
+	}
+}
+
+func TestHeatmapValueAtIsColorLuminance(t *testing.T) {
+	red, blue := color.RGBA{255, 0, 0, 255}, color.RGBA{0, 0, 255, 255}
+	h := NewHeatmap(func(x, y float64) float64 { return x }, SetBounds(image.Rect(0, 0, 10, 10)), SetStartColor(red), SetEndColor(blue)).(*Heatmap)
+	for _, x := range []int{0, 3, 5, 9} {
+		want := getLuminanceForMaterial(h.At(x, 0))
+		if v := h.ValueAt(float64(x), 0); math.Abs(v-want) > 1e-4 {
+			t.Errorf("ValueAt(%d, 0) = %v, want the luminance %v of the colour drawn", x, v, want)
+		}
+	}
+}
+
+func TestWarpClampsScalarDistortion(t *testing.T) {
+	source := NewGeneric(func(x, y int) color.Color { return color.Gray{Y: uint8(x)} })
+	// A field beyond [0, 1] displaces no further than the scale.
+	field := NewScalarImage(ScalarFieldFunc(func(x, y float64) float64 { return 3 }))
+	w := NewWarp(source, WarpDistortionX(field), WarpScale(10))
+	if got := w.At(20, 0).(color.Gray).Y; got != 30 {
+		t.Errorf("At(20, 0) = %d, want the source 10 pixels along at 30", got)
+	}
+}
@@ -111,3 +111,44 @@ func NewScale(img image.Image, opts ...ScaleOption) image.Image {
 
 	return &ScaledImage{Image: dst}
 }
+
+// scalerNames names the interpolators a registered scale can use, in the order of
+// scalers.
+var scalerNames = []string{"nearest", "approx_bilinear", "bilinear", "catmull_rom"}
+
+var scalers = []draw.Scaler{draw.NearestNeighbor, draw.ApproxBiLinear, draw.BiLinear, draw.CatmullRom}
+
+func init() {
+	// NewScale renders the source when it is called, keeping none of its
+	// settings, so scaled images are not saved.
+	RegisterPattern(&PatternType{
+		Name:     "scale",
+		Category: CategoryFilter,
+		Inputs:   []Input{{Name: "source"}},
+		Params: []Param{
+			{Name: "width", Type: ParamInt, Default: 0, Doc: "Width of the result; 0 scales the source width by scale_x."},
+			{Name: "height", Type: ParamInt, Default: 0, Doc: "Height of the result; 0 scales the source height by scale_y."},
+			{Name: "scale_x", Type: ParamFloat, Default: 1.0, Doc: "Horizontal scale factor."},
+			{Name: "scale_y", Type: ParamFloat, Default: 1.0, Doc: "Vertical scale factor."},
+			{Name: "scaler", Type: ParamEnum, Default: "catmull_rom", Values: scalerNames, Doc: "Interpolation."},
+		},
+		New: func(a *Args) (image.Image, error) {
+			opts := []ScaleOption{
+				ScaleX(a.Float("scale_x")), ScaleY(a.Float("scale_y")),
+				ScaleUsing(scalers[a.Enum("scaler", scalerNames)]),
+			}
+			if w, h := a.Int("width"), a.Int("height"); w != 0 || h != 0 {
+				// NewScale only scales by the factors when neither size is set.
+				src := a.Input("source").Bounds()
+				if w == 0 {
+					w = int(float64(src.Dx()) * a.Float("scale_x"))
+				}
+				if h == 0 {
+					h = int(float64(src.Dy()) * a.Float("scale_y"))
+				}
+				opts = append(opts, ScaleToSize(w, h))
+			}
+			return NewScale(a.Input("source"), opts...), nil
+		},
+	})
+}
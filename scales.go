@@ -154,3 +154,7 @@ func SetScaleYSpacing(v int) func(any) {
 func (s *Scales) SetScaleRadius(v int) { s.Radius = v }
 func (s *Scales) SetScaleXSpacing(v int) { s.SpacingX = v }
 func (s *Scales) SetScaleYSpacing(v int) { s.SpacingY = v }
+
+func init() {
+	RegisterPattern(generatorType("scales", &Scales{}, NewScales))
+}
@@ -5,6 +5,7 @@ import (
 	"image/color"
 	"math"
 	"sort"
+	"sync"
 )
 
 // Ensure Scatter implements the image.Image interface.
@@ -33,6 +34,14 @@ type Scatter struct {
 	Generator  ScatterGenerator
 	Seed       int64
 	MaxOverlap int // Radius of neighbor cells to check (default 1 for 3x3)
+	Period         // Tile every PeriodX by PeriodY pixels when set
+	// Points, when set, places the items at these points instead of in cells,
+	// such as those of PoissonDiskPoints. The Period does not apply to them, and
+	// they should not change once the pattern is drawn.
+	Points []image.Point
+
+	once  sync.Once
+	index *voronoiIndex
 }
 
 func (s *Scatter) SetSeed(v int64) {
@@ -48,11 +57,14 @@ func (s *Scatter) At(x, y int) color.Color {
 	if freq == 0 {
 		freq = 0.05
 	}
-	cellSize := 1.0 / freq
+	// With a period the cells are resized to fit it exactly, and hashed wrapped.
+	cellsX, freqX := periodCells(s.PeriodX, freq)
+	cellsY, freqY := periodCells(s.PeriodY, freq)
+	cellSizeX, cellSizeY := 1.0/freqX, 1.0/freqY
 
 	// Determine grid cell
-	gx := int(math.Floor(float64(x) * freq))
-	gy := int(math.Floor(float64(y) * freq))
+	gx := int(math.Floor(float64(x) * freqX))
+	gy := int(math.Floor(float64(y) * freqY))
 
 	// Candidates for rendering at this pixel
 	type candidate struct {
@@ -66,44 +78,62 @@ func (s *Scatter) At(x, y int) color.Color {
 		overlap = 1
 	}
 
-	// Check neighbor cells
-	for dy := -overlap; dy <= overlap; dy++ {
-		for dx := -overlap; dx <= overlap; dx++ {
-			cx := gx + dx
-			cy := gy + dy
+	// place considers the item centred at (centerX, centerY) with hash h.
+	place := func(centerX, centerY float64, h uint64) {
+		// Deterministic random float [0, 1)
+		r1 := float64(h&0xFFFF) / 65535.0
 
-			// Hash for this cell
-			h := s.hash(cx, cy)
+		// Density check
+		if r1 > s.Density {
+			return
+		}
 
-			// Deterministic random float [0, 1)
-			r1 := float64(h&0xFFFF) / 65535.0
+		// Local coordinates (u, v) relative to center
+		// Normalized so that 1.0 is roughly the size of a cell?
+		// Let's pass pixel delta. Generator can decide scaling.
+		u := float64(x) - centerX
+		v := float64(y) - centerY
+
+		// Call generator
+		if s.Generator != nil {
+			col, z := s.Generator(u, v, h)
+			_, _, _, a := col.RGBA()
+			if a > 0 {
+				candidates = append(candidates, candidate{col, z})
+			}
+		}
+	}
 
-			// Density check
-			if r1 > s.Density {
-				continue
+	if len(s.Points) > 0 {
+		// Items within MaxOverlap cells of the pixel, either way, are considered.
+		s.once.Do(func() {
+			s.index = newVoronoiIndex(s.Points, nil, s.Bounds())
+		})
+		limitX, limitY := float64(overlap)*cellSizeX, float64(overlap)*cellSizeY
+		s.index.search(float64(x), float64(y), func(i int) {
+			p := s.Points[i]
+			if math.Abs(float64(p.X-x)) <= limitX && math.Abs(float64(p.Y-y)) <= limitY {
+				place(float64(p.X), float64(p.Y), s.hash(i, 0))
 			}
+		}, func(reach float64) bool {
+			return reach > math.Max(limitX, limitY)
+		})
+	} else {
+		// Check neighbor cells
+		for dy := -overlap; dy <= overlap; dy++ {
+			for dx := -overlap; dx <= overlap; dx++ {
+				cx := gx + dx
+				cy := gy + dy
+
+				// Hash for this cell
+				h := s.hash(wrapCell(cx, cellsX), wrapCell(cy, cellsY))
 
-			// Random position within the cell
-			rX := float64((h>>16)&0xFFFF) / 65535.0
-			rY := float64((h>>32)&0xFFFF) / 65535.0
-
-			// Center of the item in pixel coordinates
-			centerX := (float64(cx) + rX) * cellSize
-			centerY := (float64(cy) + rY) * cellSize
-
-			// Local coordinates (u, v) relative to center
-			// Normalized so that 1.0 is roughly the size of a cell?
-			// Let's pass pixel delta. Generator can decide scaling.
-			u := float64(x) - centerX
-			v := float64(y) - centerY
-
-			// Call generator
-			if s.Generator != nil {
-				col, z := s.Generator(u, v, h)
-				_, _, _, a := col.RGBA()
-				if a > 0 {
-					candidates = append(candidates, candidate{col, z})
-				}
+				// Random position within the cell
+				rX := float64((h>>16)&0xFFFF) / 65535.0
+				rY := float64((h>>32)&0xFFFF) / 65535.0
+
+				// Center of the item in pixel coordinates
+				place((float64(cx)+rX)*cellSizeX, (float64(cy)+rY)*cellSizeY, h)
 			}
 		}
 	}
@@ -220,3 +250,16 @@ func SetScatterMaxOverlap(overlap int) func(any) {
 		}
 	}
 }
+
+// SetScatterPoints sets the points the items are placed at, instead of in cells.
+func SetScatterPoints(points []image.Point) func(any) {
+	return func(i any) {
+		if p, ok := i.(*Scatter); ok {
+			p.Points = points
+		}
+	}
+}
+
+func init() {
+	RegisterPattern(generatorType("scatter", &Scatter{}, NewScatter))
+}
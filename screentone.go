@@ -98,3 +98,7 @@ func NewScreenTone(ops ...func(any)) image.Image {
 	}
 	return p
 }
+
+func init() {
+	RegisterPattern(generatorType("screen_tone", &ScreenTone{}, NewScreenTone))
+}
@@ -181,3 +181,7 @@ func NewShojo(ops ...func(any)) image.Image {
 	}
 	return p
 }
+
+func init() {
+	RegisterPattern(generatorType("shojo", &Shojo{}, NewShojo))
+}
@@ -87,3 +87,8 @@ func NewSierpinskiCarpet(ops ...func(any)) image.Image {
 	}
 	return p
 }
+
+func init() {
+	RegisterPattern(generatorType("sierpinski_carpet", &SierpinskiCarpet{}, NewSierpinskiCarpet))
+	RegisterPattern(generatorType("sierpinski_triangle", &SierpinskiTriangle{}, NewSierpinskiTriangle))
+}
@@ -47,3 +47,32 @@ func NewSimpleZoom(img image.Image, factor int, ops ...func(any)) image.Image {
 func NewDemoSimpleZoom(img image.Image, ops ...func(any)) image.Image {
 	return NewSimpleZoom(img, 2, ops...)
 }
+
+func init() {
+	RegisterPattern(&PatternType{
+		Name:     "simple_zoom",
+		Category: CategoryFilter,
+		Inputs:   []Input{{Name: "source"}},
+		Params: []Param{
+			{Name: "factor", Type: ParamInt, Default: 2, Min: 1, Max: 256, Doc: "Zoom factor."},
+		},
+		Sample: &SimpleZoom{},
+		New: func(a *Args) (image.Image, error) {
+			return NewSimpleZoom(a.Input("source"), a.Int("factor"), a.Options...), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			p := img.(*SimpleZoom)
+			a.SetInput("source", p.img)
+			a.Set("factor", p.factor)
+			return nil
+		},
+	})
+	RegisterPattern(&PatternType{
+		Name:     "demo_simple_zoom",
+		Category: CategoryFilter,
+		Inputs:   []Input{{Name: "source"}},
+		New: func(a *Args) (image.Image, error) {
+			return NewDemoSimpleZoom(a.Input("source"), a.Options...), nil
+		},
+	})
+}
@@ -18,6 +18,8 @@ const (
 )
 
 // SpeedLines pattern generates manga-style speed lines.
+// Animated with SetTime, the lines are redrawn 12 times a second, repeating
+// every second.
 type SpeedLines struct {
 	Null
 	Center
@@ -29,6 +31,7 @@ type SpeedLines struct {
 	MaxRadius
 	Density
 	Phase
+	Time
 	Type SpeedLinesType
 }
 
@@ -117,6 +120,11 @@ func (p *SpeedLines) At(x, y int) color.Color {
 	// We need a stateless noise function. I'll use a simple hash helper.
 
 	seed := int64(p.Phase.Phase * 1000) // Phase affects seed/offset
+	frame := int64(math.Floor(p.Time.Time*speedLinesFPS)) % speedLinesFPS
+	if frame < 0 {
+		frame += speedLinesFPS
+	}
+	seed += frame * 7919 // Each frame of an animation draws other lines
 
 	// High frequency noise for line presence
 	n1 := noise1D(normalizedAngle * p.Density.Density, seed)
@@ -174,6 +182,9 @@ func (p *SpeedLines) At(x, y int) color.Color {
 	return color.RGBA{} // Transparent
 }
 
+// speedLinesFPS is the number of times a second animated speed lines are redrawn.
+const speedLinesFPS = 12
+
 // noise1D returns a value 0..1
 func noise1D(x float64, seed int64) float64 {
 	// Simple hash of coordinate
@@ -233,3 +244,7 @@ func SpeedLinesLinearType() func(any) {
 		}
 	}
 }
+
+func init() {
+	RegisterPattern(generatorType("speed_lines", &SpeedLines{}, NewSpeedLines))
+}
@@ -194,3 +194,8 @@ func SetVignetteRadius(radius float64) func(any) {
 		}
 	}
 }
+
+func init() {
+	RegisterPattern(generatorType("subpixel_lines", &SubpixelLines{}, NewSubpixelLines))
+	RegisterPattern(generatorType("demo_subpixel_lines", nil, NewDemoSubpixelLines))
+}
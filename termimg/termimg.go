@@ -0,0 +1,249 @@
+package termimg
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/png"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/arran4/go-pattern"
+)
+
+// Format is a way of drawing images in a terminal.
+type Format string
+
+const (
+	ANSI  Format = "ansi"  // 24-bit colour upper half blocks, two pixels to a character cell.
+	Sixel Format = "sixel" // DEC Sixel graphics, in at most 256 colours.
+	Kitty Format = "kitty" // The Kitty graphics protocol, sending the image as a PNG.
+)
+
+// Formats lists the supported formats.
+var Formats = []Format{ANSI, Sixel, Kitty}
+
+// ParseFormat returns the named format.
+func ParseFormat(name string) (Format, error) {
+	for _, f := range Formats {
+		if strings.EqualFold(name, string(f)) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown terminal format %q, expected one of ansi, sixel, kitty", name)
+}
+
+// DefaultWidth is the width images are scaled down to when none is given: the
+// terminal width from $COLUMNS, or 80 columns, for ANSI and 800 pixels otherwise.
+func DefaultWidth(format Format) int {
+	if format != ANSI {
+		return 800
+	}
+	if cols, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && cols > 0 {
+		return cols
+	}
+	return 80
+}
+
+// Encode writes img to w in format. Images wider than width, counted in
+// character cells for ANSI and in pixels otherwise, are first scaled down with
+// Fit. A width of 0 uses DefaultWidth.
+func Encode(w io.Writer, img image.Image, format Format, width int) error {
+	if width <= 0 {
+		width = DefaultWidth(format)
+	}
+	img = Fit(img, width)
+	switch format {
+	case ANSI:
+		return EncodeANSI(w, img)
+	case Sixel:
+		return EncodeSixel(w, img)
+	case Kitty:
+		return EncodeKitty(w, img)
+	}
+	return fmt.Errorf("unknown terminal format %q", format)
+}
+
+// Fit scales img down with pattern.NewScale so it is at most width pixels wide,
+// keeping its aspect ratio. Narrower images are returned as they are.
+func Fit(img image.Image, width int) image.Image {
+	b := img.Bounds()
+	if b.Dx() <= width || width <= 0 {
+		return img
+	}
+	height := max(1, b.Dy()*width/b.Dx())
+	return pattern.NewScale(img, pattern.ScaleToSize(width, height))
+}
+
+// EncodeANSI writes img as 24-bit colour upper half blocks, so each character
+// cell shows two pixels, one above the other. Each row of cells ends with a reset
+// and a newline.
+func EncodeANSI(w io.Writer, img image.Image) error {
+	b := img.Bounds()
+	bw := bufio.NewWriter(w)
+	for y := b.Min.Y; y < b.Max.Y; y += 2 {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			top := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+			fmt.Fprintf(bw, "\x1b[38;2;%d;%d;%dm", top.R, top.G, top.B)
+			if y+1 < b.Max.Y {
+				bottom := color.RGBAModel.Convert(img.At(x, y+1)).(color.RGBA)
+				fmt.Fprintf(bw, "\x1b[48;2;%d;%d;%dm", bottom.R, bottom.G, bottom.B)
+			} else {
+				bw.WriteString("\x1b[49m")
+			}
+			bw.WriteString("▀")
+		}
+		bw.WriteString("\x1b[0m\n")
+	}
+	return bw.Flush()
+}
+
+// EncodeSixel writes img as Sixel graphics. Images of up to 256 colours keep their
+// colours; others are dithered to the Plan 9 palette. Pixels less than half
+// opaque are left transparent.
+func EncodeSixel(w io.Writer, img image.Image) error {
+	b := img.Bounds()
+	pal, idx := sixelPalette(img)
+	bw := bufio.NewWriter(w)
+	// P2=1 leaves pixels that are not drawn transparent.
+	fmt.Fprintf(bw, "\x1bP0;1;0q\"1;1;%d;%d", b.Dx(), b.Dy())
+	for i, c := range pal {
+		r, g, bl, _ := c.RGBA()
+		fmt.Fprintf(bw, "#%d;2;%d;%d;%d", i, r*100/0xffff, g*100/0xffff, bl*100/0xffff)
+	}
+	sixels := make([]byte, b.Dx())
+	for top := 0; top < b.Dy(); top += 6 {
+		// used lists the colours in this band of six rows, in the order found.
+		var used []int
+		seen := make(map[int]bool)
+		for y := top; y < min(top+6, b.Dy()); y++ {
+			for x := 0; x < b.Dx(); x++ {
+				if i := idx(x, y); i >= 0 && !seen[i] {
+					seen[i] = true
+					used = append(used, i)
+				}
+			}
+		}
+		for n, c := range used {
+			for x := range sixels {
+				sixels[x] = 0
+				for dy := 0; dy < 6 && top+dy < b.Dy(); dy++ {
+					if idx(x, top+dy) == c {
+						sixels[x] |= 1 << dy
+					}
+				}
+			}
+			fmt.Fprintf(bw, "#%d", c)
+			writeSixelRun(bw, sixels)
+			if n < len(used)-1 {
+				bw.WriteByte('$')
+			}
+		}
+		bw.WriteByte('-')
+	}
+	bw.WriteString("\x1b\\")
+	return bw.Flush()
+}
+
+// writeSixelRun writes a row of sixels, run length encoding repeats.
+func writeSixelRun(w *bufio.Writer, sixels []byte) {
+	for x := 0; x < len(sixels); {
+		n := 1
+		for x+n < len(sixels) && sixels[x+n] == sixels[x] {
+			n++
+		}
+		if n > 3 {
+			fmt.Fprintf(w, "!%d%c", n, 63+sixels[x])
+		} else {
+			for i := 0; i < n; i++ {
+				w.WriteByte(63 + sixels[x])
+			}
+		}
+		x += n
+	}
+}
+
+// sixelPalette chooses the colours to draw img with, and returns them with a
+// function giving the colour index of the pixel at (x, y) relative to the
+// image's origin, or -1 for a transparent pixel.
+func sixelPalette(img image.Image) (color.Palette, func(x, y int) int) {
+	b := img.Bounds()
+	opaque := func(c color.Color) bool {
+		_, _, _, a := c.RGBA()
+		return a >= 0x8000
+	}
+	var pal color.Palette
+	index := make(map[color.RGBA]int)
+	exact := make([]int, b.Dx()*b.Dy())
+	for y := 0; y < b.Dy(); y++ {
+		for x := 0; x < b.Dx(); x++ {
+			c := img.At(b.Min.X+x, b.Min.Y+y)
+			if !opaque(c) {
+				exact[y*b.Dx()+x] = -1
+				continue
+			}
+			rgba := color.RGBAModel.Convert(c).(color.RGBA)
+			i, ok := index[rgba]
+			if !ok {
+				if len(pal) == 256 {
+					return ditheredPalette(img, opaque)
+				}
+				i = len(pal)
+				index[rgba] = i
+				pal = append(pal, rgba)
+			}
+			exact[y*b.Dx()+x] = i
+		}
+	}
+	return pal, func(x, y int) int { return exact[y*b.Dx()+x] }
+}
+
+// ditheredPalette draws img onto the Plan 9 palette with Floyd-Steinberg
+// dithering.
+func ditheredPalette(img image.Image, opaque func(color.Color) bool) (color.Palette, func(x, y int) int) {
+	b := img.Bounds()
+	p := image.NewPaletted(image.Rect(0, 0, b.Dx(), b.Dy()), palette.Plan9)
+	draw.FloydSteinberg.Draw(p, p.Bounds(), img, b.Min)
+	return p.Palette, func(x, y int) int {
+		if !opaque(img.At(b.Min.X+x, b.Min.Y+y)) {
+			return -1
+		}
+		return int(p.ColorIndexAt(x, y))
+	}
+}
+
+// EncodeKitty writes img with the Kitty graphics protocol, as a PNG sent in
+// base64 chunks of 4096 bytes.
+func EncodeKitty(w io.Writer, img image.Image) error {
+	var sb strings.Builder
+	enc := base64.NewEncoder(base64.StdEncoding, &sb)
+	if err := png.Encode(enc, img); err != nil {
+		return err
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	data := sb.String()
+	bw := bufio.NewWriter(w)
+	for first := true; first || data != ""; first = false {
+		chunk := data[:min(4096, len(data))]
+		data = data[len(chunk):]
+		more := 0
+		if data != "" {
+			more = 1
+		}
+		if first {
+			fmt.Fprintf(bw, "\x1b_Ga=T,f=100,m=%d;%s\x1b\\", more, chunk)
+		} else {
+			fmt.Fprintf(bw, "\x1b_Gm=%d;%s\x1b\\", more, chunk)
+		}
+	}
+	bw.WriteString("\n")
+	return bw.Flush()
+}
@@ -0,0 +1,131 @@
+package termimg
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/arran4/go-pattern"
+)
+
+func TestEncodeANSI(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Encode(&buf, pattern.NewChecker(color.Black, color.White, pattern.SetBounds(image.Rect(0, 0, 40, 20))), ANSI, 10); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 10x5 pixels to take 3 lines, got %d", len(lines))
+	}
+	for _, line := range lines {
+		if n := strings.Count(line, "▀"); n != 10 {
+			t.Errorf("Expected 10 cells, got %d in %q", n, line)
+		}
+		if !strings.HasSuffix(line, "\x1b[0m") {
+			t.Errorf("Expected the line to reset its colours: %q", line)
+		}
+	}
+	if !strings.Contains(lines[2], "\x1b[49m") {
+		t.Error("Expected the odd last row to leave the background unset")
+	}
+}
+
+func TestEncodeSixel(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 8, 7))
+	for y := 0; y < 7; y++ {
+		for x := 0; x < 8; x++ {
+			if x < 4 {
+				img.Set(x, y, color.NRGBA{R: 255, A: 255})
+			} else if y != 0 {
+				img.Set(x, y, color.NRGBA{B: 255, A: 255})
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := EncodeSixel(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	want := "\x1bP0;1;0q\"1;1;8;7" +
+		"#0;2;100;0;0#1;2;0;0;100" +
+		"#0!4~!4?$#1!4?!4}-" +
+		"#0!4@!4?$#1!4?!4@-" +
+		"\x1b\\"
+	if got := buf.String(); got != want {
+		t.Errorf("Expected\n%q\ngot\n%q", want, got)
+	}
+}
+
+func TestEncodeSixelDithers(t *testing.T) {
+	img := pattern.NewLinearGradient(pattern.SetBounds(image.Rect(0, 0, 300, 4)))
+	var buf bytes.Buffer
+	if err := EncodeSixel(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	colours := regexp.MustCompile(`#(\d+);2;`).FindAllString(buf.String(), -1)
+	if len(colours) != 256 {
+		t.Errorf("Expected the Plan 9 palette of 256 colours, got %d", len(colours))
+	}
+}
+
+func TestEncodeKitty(t *testing.T) {
+	// Noise does not compress, so the PNG needs several chunks.
+	src := image.NewNRGBA(image.Rect(0, 0, 200, 100))
+	rand.New(rand.NewSource(1)).Read(src.Pix)
+	var buf bytes.Buffer
+	if err := Encode(&buf, src, Kitty, 120); err != nil {
+		t.Fatal(err)
+	}
+	chunks := regexp.MustCompile("\x1b_G([^;]*);([^\x1b]*)\x1b\\\\").FindAllStringSubmatch(buf.String(), -1)
+	if len(chunks) < 2 {
+		t.Fatalf("Expected the image to be sent in several chunks, got %d", len(chunks))
+	}
+	var data string
+	for i, c := range chunks {
+		want := "m=1"
+		switch {
+		case i == 0:
+			want = "a=T,f=100,m=1"
+		case i == len(chunks)-1:
+			want = "m=0"
+		}
+		if c[1] != want {
+			t.Errorf("Chunk %d: expected %s, got %s", i, want, c[1])
+		}
+		if len(c[2]) > 4096 {
+			t.Errorf("Chunk %d has %d bytes", i, len(c[2]))
+		}
+		data += c[2]
+	}
+	img, err := png.Decode(base64.NewDecoder(base64.StdEncoding, strings.NewReader(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds() != image.Rect(0, 0, 120, 60) {
+		t.Errorf("Expected the image scaled to 120x60, got %v", img.Bounds())
+	}
+}
+
+func TestFit(t *testing.T) {
+	img := pattern.NewChecker(color.Black, color.White, pattern.SetBounds(image.Rect(0, 0, 100, 30)))
+	if got := Fit(img, 200); got != image.Image(img) {
+		t.Error("Expected a narrow image to be returned as it is")
+	}
+	if b := Fit(img, 10).Bounds(); b.Dx() != 10 || b.Dy() != 3 {
+		t.Errorf("Expected 10x3, got %v", b)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	if f, err := ParseFormat("Sixel"); err != nil || f != Sixel {
+		t.Errorf("Expected sixel, got %q, %v", f, err)
+	}
+	if _, err := ParseFormat("png"); err == nil {
+		t.Error("Expected png not to be a terminal format")
+	}
+}
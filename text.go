@@ -56,6 +56,7 @@ func TextBackgroundColorColor(bg color.Color) TextOption {
 	}
 }
 
+// NewText renders s in the Go font, sized to fit the text.
 func NewText(s string, opts ...TextOption) image.Image {
 	cfg := &textConfig{
 		fontSize: 24,
@@ -105,3 +106,25 @@ func NewText(s string, opts ...TextOption) image.Image {
 
 	return img
 }
+
+func init() {
+	// NewText draws into a plain RGBA image, which has no Sample to save it by.
+	RegisterPattern(&PatternType{
+		Name:     "text",
+		Category: CategoryGenerator,
+		Params: []Param{
+			{Name: "text", Type: ParamString, Default: "hello", Doc: "Text to draw."},
+			{Name: "size", Type: ParamFloat, Default: 24.0, Min: 1, Max: 1000, Doc: "Font size in points."},
+			{Name: "dpi", Type: ParamFloat, Default: 72.0, Min: 1, Max: 1200, Doc: "Resolution in dots per inch."},
+			{Name: "color", Type: ParamColor, Default: color.Black, Doc: "Colour of the text."},
+			{Name: "background", Type: ParamColor, Doc: "Colour behind the text; transparent when unset."},
+		},
+		New: func(a *Args) (image.Image, error) {
+			opts := []TextOption{TextSize(a.Float("size")), TextDPI(a.Float("dpi")), TextColorColor(a.Color("color"))}
+			if bg := a.Color("background"); bg != nil {
+				opts = append(opts, TextBackgroundColorColor(bg))
+			}
+			return NewText(a.String("text"), opts...), nil
+		},
+	})
+}
@@ -242,3 +242,8 @@ func clampUint8(v float64) uint8 {
 	}
 	return uint8(math.Round(v))
 }
+
+func init() {
+	RegisterPattern(generatorType("thread_bands", &ThreadBands{}, NewThreadBands))
+	RegisterPattern(generatorType("demo_thread_bands", nil, NewDemoThreadBands))
+}
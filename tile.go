@@ -57,3 +57,24 @@ func NewTile(img image.Image, bounds image.Rectangle) image.Image {
 		bounds: bounds,
 	}
 }
+
+func init() {
+	RegisterPattern(&PatternType{
+		Name:     "tile",
+		Category: CategoryFilter,
+		Inputs:   []Input{{Name: "source"}},
+		Params: []Param{
+			{Name: "rect", Type: ParamRect, Default: image.Rect(0, 0, 255, 255), Doc: "Bounds of the tiled image."},
+		},
+		Sample: &Tile{},
+		New: func(a *Args) (image.Image, error) {
+			return applyOps(NewTile(a.Input("source"), a.Rect("rect")), a.Options), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			p := img.(*Tile)
+			a.SetInput("source", p.img)
+			a.Set("rect", p.bounds)
+			return nil
+		},
+	})
+}
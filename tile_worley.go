@@ -226,3 +226,7 @@ func smoothStep(edge0, edge1, x float64) float64 {
 	t := clamp01((x - edge0) / (edge1 - edge0))
 	return t * t * (3 - 2*t)
 }
+
+func init() {
+	RegisterPattern(generatorType("worley_tiles", &WorleyTiles{}, NewWorleyTiles))
+}
@@ -45,3 +45,27 @@ func NewTransposed(img image.Image, x, y int, ops ...func(any)) image.Image {
 func NewDemoTransposed(ops ...func(any)) image.Image {
 	return NewTransposed(NewSimpleZoom(NewChecker(color.Black, color.White, ops...), 20, ops...), 10, 10, ops...)
 }
+
+func init() {
+	RegisterPattern(&PatternType{
+		Name:     "transposed",
+		Category: CategoryFilter,
+		Inputs:   []Input{{Name: "source"}},
+		Params: []Param{
+			{Name: "x", Type: ParamInt, Default: 0, Doc: "Horizontal offset."},
+			{Name: "y", Type: ParamInt, Default: 0, Doc: "Vertical offset."},
+		},
+		Sample: &Transposed{},
+		New: func(a *Args) (image.Image, error) {
+			return NewTransposed(a.Input("source"), a.Int("x"), a.Int("y"), a.Options...), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			p := img.(*Transposed)
+			a.SetInput("source", p.img)
+			a.Set("x", p.x)
+			a.Set("y", p.y)
+			return nil
+		},
+	})
+	RegisterPattern(generatorType("demo_transposed", nil, NewDemoTransposed))
+}
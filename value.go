@@ -0,0 +1,176 @@
+package pattern
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// namedPalettes are the palettes ParseValue and colour list parameters accept
+// by name.
+var namedPalettes = map[string]color.Palette{
+	"bw":        {color.Black, color.White},
+	"cga":       PaletteCGA,
+	"plan9":     palette.Plan9,
+	"websafe":   palette.WebSafe,
+	"windows16": Windows16,
+}
+
+// valueParsers convert the text form of a value to each of the types in ValueTypes.
+var valueParsers = map[reflect.Type]func(s string) (any, error){
+	reflect.TypeOf(0):          func(s string) (any, error) { return convertInt(s) },
+	reflect.TypeOf(int64(0)):   func(s string) (any, error) { return strconv.ParseInt(strings.TrimSpace(s), 10, 64) },
+	reflect.TypeOf(uint64(0)):  func(s string) (any, error) { return strconv.ParseUint(strings.TrimSpace(s), 10, 64) },
+	reflect.TypeOf(0.0):        func(s string) (any, error) { return convertFloat(s) },
+	reflect.TypeOf(false):      paramParser(ParamBool),
+	reflect.TypeOf(""):         func(s string) (any, error) { return s, nil },
+	reflect.TypeOf([]int(nil)): func(s string) (any, error) { return convertInts(s, ",") },
+	reflect.TypeOf([]float64(nil)): func(s string) (any, error) {
+		return convertFloats(s)
+	},
+	reflect.TypeOf((*color.Color)(nil)).Elem(): func(s string) (any, error) { return convertColor(s) },
+	reflect.TypeOf(color.RGBA{}): func(s string) (any, error) {
+		c, err := convertColor(s)
+		if err != nil {
+			return nil, err
+		}
+		return color.RGBAModel.Convert(c), nil
+	},
+	reflect.TypeOf([]color.Color(nil)): func(s string) (any, error) { return convertColors(s) },
+	reflect.TypeOf(color.Palette(nil)): func(s string) (any, error) {
+		l, err := convertColors(s)
+		if err != nil {
+			return nil, fmt.Errorf("expected a palette name or a list of colours: %w", err)
+		}
+		return color.Palette(l), nil
+	},
+	reflect.TypeOf(image.Point{}): func(s string) (any, error) {
+		xy, err := convertInts(s, ":")
+		if err != nil || len(xy) != 2 {
+			return nil, fmt.Errorf("expected a point as x:y, got %q", s)
+		}
+		return image.Pt(xy[0], xy[1]), nil
+	},
+	reflect.TypeOf([]image.Point(nil)): paramParser(ParamPoints),
+	reflect.TypeOf(image.Rectangle{}):  paramParser(ParamRect),
+	reflect.TypeOf([]ColorStop(nil)):   paramParser(ParamColorStops),
+	reflect.TypeOf(time.Duration(0)): func(s string) (any, error) {
+		return time.ParseDuration(strings.TrimSpace(s))
+	},
+	reflect.TypeOf(DiffusionKernel{}): func(s string) (any, error) {
+		i, err := parseEnum(diffusionKernelNames, s)
+		if err != nil {
+			return nil, err
+		}
+		return diffusionKernels()[i], nil
+	},
+	reflect.TypeOf(BlendMode(0)):      enumParser[BlendMode](blendModeNames),
+	reflect.TypeOf(BooleanMode(0)):    enumParser[BooleanMode](booleanModeNames),
+	reflect.TypeOf(BooleanOpType(0)):  enumParser[BooleanOpType](booleanOpNames),
+	reflect.TypeOf(DistanceMetric(0)): enumParser[DistanceMetric](distanceMetricNames),
+	reflect.TypeOf(WorleyOutput(0)):   enumParser[WorleyOutput](worleyOutputNames),
+}
+
+func paramParser(t ParamType) func(s string) (any, error) {
+	return func(s string) (any, error) { return Param{Type: t}.convert(s) }
+}
+
+func enumParser[T ~int](names []string) func(s string) (any, error) {
+	return func(s string) (any, error) {
+		i, err := parseEnum(names, s)
+		return T(i), err
+	}
+}
+
+func parseEnum(names []string, s string) (int, error) {
+	s = strings.TrimSpace(s)
+	for i, name := range names {
+		if name == s {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("expected one of %s, got %q", strings.Join(names, ", "), s)
+}
+
+// ParseValue converts the text form of a value to type t, for arguments given as
+// text such as those of the DSL. It accepts the forms Param.Convert does, palettes
+// by name (bw, cga, plan9, websafe and windows16) or as a list of colours,
+// diffusion kernels and enums such as BlendMode by their names in the pattern
+// registry, and durations as understood by time.ParseDuration.
+func ParseValue(t reflect.Type, s string) (any, error) {
+	parse, ok := valueParsers[t]
+	if !ok {
+		return nil, fmt.Errorf("cannot parse a %s from text", t)
+	}
+	return parse(s)
+}
+
+// ValueTypes lists the types ParseValue converts to.
+func ValueTypes() []reflect.Type {
+	types := make([]reflect.Type, 0, len(valueParsers))
+	for t := range valueParsers {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i].String() < types[j].String() })
+	return types
+}
+
+// SetOption sets the option called name on target, as the option's Set function
+// would. Names are matched ignoring case and underscores, so "mortar_size" sets
+// MortarSize. Only options listed by Describe can be set. A string value is
+// converted with ParseValue, with the arguments of setters that take several, such
+// as SetCenter, separated by commas; other values must be assignable to the
+// setter's single argument.
+func SetOption(target any, name string, value any) error {
+	var info *OptionInfo
+	for _, o := range Describe(target) {
+		if optionKey(o.Name) == optionKey(name) {
+			info = &o
+			break
+		}
+	}
+	if info == nil {
+		return fmt.Errorf("%T has no option %q", target, name)
+	}
+	var in []reflect.Value
+	if s, ok := value.(string); ok && !(len(info.Args) == 1 && info.Args[0].Kind() == reflect.String) {
+		parts := []string{s}
+		if len(info.Args) > 1 {
+			parts = strings.Split(s, ",")
+			if len(parts) != len(info.Args) {
+				return fmt.Errorf("option %s takes %d comma separated values, got %q", info.Name, len(info.Args), s)
+			}
+		}
+		for i, t := range info.Args {
+			v, err := ParseValue(t, parts[i])
+			if err != nil {
+				return fmt.Errorf("option %s: %w", info.Name, err)
+			}
+			in = append(in, reflect.ValueOf(v).Convert(t))
+		}
+	} else {
+		v := reflect.ValueOf(value)
+		if len(info.Args) != 1 || !v.IsValid() || !v.Type().AssignableTo(info.Args[0]) {
+			return fmt.Errorf("option %s does not take a %T", info.Name, value)
+		}
+		in = append(in, v)
+	}
+	reflect.ValueOf(target).MethodByName("Set" + info.Name).Call(in)
+	return nil
+}
+
+func optionKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || r == '-' {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, name)
+}
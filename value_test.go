@@ -0,0 +1,80 @@
+package pattern
+
+import (
+	"image"
+	"image/color"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseValue(t *testing.T) {
+	tests := []struct {
+		in   string
+		want any
+	}{
+		{"12", 12},
+		{"-3", int64(-3)},
+		{"0.5", 0.5},
+		{"true", true},
+		{"red", color.RGBA{255, 0, 0, 255}},
+		{"cga", PaletteCGA},
+		{"black,white", color.Palette{color.RGBA{0, 0, 0, 255}, color.RGBA{255, 255, 255, 255}}},
+		{"3:4", image.Pt(3, 4)},
+		{"1:2,3:4", []image.Point{{1, 2}, {3, 4}}},
+		{"0,0,4,8", image.Rect(0, 0, 4, 8)},
+		{"1,2,3", []int{1, 2, 3}},
+		{"250ms", 250 * time.Millisecond},
+		{"stucki", Stucki},
+		{"overlay", BlendOverlay},
+		{"bitwise_xor", OpBitwiseXor},
+		{"threshold", ModeThreshold},
+	}
+	for _, tt := range tests {
+		typ := reflect.TypeOf(tt.want)
+		got, err := ParseValue(typ, tt.in)
+		if err != nil {
+			t.Errorf("ParseValue(%s, %q) failed: %v", typ, tt.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseValue(%s, %q): expected %v, got %v", typ, tt.in, tt.want, got)
+		}
+	}
+	if c, err := ParseValue(reflect.TypeOf((*color.Color)(nil)).Elem(), "#00f"); err != nil || c != (color.RGBA{0, 0, 255, 255}) {
+		t.Errorf("Expected #00f to parse as a color.Color, got %v, %v", c, err)
+	}
+	if _, err := ParseValue(reflect.TypeOf(BlendMode(0)), "dodge"); err == nil || !strings.Contains(err.Error(), "expected one of add,") {
+		t.Errorf("Expected an error listing the blend modes, got %v", err)
+	}
+	if _, err := ParseValue(reflect.TypeOf(struct{}{}), ""); err == nil {
+		t.Error("Expected an error for an unsupported type")
+	}
+}
+
+func TestSetOption(t *testing.T) {
+	b := NewBrick().(*Brick)
+	for name, value := range map[string]any{
+		"mortar_size":  "3",
+		"BrickSize":    "10,5",
+		"mortar_image": NewNull(),
+	} {
+		if err := SetOption(b, name, value); err != nil {
+			t.Errorf("SetOption(%s) failed: %v", name, err)
+		}
+	}
+	if b.MortarSize != 3 || b.Width != 10 || b.Height != 5 || b.MortarImage == nil {
+		t.Errorf("Expected mortar 3, size 10x5 and a mortar image, got %d, %dx%d and %v", b.MortarSize, b.Width, b.Height, b.MortarImage)
+	}
+	for name, value := range map[string]any{
+		"radius":      "3",
+		"mortar_size": "wide",
+		"brick_size":  "10",
+		"mortarsize":  1.5,
+	} {
+		if err := SetOption(b, name, value); err == nil {
+			t.Errorf("Expected SetOption(%s, %v) to fail", name, value)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package pattern
+
+import (
+	"image/color"
+	"math"
+	"sync"
+)
+
+// Ensure ValueNoise implements the NoiseAlgorithm and ScalarField interfaces.
+var _ NoiseAlgorithm = (*ValueNoise)(nil)
+var _ ScalarField = (*ValueNoise)(nil)
+
+// ValueNoise implements value noise with Fractional Brownian Motion (fBm): random
+// values at the points of a square lattice, hashed with StableHash and smoothly
+// interpolated between them. It is softer and blockier than gradient noise, and
+// the cheapest of the smooth algorithms.
+type ValueNoise struct {
+	Seed        int64
+	Octaves     int
+	Persistence float64
+	Lacunarity  float64
+	Frequency   float64
+
+	once sync.Once
+}
+
+func (n *ValueNoise) init() {
+	n.once.Do(func() {
+		n.Frequency, n.Octaves, n.Lacunarity, n.Persistence = noiseDefaults(n.Frequency, n.Octaves, n.Lacunarity, n.Persistence)
+	})
+}
+
+func (n *ValueNoise) At(x, y int) color.Color {
+	return color.Gray{Y: uint8(clamp01(n.ValueAt(float64(x), float64(y))) * 255)}
+}
+
+// ValueAt returns the fBm sum at (x, y) in [0, 1], without quantisation.
+func (n *ValueNoise) ValueAt(x, y float64) float64 {
+	n.init()
+	seed := uint64(n.Seed)
+	return fbm(n.Octaves, n.Frequency, n.Lacunarity, n.Persistence, func(f float64) float64 {
+		return valueNoise(seed, float64(x*f), float64(y*f))
+	})
+}
+
+// valueNoise returns value noise at (x, y) in [-1, 1].
+func valueNoise(seed uint64, x, y float64) float64 {
+	xb, yb := math.Floor(x), math.Floor(y)
+	X, Y := int(xb), int(yb)
+	u, v := fade(x-xb), fade(y-yb)
+	value := func(X, Y int) float64 {
+		return float64(StableHash(X, Y, seed)>>11)/(1<<52) - 1
+	}
+	// The differences are converted before they are scaled and added, so they are
+	// not fused into multiply-adds and round the same on every platform.
+	top := value(X, Y) + float64(u*(value(X+1, Y)-value(X, Y)))
+	bottom := value(X, Y+1) + float64(u*(value(X+1, Y+1)-value(X, Y+1)))
+	return top + float64(v*(bottom-top))
+}
@@ -10,8 +10,11 @@ import (
 var _ image.Image = (*VHS)(nil)
 
 // VHS applies a retro VHS effect with scanlines, chromatic aberration, and noise.
+// Animated with SetTime, the scanlines roll down one period a second and the noise
+// changes 30 times a second, repeating every second.
 type VHS struct {
 	Null
+	Time
 	Image             image.Image
 	ScanlineFrequency float64 // Frequency of the scanlines (e.g. 0.5 for every other line)
 	ScanlineIntensity float64 // Intensity of the scanline darkening (0.0 to 1.0)
@@ -48,7 +51,7 @@ func (p *VHS) At(x, y int) color.Color {
 	b8 := bN.B
 
 	// 2. Scanlines
-	s := math.Sin(float64(y) * p.ScanlineFrequency)
+	s := math.Sin(float64(y)*p.ScanlineFrequency - 2*math.Pi*p.Time.Time)
 	scanFactor := 1.0
 	if p.ScanlineIntensity > 0 {
 		normSine := (s + 1.0) / 2.0
@@ -123,7 +126,7 @@ func NewVHS(img image.Image, ops ...func(any)) image.Image {
 	}
 
 	// Initialize noise after ops, so Seed is set
-	p.noise = &HashNoise{Seed: p.Seed}
+	p.resetNoise()
 
 	return p
 }
@@ -196,10 +199,33 @@ func (p *VHS) SetNoiseIntensity(f float64) {
 
 func (p *VHS) SetSeed(s int64) {
 	p.Seed = s
-	p.noise = &HashNoise{Seed: p.Seed}
+	p.resetNoise()
 }
 
 func (p *VHS) SetSeedUint64(s uint64) {
 	p.Seed = int64(s)
-	p.noise = &HashNoise{Seed: p.Seed}
+	p.resetNoise()
+}
+
+func (p *VHS) SetTime(t float64) {
+	p.Time.Time = t
+	p.resetNoise()
+}
+
+// vhsNoiseFPS is the number of times a second the noise of an animated VHS changes.
+const vhsNoiseFPS = 30
+
+// resetNoise seeds the noise for the seed and the frame of the animation.
+func (p *VHS) resetNoise() {
+	frame := int64(math.Floor(p.Time.Time*vhsNoiseFPS)) % vhsNoiseFPS
+	if frame < 0 {
+		frame += vhsNoiseFPS
+	}
+	p.noise = &HashNoise{Seed: p.Seed + frame*7919}
+}
+
+func init() {
+	RegisterPattern(filterType("vhs", &VHS{}, NewVHS, func(img image.Image) image.Image {
+		return img.(*VHS).Image
+	}))
 }
@@ -0,0 +1,286 @@
+package pattern
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// BlueNoiseCacheDir is the directory blue noise masks are kept in between runs.
+// Masks are slow to generate, a second or so at 256x256, so when it is set
+// BlueNoiseMask saves each mask there and loads it again instead of generating
+// it. It is empty, and masks are only kept in memory, by default. Errors reading
+// or writing the cache are ignored: the mask is generated instead.
+var BlueNoiseCacheDir string
+
+// blueNoiseSigma is the deviation, in pixels, of the Gaussian filter that measures
+// how clustered the pixels of a mask are, as in Ulichney's paper.
+const blueNoiseSigma = 1.5
+
+// Sizes of the masks BlueNoiseMask generates.
+const (
+	MinBlueNoiseSize = 16
+	MaxBlueNoiseSize = 256
+)
+
+var blueNoiseMasks = struct {
+	sync.Mutex
+	m map[[2]int64]*blueNoiseEntry
+}{m: map[[2]int64]*blueNoiseEntry{}}
+
+// blueNoiseEntry generates a mask once, however many ask for it at once.
+type blueNoiseEntry struct {
+	once sync.Once
+	mask []uint16
+}
+
+// BlueNoiseMask returns a size by size blue noise rank mask generated by
+// Ulichney's void-and-cluster method. Each pixel holds its rank, from 0 to
+// size²-1, and the pixels ranked below any threshold are spread evenly, without
+// clumps, even where the mask is tiled. Thresholding a grey at its value with the
+// mask is an ordered dither free of the grid patterns of Bayer matrices.
+//
+// The size is clamped to [16, 256]. Masks are deterministic for a size and seed,
+// and are kept once generated; see BlueNoiseCacheDir. The mask returned is shared
+// and must not be modified.
+func BlueNoiseMask(size int, seed int64) []uint16 {
+	if size < MinBlueNoiseSize {
+		size = MinBlueNoiseSize
+	}
+	if size > MaxBlueNoiseSize {
+		size = MaxBlueNoiseSize
+	}
+	key := [2]int64{int64(size), seed}
+	blueNoiseMasks.Lock()
+	e, ok := blueNoiseMasks.m[key]
+	if !ok {
+		e = &blueNoiseEntry{}
+		blueNoiseMasks.m[key] = e
+	}
+	blueNoiseMasks.Unlock()
+	e.once.Do(func() {
+		path := ""
+		if BlueNoiseCacheDir != "" {
+			path = filepath.Join(BlueNoiseCacheDir, fmt.Sprintf("bluenoise-%d-%d.bin", size, seed))
+			if mask, err := readBlueNoiseMask(path, size); err == nil {
+				e.mask = mask
+				return
+			}
+		}
+		e.mask = voidAndCluster(size, uint64(seed))
+		if path != "" {
+			_ = writeBlueNoiseMask(path, e.mask)
+		}
+	})
+	return e.mask
+}
+
+// readBlueNoiseMask reads a mask saved by writeBlueNoiseMask, checking that it is
+// a whole mask of the size.
+func readBlueNoiseMask(path string, size int) ([]uint16, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) != 2*size*size {
+		return nil, fmt.Errorf("%s holds %d bytes, not a %dx%d mask", path, len(data), size, size)
+	}
+	mask := make([]uint16, size*size)
+	seen := make([]bool, size*size)
+	for i := range mask {
+		mask[i] = binary.LittleEndian.Uint16(data[2*i:])
+		if int(mask[i]) >= len(mask) || seen[mask[i]] {
+			return nil, fmt.Errorf("%s is not a rank mask", path)
+		}
+		seen[mask[i]] = true
+	}
+	return mask, nil
+}
+
+// writeBlueNoiseMask saves a mask as little endian 16-bit ranks. It writes a
+// temporary file and renames it, so a mask is never read half written.
+func writeBlueNoiseMask(path string, mask []uint16) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data := make([]byte, 2*len(mask))
+	for i, v := range mask {
+		binary.LittleEndian.PutUint16(data[2*i:], v)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// voidAndCluster generates a size by size rank mask.
+//
+// The energy of a pixel is the sum of a toroidal Gaussian over the set pixels
+// around it: high in the tightest clusters, low in the largest voids. An initial
+// pattern of a tenth of the pixels, set at random, is relaxed by moving the pixel
+// in the tightest cluster to the largest void until it stays put. Its pixels are
+// then ranked downwards by removing the tightest cluster each time, and the rest
+// ranked upwards by filling the largest void each time. Filling the largest void
+// of the set pixels is the same as taking the tightest cluster of the unset ones,
+// as the two energies add up to the same everywhere, so a single rule serves both
+// halves of the upward ranking.
+//
+// The Gaussian is cut off beyond three deviations, so setting or clearing a pixel
+// updates the energies around it alone, and two trees find the tightest cluster and
+// largest void in logarithmic time.
+func voidAndCluster(size int, seed uint64) []uint16 {
+	n := size * size
+	radius := int(math.Ceil(3 * blueNoiseSigma))
+	type tap struct {
+		dx, dy int
+		w      float64
+	}
+	var kernel []tap
+	for dy := -radius; dy <= radius; dy++ {
+		for dx := -radius; dx <= radius; dx++ {
+			kernel = append(kernel, tap{dx, dy, math.Exp(-float64(dx*dx+dy*dy) / (2 * blueNoiseSigma * blueNoiseSigma))})
+		}
+	}
+
+	set := make([]bool, n)
+	energy := make([]float64, n)
+	// clusters holds the negated energy of each set pixel, and voids the energy of
+	// each unset one, so the least of each is the tightest cluster or largest void.
+	clusters, voids := newMinTree(n), newMinTree(n)
+	for i := 0; i < n; i++ {
+		voids.set(i, 0)
+	}
+	toggle := func(i int) {
+		set[i] = !set[i]
+		sign := 1.0
+		if !set[i] {
+			sign = -1
+		}
+		x, y := i%size, i/size
+		for _, t := range kernel {
+			j := (y+t.dy+size)%size*size + (x+t.dx+size)%size
+			energy[j] += sign * t.w
+			if set[j] {
+				clusters.set(j, -energy[j])
+			} else {
+				voids.set(j, energy[j])
+			}
+		}
+		if set[i] {
+			voids.clear(i)
+			clusters.set(i, -energy[i])
+		} else {
+			clusters.clear(i)
+			voids.set(i, energy[i])
+		}
+	}
+
+	// The initial pattern, relaxed.
+	ones := n / 10
+	for k := 0; k < ones; k++ {
+		i := int(StableHash(k, 0, seed) % uint64(n))
+		for set[i] {
+			i = (i + 1) % n
+		}
+		toggle(i)
+	}
+	for k := 0; k < 4*n; k++ {
+		cluster := clusters.least()
+		toggle(cluster)
+		void := voids.least()
+		toggle(void)
+		if void == cluster {
+			break
+		}
+	}
+	initial := append([]bool(nil), set...)
+	initialEnergy := append([]float64(nil), energy...)
+
+	ranks := make([]uint16, n)
+	// Rank the initial pattern downwards.
+	for rank := ones - 1; rank >= 0; rank-- {
+		i := clusters.least()
+		ranks[i] = uint16(rank)
+		toggle(i)
+	}
+	// Restore it, and rank the rest upwards.
+	copy(set, initial)
+	copy(energy, initialEnergy)
+	for i := 0; i < n; i++ {
+		if set[i] {
+			clusters.set(i, -energy[i])
+			voids.clear(i)
+		} else {
+			clusters.clear(i)
+			voids.set(i, energy[i])
+		}
+	}
+	for rank := ones; rank < n; rank++ {
+		i := voids.least()
+		ranks[i] = uint16(rank)
+		toggle(i)
+	}
+	return ranks
+}
+
+// minTree is a segment tree over values that finds the least, the first of equals,
+// in logarithmic time.
+type minTree struct {
+	leaves int
+	value  []float64
+	index  []int
+}
+
+func newMinTree(n int) *minTree {
+	leaves := 1
+	for leaves < n {
+		leaves *= 2
+	}
+	t := &minTree{leaves: leaves, value: make([]float64, 2*leaves), index: make([]int, 2*leaves)}
+	for i := range t.value {
+		t.value[i] = math.Inf(1)
+	}
+	for i := 0; i < leaves; i++ {
+		t.index[leaves+i] = i
+	}
+	for i := leaves - 1; i > 0; i-- {
+		t.index[i] = t.index[2*i]
+	}
+	return t
+}
+
+// set sets the value of i.
+func (t *minTree) set(i int, v float64) {
+	i += t.leaves
+	t.value[i] = v
+	for i /= 2; i > 0; i /= 2 {
+		l, r := 2*i, 2*i+1
+		if t.value[r] < t.value[l] {
+			l = r
+		}
+		t.value[i], t.index[i] = t.value[l], t.index[l]
+	}
+}
+
+// clear removes i from the tree.
+func (t *minTree) clear(i int) {
+	t.set(i, math.Inf(1))
+}
+
+// least returns the index of the least value.
+func (t *minTree) least() int {
+	return t.index[1]
+}
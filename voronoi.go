@@ -4,39 +4,274 @@ import (
 	"image"
 	"image/color"
 	"math"
+	"sync"
 )
 
-// Ensure Voronoi implements the image.Image interface.
+// Ensure Voronoi implements the image.Image and ScalarField interfaces.
 var _ image.Image = (*Voronoi)(nil)
+var _ ScalarField = (*Voronoi)(nil)
+
+// VoronoiOutput selects what a Voronoi draws.
+type VoronoiOutput int
+
+const (
+	// VoronoiCells fills each cell with its colour or source image.
+	VoronoiCells VoronoiOutput = iota
+	// VoronoiDistance is the distance to the nearest site.
+	VoronoiDistance
+	// VoronoiBorder is the distance to the nearest border between cells.
+	VoronoiBorder
+)
+
+// voronoiOutputNames are the names of the VoronoiOutput values in the pattern registry.
+var voronoiOutputNames = []string{"cells", "distance", "border"}
 
 // Voronoi is a pattern that generates Voronoi cells based on a set of points and colors.
+//
+// Each cell is filled with its colour, used in turn, or with its image from Sources,
+// also used in turn, which take precedence. Distances are measured with Metric, and
+// Weights, when set, make a power diagram: each site claims the points where d² − w²
+// is least, w being its weight in pixels, so heavier sites claim larger cells. Sites
+// without a weight weigh nothing.
+//
+// Where BorderWidth is positive the cells are outlined in BorderColor. The distance
+// outputs are drawn in units of the mean spacing of the sites over the bounds, so
+// they are roughly in [0, 1]; borders are exact for the Euclidean metric and
+// estimated, as half F2 − F1, for the others.
+//
+// The sites are indexed in a grid the first time the pattern is drawn, so Points and
+// Weights should not change after that.
 type Voronoi struct {
 	Null
-	Points []image.Point
-	Colors []color.Color
+	Points      []image.Point
+	Colors      []color.Color
+	Sources     []image.Image
+	Weights     []float64
+	Metric      DistanceMetric
+	MinkowskiP  float64
+	Output      VoronoiOutput
+	BorderWidth float64
+	BorderColor color.Color
+
+	once  sync.Once
+	index *voronoiIndex
+}
+
+// voronoiIndex buckets the sites of a Voronoi into square cells of a grid, so a
+// search need only look at the cells near the point.
+type voronoiIndex struct {
+	minX, minY float64
+	size       float64
+	cols, rows int
+	cells      [][]int
+	// maxWeight2 is the largest squared weight, which bounds how far a site can
+	// reach beyond its cell.
+	maxWeight2 float64
+	// spacing is the mean distance between sites over the bounds.
+	spacing float64
+}
+
+func (v *Voronoi) init() {
+	v.once.Do(func() {
+		v.index = newVoronoiIndex(v.Points, v.Weights, v.Bounds())
+	})
+}
+
+func newVoronoiIndex(points []image.Point, weights []float64, bounds image.Rectangle) *voronoiIndex {
+	idx := &voronoiIndex{}
+	n := float64(len(points))
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range points {
+		minX, minY = math.Min(minX, float64(p.X)), math.Min(minY, float64(p.Y))
+		maxX, maxY = math.Max(maxX, float64(p.X)), math.Max(maxY, float64(p.Y))
+	}
+	w, h := maxX-minX, maxY-minY
+	// Cells hold about one site each, but are never so small that a line of
+	// sites leaves most of them empty.
+	idx.size = math.Max(math.Sqrt(w*h/n), math.Max(math.Max(w, h)/n, 1))
+	idx.minX, idx.minY = minX, minY
+	idx.cols = int(w/idx.size) + 1
+	idx.rows = int(h/idx.size) + 1
+	idx.cells = make([][]int, idx.cols*idx.rows)
+	for i, p := range points {
+		cx, cy := idx.cell(float64(p.X), float64(p.Y))
+		idx.cells[cy*idx.cols+cx] = append(idx.cells[cy*idx.cols+cx], i)
+	}
+	for _, w := range weights {
+		idx.maxWeight2 = math.Max(idx.maxWeight2, w*w)
+	}
+	idx.spacing = math.Sqrt(float64(bounds.Dx()*bounds.Dy()) / n)
+	if idx.spacing < 1 {
+		idx.spacing = 1
+	}
+	return idx
+}
+
+// cell returns the grid cell containing (x, y), or the nearest one to it.
+func (idx *voronoiIndex) cell(x, y float64) (int, int) {
+	cx := int(math.Floor((x - idx.minX) / idx.size))
+	cy := int(math.Floor((y - idx.minY) / idx.size))
+	if cx < 0 {
+		cx = 0
+	} else if cx >= idx.cols {
+		cx = idx.cols - 1
+	}
+	if cy < 0 {
+		cy = 0
+	} else if cy >= idx.rows {
+		cy = idx.rows - 1
+	}
+	return cx, cy
+}
+
+// search visits the sites in rings of grid cells around (x, y), nearest first,
+// until done, which is given the least distance from (x, y) to any site yet to be
+// visited, reports that the search is over.
+func (idx *voronoiIndex) search(x, y float64, visit func(i int), done func(reach float64) bool) {
+	cx, cy := idx.cell(x, y)
+	last := idx.cols
+	if idx.rows > last {
+		last = idx.rows
+	}
+	for r := 0; r < last; r++ {
+		// Every metric is at least the Chebyshev distance, and a site in ring r
+		// is at least r-1 cells away by that.
+		if reach := float64(r-1) * idx.size; r > 0 && done(reach) {
+			return
+		}
+		for y := cy - r; y <= cy+r; y++ {
+			if y < 0 || y >= idx.rows {
+				continue
+			}
+			step := 2 * r
+			if y == cy-r || y == cy+r || step == 0 {
+				step = 1
+			}
+			for x := cx - r; x <= cx+r; x += step {
+				if x < 0 || x >= idx.cols {
+					continue
+				}
+				for _, i := range idx.cells[y*idx.cols+x] {
+					visit(i)
+				}
+			}
+		}
+	}
 }
 
 func (v *Voronoi) At(x, y int) color.Color {
 	if len(v.Points) == 0 {
 		return color.Transparent
 	}
+	if v.Output != VoronoiCells {
+		return color.Gray16{Y: uint16(clamp01(v.ValueAt(float64(x), float64(y)))*65535 + 0.5)}
+	}
+	return v.colorAt(float64(x), float64(y))
+}
 
-	minDist := math.MaxFloat64
-	closestIndex := 0
+// ValueAt returns the luminance of the colour of the cell containing (x, y), or
+// the selected distance.
+func (v *Voronoi) ValueAt(x, y float64) float64 {
+	if len(v.Points) == 0 {
+		return 0
+	}
+	switch v.Output {
+	case VoronoiDistance:
+		i := v.nearest(x, y)
+		_, d := v.power(i, x, y)
+		return d / v.index.spacing
+	case VoronoiBorder:
+		return v.border(v.nearest(x, y), x, y) / v.index.spacing
+	}
+	return getLuminanceForMaterial(v.colorAt(x, y))
+}
 
-	for i, p := range v.Points {
-		dx := float64(x - p.X)
-		dy := float64(y - p.Y)
-		dist := dx*dx + dy*dy
+// colorAt returns the colour of the cells at (x, y), borders included.
+func (v *Voronoi) colorAt(x, y float64) color.Color {
+	i := v.nearest(x, y)
+	if v.BorderWidth > 0 && v.border(i, x, y) < v.BorderWidth/2 {
+		if v.BorderColor == nil {
+			return color.Black
+		}
+		return v.BorderColor
+	}
+	if len(v.Sources) > 0 {
+		return v.Sources[i%len(v.Sources)].At(int(math.Floor(x)), int(math.Floor(y)))
+	}
+	return v.colorOf(i)
+}
 
-		if dist < minDist {
-			minDist = dist
+// power returns the power of (x, y) with respect to site i, d² − w², and its
+// distance d.
+func (v *Voronoi) power(i int, x, y float64) (float64, float64) {
+	dx := x - float64(v.Points[i].X)
+	dy := y - float64(v.Points[i].Y)
+	var pow, d float64
+	if v.Metric == MetricEuclidean {
+		pow = dx*dx + dy*dy
+		d = math.Sqrt(pow)
+	} else {
+		d = v.Metric.Distance(dx, dy, v.MinkowskiP)
+		pow = d * d
+	}
+	if i < len(v.Weights) {
+		pow -= v.Weights[i] * v.Weights[i]
+	}
+	return pow, d
+}
+
+// nearest returns the index of the site whose cell contains (x, y). Of sites
+// equally near, the first wins.
+func (v *Voronoi) nearest(x, y float64) int {
+	v.init()
+	minPow := math.MaxFloat64
+	closestIndex := -1
+	v.index.search(x, y, func(i int) {
+		pow, _ := v.power(i, x, y)
+		if pow < minPow || pow == minPow && i < closestIndex {
+			minPow = pow
 			closestIndex = i
 		}
-	}
+	}, func(reach float64) bool {
+		return reach*reach-v.index.maxWeight2 > minPow
+	})
+	return closestIndex
+}
+
+// border returns the distance from (x, y), in the cell of site i, to the nearest
+// border with another cell. For the Euclidean metric the border with site j is the
+// line where their powers are equal, which lies (pow_j − pow_i) / 2|p_j − p_i|
+// away; for the others |p_j − p_i| is estimated by d_i + d_j, which makes
+// unweighted borders half of F2 − F1.
+func (v *Voronoi) border(i int, x, y float64) float64 {
+	powI, dI := v.power(i, x, y)
+	pi := v.Points[i]
+	best := math.MaxFloat64
+	v.index.search(x, y, func(j int) {
+		pj := v.Points[j]
+		if j == i || pj == pi {
+			return
+		}
+		powJ, dJ := v.power(j, x, y)
+		span := dI + dJ
+		if v.Metric == MetricEuclidean {
+			span = math.Hypot(float64(pj.X-pi.X), float64(pj.Y-pi.Y))
+		}
+		if b := (powJ - powI) / (2 * span); b < best {
+			best = b
+		}
+	}, func(reach float64) bool {
+		// The border with a site d_j away is at least (d_j² − w_j² − pow_i) /
+		// 2(d_j + d_i), which grows with d_j.
+		return (reach*reach-v.index.maxWeight2-powI)/(2*(reach+dI)) >= best
+	})
+	return best
+}
 
+func (v *Voronoi) colorOf(index int) color.Color {
 	if len(v.Colors) > 0 {
-		return v.Colors[closestIndex%len(v.Colors)]
+		return v.Colors[index%len(v.Colors)]
 	}
 	return color.Black
 }
@@ -47,8 +282,9 @@ func NewVoronoi(points []image.Point, colors []color.Color, ops ...func(any)) im
 		Null: Null{
 			bounds: image.Rect(0, 0, 255, 255),
 		},
-		Points: points,
-		Colors: colors,
+		Points:      points,
+		Colors:      colors,
+		BorderColor: color.Black,
 	}
 	for _, op := range ops {
 		op(p)
@@ -56,6 +292,62 @@ func NewVoronoi(points []image.Point, colors []color.Color, ops ...func(any)) im
 	return p
 }
 
+// SetVoronoiSources sets the images the cells of a Voronoi are filled from, in turn.
+func SetVoronoiSources(sources ...image.Image) func(any) {
+	return func(i any) {
+		if v, ok := i.(*Voronoi); ok {
+			v.Sources = sources
+		}
+	}
+}
+
+// SetVoronoiWeights sets the weights of the sites of a Voronoi, in pixels, making
+// it a power diagram.
+func SetVoronoiWeights(weights ...float64) func(any) {
+	return func(i any) {
+		if v, ok := i.(*Voronoi); ok {
+			v.Weights = weights
+		}
+	}
+}
+
+// SetVoronoiMetric sets the distance metric of a Voronoi.
+func SetVoronoiMetric(m DistanceMetric) func(any) {
+	return func(i any) {
+		if v, ok := i.(*Voronoi); ok {
+			v.Metric = m
+		}
+	}
+}
+
+// SetVoronoiMinkowskiP sets the order of the Minkowski metric of a Voronoi.
+func SetVoronoiMinkowskiP(p float64) func(any) {
+	return func(i any) {
+		if v, ok := i.(*Voronoi); ok {
+			v.MinkowskiP = p
+		}
+	}
+}
+
+// SetVoronoiOutput sets what a Voronoi draws.
+func SetVoronoiOutput(o VoronoiOutput) func(any) {
+	return func(i any) {
+		if v, ok := i.(*Voronoi); ok {
+			v.Output = o
+		}
+	}
+}
+
+// SetVoronoiBorder outlines the cells of a Voronoi with borders width pixels wide.
+func SetVoronoiBorder(width float64, c color.Color) func(any) {
+	return func(i any) {
+		if v, ok := i.(*Voronoi); ok {
+			v.BorderWidth = width
+			v.BorderColor = c
+		}
+	}
+}
+
 // NewDemoVoronoi produces a demo variant for readme.md pre-populated values.
 func NewDemoVoronoi(ops ...func(any)) image.Image {
 	points := []image.Point{
@@ -72,3 +364,51 @@ func NewDemoVoronoi(ops ...func(any)) image.Image {
 	}
 	return NewVoronoi(points, colors, ops...)
 }
+
+func init() {
+	RegisterPattern(&PatternType{
+		Name:     "voronoi",
+		Category: CategoryGenerator,
+		Inputs:   []Input{{Name: "sources", Optional: true, Variadic: true}},
+		Params: []Param{
+			{Name: "points", Type: ParamPoints, Doc: "Cell sites."},
+			{Name: "colors", Type: ParamColors, Doc: "Cell colours, used in turn."},
+			{Name: "weights", Type: ParamFloats, Doc: "Site weights in pixels, making a power diagram."},
+			{Name: "metric", Type: ParamEnum, Default: "euclidean", Values: distanceMetricNames, Doc: "Distance metric."},
+			{Name: "minkowski_p", Type: ParamFloat, Default: 3.0, Doc: "Order of the minkowski metric."},
+			{Name: "output", Type: ParamEnum, Default: "cells", Values: voronoiOutputNames, Doc: "Value to output."},
+			{Name: "border_width", Type: ParamFloat, Default: 0.0, Min: 0, Doc: "Width of the cell borders in pixels."},
+			{Name: "border_color", Type: ParamColor, Default: color.Black, Doc: "Colour of the cell borders."},
+		},
+		Sample: &Voronoi{},
+		New: func(a *Args) (image.Image, error) {
+			p := NewVoronoi(a.Points("points"), a.Colors("colors")).(*Voronoi)
+			p.Sources = a.InputList("sources")
+			p.Weights = a.Floats("weights")
+			p.Metric = DistanceMetric(a.Enum("metric", distanceMetricNames))
+			p.MinkowskiP = a.Float("minkowski_p")
+			p.Output = VoronoiOutput(a.Enum("output", voronoiOutputNames))
+			p.BorderWidth = a.Float("border_width")
+			p.BorderColor = a.Color("border_color")
+			return applyOps(p, a.Options), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			p := img.(*Voronoi)
+			a.SetInput("sources", p.Sources...)
+			a.Set("points", p.Points)
+			a.Set("colors", p.Colors)
+			a.Set("weights", p.Weights)
+			a.Set("metric", enumName(distanceMetricNames, int(p.Metric)))
+			minkowskiP := p.MinkowskiP
+			if minkowskiP <= 0 {
+				minkowskiP = 3
+			}
+			a.Set("minkowski_p", minkowskiP)
+			a.Set("output", enumName(voronoiOutputNames, int(p.Output)))
+			a.Set("border_width", p.BorderWidth)
+			a.Set("border_color", p.BorderColor)
+			return nil
+		},
+	})
+	RegisterPattern(generatorType("demo_voronoi", nil, NewDemoVoronoi))
+}
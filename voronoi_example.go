@@ -46,6 +46,50 @@ func GenerateVoronoi(b image.Rectangle) image.Image {
 	return NewDemoVoronoi(SetBounds(b))
 }
 
+func GenerateVoronoiReferences() (map[string]func(image.Rectangle) image.Image, []string) {
+	refs := make(map[string]func(image.Rectangle) image.Image)
+	var keys []string
+
+	add := func(key string, ops ...func(any)) {
+		keys = append(keys, key)
+		refs[key] = func(b image.Rectangle) image.Image {
+			// Sites scattered over the bounds, about 24 pixels apart.
+			n := b.Dx() * b.Dy() / 576
+			points := make([]image.Point, n)
+			for i := range points {
+				h := StableHash(i, 0, 7)
+				points[i] = image.Point{b.Min.X + int(h%uint64(b.Dx())), b.Min.Y + int(h>>32%uint64(b.Dy()))}
+			}
+			colors := []color.Color{
+				color.RGBA{255, 100, 100, 255},
+				color.RGBA{100, 255, 100, 255},
+				color.RGBA{100, 100, 255, 255},
+				color.RGBA{255, 255, 100, 255},
+				color.RGBA{100, 255, 255, 255},
+			}
+			baseOps := []func(any){SetBounds(b)}
+			return NewVoronoi(points, colors, append(baseOps, ops...)...)
+		}
+	}
+
+	weights := make([]float64, 1024)
+	for i := range weights {
+		weights[i] = float64(StableHash(i, 1, 7) % 16)
+	}
+
+	add("Borders", SetVoronoiBorder(3, color.Black))
+	add("Weighted", SetVoronoiWeights(weights...), SetVoronoiBorder(2, color.White))
+	add("Manhattan", SetVoronoiMetric(MetricManhattan), SetVoronoiBorder(2, color.Black))
+	add("Chebyshev", SetVoronoiMetric(MetricChebyshev), SetVoronoiBorder(2, color.Black))
+	add("Hex", SetVoronoiMetric(MetricHex), SetVoronoiBorder(2, color.Black))
+	add("Distance", SetVoronoiOutput(VoronoiDistance))
+	add("BorderDistance", SetVoronoiOutput(VoronoiBorder))
+	add("Sources", SetVoronoiSources(NewChecker(color.Black, color.White), NewDemoVoronoi()))
+
+	return refs, keys
+}
+
 func init() {
 	RegisterGenerator(VoronoiBaseLabel, GenerateVoronoi)
+	RegisterReferences(VoronoiBaseLabel, GenerateVoronoiReferences)
 }
@@ -1,8 +1,10 @@
 package pattern
 
 import (
+	"bytes"
 	"image"
 	"image/color"
+	"math"
 	"testing"
 )
 
@@ -51,3 +53,115 @@ func TestVoronoi_At(t *testing.T) {
 		t.Errorf("Expected White at (15, 10) due to order preference, got %v", c)
 	}
 }
+
+// randomSites returns n sites scattered over a 200x200 square by StableHash.
+func randomSites(n int) []image.Point {
+	points := make([]image.Point, n)
+	for i := range points {
+		h := StableHash(i, 0, 11)
+		points[i] = image.Point{int(h % 200), int(h >> 32 % 200)}
+	}
+	return points
+}
+
+func TestVoronoiIndex(t *testing.T) {
+	points := randomSites(300)
+	weights := make([]float64, 150)
+	for i := range weights {
+		weights[i] = float64(i % 13)
+	}
+	tests := map[string]*Voronoi{
+		"euclidean": NewVoronoi(points, nil).(*Voronoi),
+		"weighted":  NewVoronoi(points, nil, SetVoronoiWeights(weights...)).(*Voronoi),
+		"manhattan": NewVoronoi(points, nil, SetVoronoiMetric(MetricManhattan)).(*Voronoi),
+		"chebyshev": NewVoronoi(points, nil, SetVoronoiMetric(MetricChebyshev), SetVoronoiWeights(weights...)).(*Voronoi),
+		"hex":       NewVoronoi(points, nil, SetVoronoiMetric(MetricHex)).(*Voronoi),
+	}
+	for name, v := range tests {
+		// The sites are checked from outside their bounding box too.
+		for y := -30.0; y < 230; y += 3.7 {
+			for x := -30.0; x < 230; x += 5.3 {
+				want, wantPow := 0, math.MaxFloat64
+				for i := range points {
+					if pow, _ := v.power(i, x, y); pow < wantPow {
+						want, wantPow = i, pow
+					}
+				}
+				if got := v.nearest(x, y); got != want {
+					t.Fatalf("%s: nearest(%v, %v) = %d, want %d", name, x, y, got, want)
+				}
+				if name != "euclidean" && name != "weighted" {
+					continue
+				}
+				powI, _ := v.power(want, x, y)
+				wantBorder := math.MaxFloat64
+				for j, pj := range points {
+					if pj == points[want] {
+						continue
+					}
+					powJ, _ := v.power(j, x, y)
+					span := math.Hypot(float64(pj.X-points[want].X), float64(pj.Y-points[want].Y))
+					wantBorder = math.Min(wantBorder, (powJ-powI)/(2*span))
+				}
+				if got := v.border(want, x, y); got != wantBorder {
+					t.Fatalf("%s: border(%v, %v) = %v, want %v", name, x, y, got, wantBorder)
+				}
+			}
+		}
+	}
+}
+
+func TestVoronoiBorder(t *testing.T) {
+	points := []image.Point{{10, 10}, {30, 10}}
+	v := NewVoronoi(points, []color.Color{color.White}, SetVoronoiBorder(4, color.RGBA{255, 0, 0, 255})).(*Voronoi)
+	for x, want := range map[int]color.Color{17: color.White, 19: color.RGBA{255, 0, 0, 255}, 21: color.RGBA{255, 0, 0, 255}, 23: color.White} {
+		if got := v.At(x, 10); got != want {
+			t.Errorf("At(%d, 10) = %v, want %v", x, got, want)
+		}
+	}
+	v.Output = VoronoiBorder
+	if got, want := v.ValueAt(16, 10), 4/v.index.spacing; math.Abs(got-want) > 1e-12 {
+		t.Errorf("border distance at (16, 10) = %v, want %v", got, want)
+	}
+
+	// A heavier site pushes the border away.
+	v = NewVoronoi(points, []color.Color{color.White, color.Black}, SetVoronoiWeights(10, 0)).(*Voronoi)
+	if got := v.At(22, 10); got != color.White {
+		t.Errorf("weighted At(22, 10) = %v, want White", got)
+	}
+}
+
+func TestVoronoiSources(t *testing.T) {
+	red := NewRect(SetFillColor(color.RGBA{255, 0, 0, 255}))
+	blue := NewRect(SetFillColor(color.RGBA{0, 0, 255, 255}))
+	v := NewVoronoi([]image.Point{{10, 10}, {30, 10}}, nil, SetVoronoiSources(red, blue))
+	if got := v.At(12, 10); got != red.At(12, 10) {
+		t.Errorf("At(12, 10) = %v, want red", got)
+	}
+	if got := v.At(28, 10); got != blue.At(28, 10) {
+		t.Errorf("At(28, 10) = %v, want blue", got)
+	}
+}
+
+func TestVoronoiGraph(t *testing.T) {
+	img := NewVoronoi(randomSites(20), []color.Color{color.White, color.Gray{Y: 128}},
+		SetBounds(image.Rect(0, 0, 40, 30)), SetVoronoiMetric(MetricManhattan),
+		SetVoronoiWeights(3, 0, 5), SetVoronoiBorder(2, color.RGBA{0, 0, 255, 255}))
+	var buf bytes.Buffer
+	if err := SaveGraph(&buf, img); err != nil {
+		t.Fatalf("SaveGraph failed: %v", err)
+	}
+	loaded, err := LoadGraph(&buf)
+	if err != nil {
+		t.Fatalf("LoadGraph failed: %v", err)
+	}
+	sameImage(t, img, loaded)
+}
+
+func BenchmarkVoronoi_At(b *testing.B) {
+	v := NewVoronoi(randomSites(5000), []color.Color{color.White, color.Black})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		v.At(i%200, i/200%200)
+	}
+}
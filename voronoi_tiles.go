@@ -142,3 +142,22 @@ func clampByte(v int) uint8 {
 	}
 	return uint8(v)
 }
+
+func init() {
+	// The tiles are a Blend of other patterns, and are saved as those.
+	RegisterPattern(&PatternType{
+		Name:     "voronoi_tiles",
+		Category: CategoryGenerator,
+		Params: []Param{
+			{Name: "bounds", Type: ParamRect, Default: image.Rectangle{}, Doc: "Area the tiles cover; empty is 255 by 255."},
+			{Name: "cell_size", Type: ParamFloat, Default: defaultVoronoiTileCellSize, Min: 1, Max: 4096, Doc: "Distance in pixels between the tile centres."},
+			{Name: "gap_width", Type: ParamFloat, Default: defaultVoronoiTileGapWidth, Min: 0, Max: 1, Doc: "Width of the dark gap between the tiles."},
+			{Name: "height_boost", Type: ParamFloat, Default: defaultVoronoiTileHeightImpact, Min: 0, Max: 10, Doc: "How much the tile centres are lightened and the edges deepened."},
+			{Name: "seed", Type: ParamInt, Default: 0, Doc: "Seed of the tile layout and dust."},
+		},
+		New: func(a *Args) (image.Image, error) {
+			p := NewVoronoiTiles(a.Rect("bounds"), a.Float("cell_size"), a.Float("gap_width"), a.Float("height_boost"), int64(a.Int("seed")))
+			return applyOps(p, a.Options), nil
+		},
+	})
+}
@@ -25,6 +25,7 @@ type Warp struct {
 // NewWarp creates a new Warp pattern.
 // If only Distortion is provided, it displaces both X and Y using the same map (usually diagonal if not handled).
 // However, typically you want different noise for X and Y, so DistortionX and DistortionY are preferred for independent axis warping.
+// The Warp takes the bounds of its source, and options such as SetBounds, applied after, override them.
 func NewWarp(source image.Image, ops ...func(any)) image.Image {
 	p := &Warp{
 		Null: Null{
@@ -34,15 +35,14 @@ func NewWarp(source image.Image, ops ...func(any)) image.Image {
 		Scale:           20.0, // Default distortion magnitude
 		DistortionScale: 1.0,  // Scale of the noise texture coordinates
 	}
-	for _, op := range ops {
-		op(p)
-	}
-	// Default behavior: if bounds are empty (from Null), we might want to adopt source bounds.
-	// But Null defaults to 255x255.
-	// Let's check if Source has bounds?
+	// Adopt the source bounds, unless the options set others, as they do when the
+	// pattern registry builds a Warp.
 	if p.Source != nil && p.Source.Bounds() != image.Rect(0, 0, 0, 0) {
 		p.bounds = p.Source.Bounds()
 	}
+	for _, op := range ops {
+		op(p)
+	}
 
 	return p
 }
@@ -55,23 +55,19 @@ func (p *Warp) At(x, y int) color.Color {
 	dx, dy := 0.0, 0.0
 
 	// Sample distortion at scaled coordinates
-	distX := int(float64(x) * p.DistortionScale)
-	distY := int(float64(y) * p.DistortionScale)
+	fx := float64(x) * p.DistortionScale
+	fy := float64(y) * p.DistortionScale
 
 	// If uniform Distortion map is provided
 	if p.Distortion != nil {
-		c := p.Distortion.At(distX, distY)
-		gray := color.GrayModel.Convert(c).(color.Gray)
-		// Map [0, 255] to [-1, 1] -> [-Scale, Scale]
-		val := (float64(gray.Y)/255.0 - 0.5) * 2.0
+		// Map [0, 1] to [-1, 1] -> [-Scale, Scale]
+		val := (distortionValue(p.Distortion, fx, fy) - 0.5) * 2.0
 		dx += val * p.Scale
 		dy += val * p.Scale
 	}
 
 	if p.DistortionX != nil {
-		c := p.DistortionX.At(distX, distY)
-		gray := color.GrayModel.Convert(c).(color.Gray)
-		val := (float64(gray.Y)/255.0 - 0.5) * 2.0
+		val := (distortionValue(p.DistortionX, fx, fy) - 0.5) * 2.0
 		scale := p.XScale
 		if scale == 0 {
 			scale = p.Scale
@@ -80,9 +76,7 @@ func (p *Warp) At(x, y int) color.Color {
 	}
 
 	if p.DistortionY != nil {
-		c := p.DistortionY.At(distX, distY)
-		gray := color.GrayModel.Convert(c).(color.Gray)
-		val := (float64(gray.Y)/255.0 - 0.5) * 2.0
+		val := (distortionValue(p.DistortionY, fx, fy) - 0.5) * 2.0
 		scale := p.YScale
 		if scale == 0 {
 			scale = p.Scale
@@ -90,6 +84,12 @@ func (p *Warp) At(x, y int) color.Color {
 		dy += val * scale
 	}
 
+	// Samplers are evaluated at the exact displaced pixel centre, avoiding
+	// the aliasing of snapping to whole pixels.
+	if s, ok := p.Source.(Sampler); ok {
+		return s.SampleAt(float64(x)+dx+0.5, float64(y)+dy+0.5)
+	}
+
 	// Sample source at displaced coordinates
 	srcX := int(float64(x) + dx)
 	srcY := int(float64(y) + dy)
@@ -97,6 +97,20 @@ func (p *Warp) At(x, y int) color.Color {
 	return p.Source.At(srcX, srcY)
 }
 
+// distortionValue returns the distortion at (x, y) in [0, 1].
+// Scalar fields are sampled at the exact fractional position, clamped to the
+// range a rendered pixel would have, so displacements never exceed the scale;
+// other images are sampled at the containing pixel through an 8-bit grayscale
+// conversion.
+func distortionValue(img image.Image, x, y float64) float64 {
+	if f, ok := img.(ScalarField); ok {
+		return clamp01(f.ValueAt(x, y))
+	}
+	c := img.At(int(x), int(y))
+	gray := color.GrayModel.Convert(c).(color.Gray)
+	return float64(gray.Y) / 255.0
+}
+
 // WarpScale sets the global distortion scale (magnitude).
 func WarpScale(scale float64) func(any) {
 	return func(i any) {
@@ -159,3 +173,46 @@ func WarpDistortionY(img image.Image) func(any) {
 		}
 	}
 }
+
+func init() {
+	RegisterPattern(&PatternType{
+		Name:     "warp",
+		Category: CategoryFilter,
+		Inputs: []Input{
+			{Name: "source"},
+			{Name: "distortion", Optional: true},
+			{Name: "distortion_x", Optional: true},
+			{Name: "distortion_y", Optional: true},
+		},
+		Params: []Param{
+			{Name: "scale", Type: ParamFloat, Default: 20.0, Doc: "Distortion magnitude in pixels."},
+			{Name: "x_scale", Type: ParamFloat, Default: 0.0, Doc: "Horizontal distortion magnitude; 0 uses scale."},
+			{Name: "y_scale", Type: ParamFloat, Default: 0.0, Doc: "Vertical distortion magnitude; 0 uses scale."},
+			{Name: "distortion_scale", Type: ParamFloat, Default: 1.0, Doc: "Scale of the distortion texture coordinates."},
+		},
+		Sample: &Warp{},
+		New: func(a *Args) (image.Image, error) {
+			p := NewWarp(a.Input("source")).(*Warp)
+			p.Distortion = a.Input("distortion")
+			p.DistortionX = a.Input("distortion_x")
+			p.DistortionY = a.Input("distortion_y")
+			p.Scale = a.Float("scale")
+			p.XScale = a.Float("x_scale")
+			p.YScale = a.Float("y_scale")
+			p.DistortionScale = a.Float("distortion_scale")
+			return applyOps(p, a.Options), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			p := img.(*Warp)
+			a.SetInput("source", p.Source)
+			a.SetInput("distortion", p.Distortion)
+			a.SetInput("distortion_x", p.DistortionX)
+			a.SetInput("distortion_y", p.DistortionY)
+			a.Set("scale", p.Scale)
+			a.Set("x_scale", p.XScale)
+			a.Set("y_scale", p.YScale)
+			a.Set("distortion_scale", p.DistortionScale)
+			return nil
+		},
+	})
+}
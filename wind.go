@@ -159,3 +159,7 @@ func SetWindNoise(img image.Image) func(any) {
 		}
 	}
 }
+
+func init() {
+	RegisterPattern(generatorType("wind_ridges", &WindRidges{}, NewWindRidges))
+}
@@ -6,17 +6,47 @@ import (
 	"math"
 )
 
-// Ensure WorleyNoise implements the image.Image interface.
+// Ensure WorleyNoise implements the image.Image and ScalarField interfaces.
 var _ image.Image = (*WorleyNoise)(nil)
+var _ ScalarField = (*WorleyNoise)(nil)
+var _ VectorField = (*WorleyNoise)(nil)
 
+// DistanceMetric is a way of measuring the distance between two points.
 type DistanceMetric int
 
 const (
 	MetricEuclidean DistanceMetric = iota
 	MetricManhattan
 	MetricChebyshev
+	// MetricMinkowski is the Minkowski distance of order p: the pth root of the sum
+	// of the pth powers of the differences. p = 1 is Manhattan, p = 2 Euclidean, and
+	// it tends to Chebyshev as p grows.
+	MetricMinkowski
+	// MetricHex is the hexagonal distance, whose circles are regular hexagons with
+	// corners on the x axis.
+	MetricHex
 )
 
+// Distance returns the length of (dx, dy) in the metric. p is the order of
+// MetricMinkowski, which defaults to 3 when it is not positive.
+func (m DistanceMetric) Distance(dx, dy, p float64) float64 {
+	dx, dy = math.Abs(dx), math.Abs(dy)
+	switch m {
+	case MetricManhattan:
+		return dx + dy
+	case MetricChebyshev:
+		return math.Max(dx, dy)
+	case MetricMinkowski:
+		if p <= 0 {
+			p = 3
+		}
+		return math.Pow(math.Pow(dx, p)+math.Pow(dy, p), 1/p)
+	case MetricHex:
+		return math.Max(dy*2/math.Sqrt(3), dx+dy/math.Sqrt(3))
+	}
+	return math.Sqrt(dx*dx + dy*dy)
+}
+
 type WorleyOutput int
 
 const (
@@ -24,101 +54,234 @@ const (
 	OutputF2
 	OutputF2MinusF1
 	OutputCellID
+	// OutputF3 is the distance to the third nearest feature point.
+	OutputF3
+	// OutputFN is the distance to the Nth nearest feature point.
+	OutputFN
+	// OutputOffsetX and OutputOffsetY are the components of the offset from the
+	// point to the nearest feature point, mapped from [-1, 1] cells to [0, 1].
+	OutputOffsetX
+	OutputOffsetY
+	// OutputCellHash is the full hash of the nearest feature point, in [0, 1).
+	OutputCellHash
+	// OutputVector draws the offset to the nearest feature point in the red and
+	// green channels, mapped as for OutputOffsetX and OutputOffsetY, and the low
+	// byte of its hash in blue. Its value is F1.
+	OutputVector
 )
 
-// WorleyNoise generates cellular noise (Worley noise).
+// worleyMaxN is the largest N of the distances WorleyNoise can report.
+const worleyMaxN = 16
+
+// worleyMaxRing bounds the rings of cells searched, so a wild Jitter cannot
+// make the search run on.
+const worleyMaxRing = 32
+
+// WorleyNoise generates cellular noise (Worley noise): the distances from each
+// point to the feature points scattered through a grid of cells.
+//
+// Each cell holds PointsPerCell points, 1 by default, placed up to Jitter cells
+// from its corner. A Jitter above 1 lets points stray into the next cell. The
+// search widens ring by ring of cells until no unvisited point could be nearer
+// than those the output needs.
+// OutputFN reports the distance to the Nth nearest point, Nth being at most 16.
 type WorleyNoise struct {
 	Null
 	Seed
 	Frequency
-	Jitter float64
-	Metric DistanceMetric
-	Output WorleyOutput
+	Period
+	Jitter        float64
+	Metric        DistanceMetric
+	Output        WorleyOutput
+	PointsPerCell int
+	Nth           int
+	MinkowskiP    float64
+}
+
+// WorleyCell describes the feature points nearest to a point of a WorleyNoise.
+type WorleyCell struct {
+	// Distances holds the distances to the nearest feature points, nearest first,
+	// in cells. Those not found are math.MaxFloat64.
+	Distances [worleyMaxN]float64
+	// OffsetX and OffsetY lead from the point to the nearest feature point, in cells.
+	OffsetX, OffsetY float64
+	// Hash is the hash of the nearest feature point.
+	Hash uint64
 }
 
 func (w *WorleyNoise) At(x, y int) color.Color {
+	c := w.cell(float64(x), float64(y), w.needed())
+
+	switch w.Output {
+	case OutputCellID:
+		// Map hash to grayscale color
+		return color.Gray{Y: uint8(c.Hash & 0xFF)}
+	case OutputCellHash:
+		return color.Gray16{Y: uint16(c.Hash >> 48)}
+	case OutputVector:
+		return color.RGBA{
+			R: uint8(clamp01(0.5+c.OffsetX/2) * 255),
+			G: uint8(clamp01(0.5+c.OffsetY/2) * 255),
+			B: uint8(c.Hash & 0xFF),
+			A: 255,
+		}
+	}
+	return color.Gray{Y: uint8(clamp01(w.output(c)) * 255)}
+}
+
+// ValueAt returns the selected output at (x, y) without clamping or quantisation.
+// Distances are in cell units, so F2 may exceed 1.
+func (w *WorleyNoise) ValueAt(x, y float64) float64 {
+	return w.output(w.cell(x, y, w.needed()))
+}
+
+// VectorAt returns the offset from (x, y) to the nearest feature point, in cells.
+func (w *WorleyNoise) VectorAt(x, y float64) (float64, float64) {
+	c := w.cell(x, y, 1)
+	return c.OffsetX, c.OffsetY
+}
+
+// output returns the value of the selected output of c.
+func (w *WorleyNoise) output(c WorleyCell) float64 {
+	switch w.Output {
+	case OutputF2:
+		return c.Distances[1]
+	case OutputF2MinusF1:
+		return c.Distances[1] - c.Distances[0]
+	case OutputCellID:
+		return float64(c.Hash&0xFF) / 255.0
+	case OutputF3:
+		return c.Distances[2]
+	case OutputFN:
+		return c.Distances[w.nth()-1]
+	case OutputOffsetX:
+		return 0.5 + c.OffsetX/2
+	case OutputOffsetY:
+		return 0.5 + c.OffsetY/2
+	case OutputCellHash:
+		return float64(c.Hash>>11) / (1 << 53)
+	}
+	return c.Distances[0]
+}
+
+// nth returns Nth, within [1, worleyMaxN].
+func (w *WorleyNoise) nth() int {
+	switch {
+	case w.Nth < 1:
+		return 1
+	case w.Nth > worleyMaxN:
+		return worleyMaxN
+	}
+	return w.Nth
+}
+
+// points returns the number of feature points in each cell, at least 1.
+func (w *WorleyNoise) points() int {
+	if w.PointsPerCell < 1 {
+		return 1
+	}
+	return w.PointsPerCell
+}
+
+// Cell finds the feature points nearest to (x, y), all worleyMaxN of them.
+func (w *WorleyNoise) Cell(x, y float64) WorleyCell {
+	return w.cell(x, y, worleyMaxN)
+}
+
+// needed returns how many of the nearest distances the output depends on.
+func (w *WorleyNoise) needed() int {
+	switch w.Output {
+	case OutputF2, OutputF2MinusF1:
+		return 2
+	case OutputF3:
+		return 3
+	case OutputFN:
+		return w.nth()
+	}
+	return 1
+}
+
+// cell finds the feature points nearest to (x, y). The first n distances are
+// exact; those after may miss points further out.
+func (w *WorleyNoise) cell(x, y float64, n int) WorleyCell {
 	freq := w.Frequency.Frequency
 	if freq == 0 {
 		freq = 0.05 // Default frequency
 	}
-	nx, ny := float64(x)*freq, float64(y)*freq
+	// With a period the frequency is adjusted to fit whole cells, and the cells
+	// are hashed wrapped so the feature points repeat.
+	cellsX, freqX := periodCells(w.PeriodX, freq)
+	cellsY, freqY := periodCells(w.PeriodY, freq)
+	nx, ny := x*freqX, y*freqY
 	ix, iy := math.Floor(nx), math.Floor(ny)
 	fx, fy := nx-ix, ny-iy
 
-	minDist := math.MaxFloat64
-	secondMinDist := math.MaxFloat64
-	closestHash := uint64(0)
-
-	// Check 3x3 neighbor grids
-	for dy := -1; dy <= 1; dy++ {
-		for dx := -1; dx <= 1; dx++ {
-			neighborX := int(ix) + dx
-			neighborY := int(iy) + dy
-
-			// Hash to find point in neighbor cell
-			h := w.hash(neighborX, neighborY)
-
-			// Extract point position from hash
-			// Use different bits for X and Y to decorrelate
-			rX := float64(h&0xFFFF) / 65535.0
-			rY := float64((h>>16)&0xFFFF) / 65535.0
-
-			pointX := float64(dx) + rX*w.Jitter
-			pointY := float64(dy) + rY*w.Jitter
-
-			// Calculate distance
-			var dist float64
-			diffX := math.Abs(pointX - fx)
-			diffY := math.Abs(pointY - fy)
-
-			switch w.Metric {
-			case MetricManhattan:
-				dist = diffX + diffY
-			case MetricChebyshev:
-				dist = math.Max(diffX, diffY)
-			case MetricEuclidean:
-				fallthrough
-			default:
-				dist = math.Sqrt(diffX*diffX + diffY*diffY)
-			}
+	var c WorleyCell
+	for i := range c.Distances {
+		c.Distances[i] = math.MaxFloat64
+	}
+	// The points of a cell lie within [lo, hi] cells of its corner on each axis,
+	// so a point in ring r of cells around (x, y) is at least r-spread away.
+	lo, hi := math.Min(0, w.Jitter), math.Max(0, w.Jitter)
+	spread := math.Max(1-lo, hi)
+	points := w.points()
 
-			if dist < minDist {
-				secondMinDist = minDist
-				minDist = dist
-				closestHash = h
-			} else if dist < secondMinDist {
-				secondMinDist = dist
+	for r := 0; r <= worleyMaxRing; r++ {
+		// Every metric is at least the Chebyshev distance, so once the nth
+		// distance is within reach of the ring no point in it can be nearer.
+		if r > 0 && c.Distances[n-1] <= float64(r)-spread {
+			return c
+		}
+		for dy := -r; dy <= r; dy++ {
+			step := 2 * r
+			if dy == -r || dy == r || step == 0 {
+				step = 1
+			}
+			for dx := -r; dx <= r; dx += step {
+				w.visit(&c, int(ix)+dx, int(iy)+dy, cellsX, cellsY, points, float64(dx)-fx, float64(dy)-fy)
 			}
 		}
 	}
+	return c
+}
 
-	var val float64
-	switch w.Output {
-	case OutputF1:
-		val = minDist
-	case OutputF2:
-		val = secondMinDist
-	case OutputF2MinusF1:
-		val = secondMinDist - minDist
-	case OutputCellID:
-		// Map hash to grayscale color
-		c := uint8(closestHash & 0xFF)
-		return color.Gray{Y: c}
-	}
+// visit inserts the feature points of cell (cx, cy), wrapped to the period,
+// into c. The cell's corner lies at (ox, oy) from the point searched from.
+func (w *WorleyNoise) visit(c *WorleyCell, cx, cy, cellsX, cellsY, points int, ox, oy float64) {
+	neighborX := wrapCell(cx, cellsX)
+	neighborY := wrapCell(cy, cellsY)
+	for k := 0; k < points; k++ {
+		// Hash to find point in neighbor cell
+		h := w.hash(neighborX, neighborY, k)
 
-	// Clamp and map to grayscale
-	if val < 0 {
-		val = 0
-	}
-	if val > 1 {
-		val = 1
+		// Extract point position from hash
+		// Use different bits for X and Y to decorrelate
+		rX := float64(h&0xFFFF) / 65535.0
+		rY := float64((h>>16)&0xFFFF) / 65535.0
+
+		offsetX := ox + rX*w.Jitter
+		offsetY := oy + rY*w.Jitter
+		dist := w.Metric.Distance(offsetX, offsetY, w.MinkowskiP)
+
+		// Insert the distance in order, keeping the nearest.
+		if dist >= c.Distances[worleyMaxN-1] {
+			continue
+		}
+		i := worleyMaxN - 1
+		for ; i > 0 && dist < c.Distances[i-1]; i-- {
+			c.Distances[i] = c.Distances[i-1]
+		}
+		c.Distances[i] = dist
+		if i == 0 {
+			c.OffsetX, c.OffsetY, c.Hash = offsetX, offsetY, h
+		}
 	}
-	return color.Gray{Y: uint8(val * 255)}
 }
 
-// hash is a stateless hash function based on coordinates and seed.
-func (w *WorleyNoise) hash(x, y int) uint64 {
-	return StableHash(x, y, uint64(w.Seed.Seed))
+// hash is a stateless hash function based on coordinates and seed. The first
+// point of each cell is hashed with the seed alone.
+func (w *WorleyNoise) hash(x, y, k int) uint64 {
+	return StableHash(x, y, uint64(w.Seed.Seed)^uint64(k)*0x9e3779b97f4a7c15)
 }
 
 // NewWorleyNoise creates a new WorleyNoise pattern.
@@ -158,7 +321,7 @@ func SetWorleyOutput(o WorleyOutput) func(any) {
 	}
 }
 
-// SetWorleyJitter sets the jitter amount (0.0 to 1.0).
+// SetWorleyJitter sets the jitter amount (0.0 to 2.0).
 func SetWorleyJitter(j float64) func(any) {
 	return func(i any) {
 		if w, ok := i.(*WorleyNoise); ok {
@@ -166,3 +329,76 @@ func SetWorleyJitter(j float64) func(any) {
 		}
 	}
 }
+
+// SetWorleyPointsPerCell sets the number of feature points in each cell.
+func SetWorleyPointsPerCell(n int) func(any) {
+	return func(i any) {
+		if w, ok := i.(*WorleyNoise); ok {
+			w.PointsPerCell = n
+		}
+	}
+}
+
+// SetWorleyNth sets which nearest feature point OutputFN reports, counting from 1.
+func SetWorleyNth(n int) func(any) {
+	return func(i any) {
+		if w, ok := i.(*WorleyNoise); ok {
+			w.Nth = n
+		}
+	}
+}
+
+// SetWorleyMinkowskiP sets the order of the Minkowski metric.
+func SetWorleyMinkowskiP(p float64) func(any) {
+	return func(i any) {
+		if w, ok := i.(*WorleyNoise); ok {
+			w.MinkowskiP = p
+		}
+	}
+}
+
+// Names of the DistanceMetric and WorleyOutput values in the pattern registry.
+var (
+	distanceMetricNames = []string{"euclidean", "manhattan", "chebyshev", "minkowski", "hex"}
+	worleyOutputNames   = []string{"f1", "f2", "f2-f1", "cell-id", "f3", "fn", "offset-x", "offset-y", "cell-hash", "vector"}
+)
+
+func init() {
+	RegisterPattern(&PatternType{
+		Name:     "worley_noise",
+		Category: CategoryGenerator,
+		Params: []Param{
+			{Name: "jitter", Type: ParamFloat, Default: 1.0, Min: 0, Max: 2, Doc: "How far feature points stray from their cell corner, in cells."},
+			{Name: "metric", Type: ParamEnum, Default: "euclidean", Values: distanceMetricNames, Doc: "Distance metric."},
+			{Name: "output", Type: ParamEnum, Default: "f1", Values: worleyOutputNames, Doc: "Value to output."},
+			{Name: "points_per_cell", Type: ParamInt, Default: 1, Min: 1, Max: 16, Doc: "Feature points in each cell."},
+			{Name: "nth", Type: ParamInt, Default: 1, Min: 1, Max: worleyMaxN, Doc: "Nearest feature point the fn output reports."},
+			{Name: "minkowski_p", Type: ParamFloat, Default: 3.0, Doc: "Order of the minkowski metric."},
+		},
+		Sample: &WorleyNoise{},
+		New: func(a *Args) (image.Image, error) {
+			p := NewWorleyNoise().(*WorleyNoise)
+			p.Jitter = a.Float("jitter")
+			p.Metric = DistanceMetric(a.Enum("metric", distanceMetricNames))
+			p.Output = WorleyOutput(a.Enum("output", worleyOutputNames))
+			p.PointsPerCell = a.Int("points_per_cell")
+			p.Nth = a.Int("nth")
+			p.MinkowskiP = a.Float("minkowski_p")
+			return applyOps(p, a.Options), nil
+		},
+		Encode: func(img image.Image, a *Args) error {
+			p := img.(*WorleyNoise)
+			a.Set("jitter", p.Jitter)
+			a.Set("metric", enumName(distanceMetricNames, int(p.Metric)))
+			a.Set("output", enumName(worleyOutputNames, int(p.Output)))
+			a.Set("points_per_cell", p.points())
+			a.Set("nth", p.nth())
+			minkowskiP := p.MinkowskiP
+			if minkowskiP <= 0 {
+				minkowskiP = 3
+			}
+			a.Set("minkowski_p", minkowskiP)
+			return nil
+		},
+	})
+}
@@ -62,6 +62,15 @@ func GenerateWorleyNoiseReferences() (map[string]func(image.Rectangle) image.Ima
 	add("F2_Chebyshev", SetWorleyMetric(MetricChebyshev), SetWorleyOutput(OutputF2))
 	add("F2MinusF1_Chebyshev", SetWorleyMetric(MetricChebyshev), SetWorleyOutput(OutputF2MinusF1))
 
+	add("F1_Minkowski", SetWorleyMetric(MetricMinkowski), SetWorleyMinkowskiP(0.7), SetWorleyOutput(OutputF1))
+	add("F1_Hex", SetWorleyMetric(MetricHex), SetWorleyOutput(OutputF1))
+
+	add("F3_Euclidean", SetWorleyOutput(OutputF3))
+	add("F4_ThreePointsPerCell", SetWorleyPointsPerCell(3), SetWorleyNth(4), SetWorleyOutput(OutputFN))
+	add("F1_Jitter2", SetWorleyJitter(2), SetWorleyOutput(OutputF1))
+	add("CellHash", SetWorleyOutput(OutputCellHash))
+	add("Vector", SetWorleyOutput(OutputVector))
+
 	return refs, keys
 }
 
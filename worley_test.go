@@ -0,0 +1,180 @@
+package pattern
+
+import (
+	"bytes"
+	"crypto/md5"
+	"fmt"
+	"image"
+	"image/draw"
+	"math"
+	"sort"
+	"testing"
+)
+
+func TestWorleyNoiseGolden(t *testing.T) {
+	// The default single point per cell must draw as it always has.
+	tests := map[string][]func(any){
+		"f1":        {SetWorleyOutput(OutputF1)},
+		"f2-f1":     {SetWorleyOutput(OutputF2MinusF1)},
+		"cell-id":   {SetWorleyOutput(OutputCellID)},
+		"manhattan": {SetWorleyMetric(MetricManhattan)},
+	}
+	want := map[string]string{
+		"f1":        "a03dc04c71fb505d00879dcae2c023a4",
+		"f2-f1":     "14543a90cfdcfb0c8a3b3b718d0e41e8",
+		"cell-id":   "0c3784e4076b2743c6ec8682ed21eec0",
+		"manhattan": "4602a34f2fd3ac2dbad9a1dcb5ce696b",
+	}
+	for name, ops := range tests {
+		g := image.NewGray(image.Rect(0, 0, 128, 128))
+		draw.Draw(g, g.Bounds(), NewWorleyNoise(append([]func(any){SetSeed(3)}, ops...)...), image.Point{}, draw.Src)
+		if got := fmt.Sprintf("%x", md5.Sum(g.Pix)); got != want[name] {
+			t.Errorf("%s: md5 = %s, want %s", name, got, want[name])
+		}
+	}
+}
+
+func TestWorleyNoiseDistances(t *testing.T) {
+	w := NewWorleyNoise(SetSeed(4), SetWorleyPointsPerCell(3)).(*WorleyNoise)
+	for y := 0.0; y < 100; y += 7 {
+		for x := 0.0; x < 100; x += 3 {
+			c := w.Cell(x, y)
+			for i := 1; i < len(c.Distances); i++ {
+				if c.Distances[i] < c.Distances[i-1] {
+					t.Fatalf("(%v, %v): distances %v are not sorted", x, y, c.Distances)
+				}
+			}
+			if d := math.Hypot(c.OffsetX, c.OffsetY); math.Abs(d-c.Distances[0]) > 1e-12 {
+				t.Fatalf("(%v, %v): offset length %v, want F1 %v", x, y, d, c.Distances[0])
+			}
+			w.Output, w.Nth = OutputFN, 3
+			if got, want := w.ValueAt(x, y), c.Distances[2]; got != want {
+				t.Fatalf("(%v, %v): F3 = %v, want %v", x, y, got, want)
+			}
+			w.Output = OutputF1
+		}
+	}
+}
+
+func TestWorleyNoiseJitter(t *testing.T) {
+	// A search of every cell within reach must agree with the 5x5 search.
+	w := NewWorleyNoise(SetSeed(6), SetWorleyJitter(2), SetFrequency(0.1)).(*WorleyNoise)
+	for y := 0.0; y < 60; y += 1.5 {
+		for x := 0.0; x < 60; x += 2.5 {
+			nx, ny := x*0.1, y*0.1
+			want := math.MaxFloat64
+			for cy := int(math.Floor(ny)) - 4; cy <= int(math.Floor(ny))+4; cy++ {
+				for cx := int(math.Floor(nx)) - 4; cx <= int(math.Floor(nx))+4; cx++ {
+					h := w.hash(cx, cy, 0)
+					px := float64(cx) + float64(h&0xFFFF)/65535.0*2
+					py := float64(cy) + float64((h>>16)&0xFFFF)/65535.0*2
+					want = math.Min(want, math.Hypot(px-nx, py-ny))
+				}
+			}
+			if got := w.ValueAt(x, y); math.Abs(got-want) > 1e-9 {
+				t.Fatalf("F1 at (%v, %v) = %v, want %v", x, y, got, want)
+			}
+		}
+	}
+}
+
+func TestWorleyNoiseBruteForce(t *testing.T) {
+	// The ring search must agree with a search of every cell within reach.
+	tests := []struct {
+		output WorleyOutput
+		nth    int
+		n      int
+	}{
+		{OutputF2, 0, 2},
+		{OutputF3, 0, 3},
+		{OutputFN, 4, 4},
+		{OutputFN, 10, 10},
+	}
+	w := NewWorleyNoise(SetSeed(3)).(*WorleyNoise)
+	for _, tt := range tests {
+		w.Output, w.Nth = tt.output, tt.nth
+		for y := 0.0; y < 200; y += 3.5 {
+			for x := 0.0; x < 200; x += 2.5 {
+				nx, ny := x*0.05, y*0.05
+				var dists []float64
+				for cy := int(math.Floor(ny)) - 4; cy <= int(math.Floor(ny))+4; cy++ {
+					for cx := int(math.Floor(nx)) - 4; cx <= int(math.Floor(nx))+4; cx++ {
+						h := w.hash(cx, cy, 0)
+						px := float64(cx) + float64(h&0xFFFF)/65535.0
+						py := float64(cy) + float64((h>>16)&0xFFFF)/65535.0
+						dists = append(dists, math.Hypot(px-nx, py-ny))
+					}
+				}
+				sort.Float64s(dists)
+				if got, want := w.ValueAt(x, y), dists[tt.n-1]; math.Abs(got-want) > 1e-9 {
+					t.Fatalf("F%d at (%v, %v) = %v, want %v", tt.n, x, y, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestDistanceMetric(t *testing.T) {
+	tests := []struct {
+		m      DistanceMetric
+		dx, dy float64
+		p      float64
+		want   float64
+	}{
+		{MetricEuclidean, 3, -4, 0, 5},
+		{MetricManhattan, 3, -4, 0, 7},
+		{MetricChebyshev, 3, -4, 0, 4},
+		{MetricMinkowski, 3, -4, 1, 7},
+		{MetricMinkowski, 3, -4, 2, 5},
+		{MetricMinkowski, 1, 1, 0, math.Cbrt(2)},
+		{MetricHex, 1, 0, 0, 1},
+		{MetricHex, 0.5, math.Sqrt(3) / 2, 0, 1},
+		{MetricHex, 0, math.Sqrt(3) / 2, 0, 1},
+	}
+	for _, tt := range tests {
+		if got := tt.m.Distance(tt.dx, tt.dy, tt.p); math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("%s.Distance(%v, %v, %v) = %v, want %v", distanceMetricNames[tt.m], tt.dx, tt.dy, tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestWorleyNoiseVector(t *testing.T) {
+	w := NewWorleyNoise(SetSeed(2), SetWorleyOutput(OutputVector)).(*WorleyNoise)
+	field := VectorFieldOf(w)
+	if field != VectorField(w) {
+		t.Fatal("WorleyNoise is not its own vector field")
+	}
+	for _, p := range [][2]int{{0, 0}, {17, 40}, {90, 3}} {
+		c := w.Cell(float64(p[0]), float64(p[1]))
+		// Following the offset leads to the feature point, where F1 is 0.
+		x := float64(p[0]) + c.OffsetX/0.05
+		y := float64(p[1]) + c.OffsetY/0.05
+		w.Output = OutputF1
+		if f1 := w.ValueAt(x, y); f1 > 1e-9 {
+			t.Errorf("F1 at the feature point from %v = %v, want 0", p, f1)
+		}
+		w.Output = OutputVector
+		r, _, b, _ := w.At(p[0], p[1]).RGBA()
+		if want := uint32(clamp01(0.5+c.OffsetX/2)*255) * 0x101; r != want {
+			t.Errorf("red at %v = %d, want %d", p, r, want)
+		}
+		if want := uint32(c.Hash&0xFF) * 0x101; b != want {
+			t.Errorf("blue at %v = %d, want %d", p, b, want)
+		}
+	}
+}
+
+func TestWorleyNoiseGraph(t *testing.T) {
+	img := NewWorleyNoise(SetBounds(image.Rect(0, 0, 40, 30)), SetSeed(8),
+		SetWorleyMetric(MetricMinkowski), SetWorleyMinkowskiP(1.5),
+		SetWorleyPointsPerCell(2), SetWorleyNth(3), SetWorleyOutput(OutputFN), SetWorleyJitter(1.5))
+	var buf bytes.Buffer
+	if err := SaveGraph(&buf, img); err != nil {
+		t.Fatalf("SaveGraph failed: %v", err)
+	}
+	loaded, err := LoadGraph(&buf)
+	if err != nil {
+		t.Fatalf("LoadGraph failed: %v", err)
+	}
+	sameImage(t, img, loaded)
+}
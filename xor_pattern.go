@@ -39,3 +39,7 @@ func NewXorPattern(ops ...func(any)) image.Image {
 	}
 	return p
 }
+
+func init() {
+	RegisterPattern(generatorType("xor_pattern", &XorPattern{}, NewXorPattern))
+}